@@ -0,0 +1,93 @@
+// Copyright 2025 The Heimdall authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/abc-inc/heimdall/res"
+)
+
+var ctx = context.Background()
+var cancel context.CancelFunc = func() {}
+
+// nested is the number of commands executed within the current command.
+var nested int
+
+// BaseContext returns the parent of the contexts created by InitContext.
+// It can be replaced, e.g., by a server, which runs commands per request.
+var BaseContext = context.Background
+
+func init() {
+	res.Context = Context
+}
+
+// Context returns the context of the current command, which is canceled on
+// timeout or interrupt (see InitContext).
+func Context() context.Context {
+	return ctx
+}
+
+// InitContext sets up the context of the current command. It is canceled after
+// the timeout (unless it is zero), or when an interrupt signal is received.
+// A second interrupt terminates the program immediately.
+func InitContext(timeout time.Duration) context.Context {
+	c, stop := signal.NotifyContext(BaseContext(), os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-c.Done()
+		stop()
+	}()
+
+	cancel = stop
+	if timeout > 0 {
+		var cancelTimeout context.CancelFunc
+		c, cancelTimeout = context.WithTimeout(c, timeout)
+		cancel = func() { cancelTimeout(); stop() }
+	}
+	ctx = c
+	return ctx
+}
+
+// CancelContext releases the resources of the context of the current command.
+func CancelContext() {
+	cancel()
+}
+
+// Nested reports whether the current command is executed within another
+// command (see RunNested). Process-wide state like the evidence recorder belongs
+// to the outermost command.
+func Nested() bool {
+	return nested > 0
+}
+
+// RunNested calls fn, which executes a command within the current command,
+// e.g., a task runner. The context of the current command is restored
+// afterward, even if the nested command failed.
+func RunNested(fn func()) {
+	c, cf := ctx, cancel
+	nested++
+	defer func() {
+		nested--
+		if ctx != c {
+			cancel()
+		}
+		ctx, cancel = c, cf
+	}()
+	fn()
+}
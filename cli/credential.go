@@ -0,0 +1,137 @@
+// Copyright 2025 The Heimdall authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"bytes"
+	"cmp"
+	"errors"
+	"maps"
+	"os"
+	"os/exec"
+	"slices"
+	"strings"
+
+	"github.com/abc-inc/heimdall/errs"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/zalando/go-keyring"
+)
+
+// KeyringService is the service of all entries in the system keyring, which
+// are managed by Heimdall.
+const KeyringService = "heimdall"
+
+// DefaultProfile is the name of the profile in keyring entries, if no profile
+// is active.
+const DefaultProfile = "default"
+
+// tokenSource holds the value of the --token flag and the names of the
+// environment variables, which may contain the token of a service.
+type tokenSource struct {
+	flag *string
+	envs []string
+}
+
+// tokenSources holds the token sources by service.
+var tokenSources = map[string]tokenSource{}
+
+// AddTokenFlag adds the persistent --token flag to the command of a service
+// and registers the service for resolving its token with Token.
+func AddTokenFlag(cmd *cobra.Command, service string, envs ...string) {
+	tokenSources[service] = tokenSource{
+		flag: cmd.PersistentFlags().String("token", "",
+			"Token for authentication (prefer the keyring to avoid exposing it, see 'heimdall help:credentials')"),
+		envs: envs,
+	}
+}
+
+// Services returns the names of the services registered by AddTokenFlag.
+func Services() []string {
+	return slices.Sorted(maps.Keys(tokenSources))
+}
+
+// TokenFlag returns the value of the --token flag of a service, which is empty
+// unless it was set on the command line.
+func TokenFlag(service string) string {
+	if f := tokenSources[service].flag; f != nil {
+		return *f
+	}
+	return ""
+}
+
+// KeyringUser returns the user of the keyring entry holding the token of a
+// service in the active profile, i.e., "<profile>/<service>".
+func KeyringUser(service string) string {
+	return cmp.Or(profile, DefaultProfile) + "/" + service
+}
+
+// Token returns the token of a service from the first source providing one:
+//
+//  1. the --token flag of the service command,
+//  2. the environment variable named by "token-env" or the file named by
+//     "token-file" in the active profile,
+//  3. the first non-empty environment variable registered by AddTokenFlag,
+//  4. the system keyring (service "heimdall", user "<profile>/<service>"),
+//  5. the output of the "credential-helper" command of the active profile or
+//     the config file.
+func Token(service string) string {
+	src := tokenSources[service]
+	if src.flag != nil && *src.flag != "" {
+		return *src.flag
+	}
+	envs := src.envs
+	if e := ProfileString(service, "token-env"); e != "" {
+		envs = append([]string{e}, envs...)
+	} else if f := ProfileString(service, "token-file"); f != "" {
+		b, err := os.ReadFile(os.ExpandEnv(f))
+		if err != nil {
+			errs.Abort(errs.New(errs.Auth, err))
+		}
+		return strings.TrimSpace(string(b))
+	}
+	if t := firstEnv(envs...); t != "" {
+		return t
+	}
+
+	t, err := Keyring().Get(KeyringService, KeyringUser(service))
+	if err == nil {
+		return t
+	} else if !errors.Is(err, keyring.ErrNotFound) {
+		log.Debug().Err(err).Str("user", KeyringUser(service)).Msg("Cannot read token from keyring")
+	}
+
+	if h := cmp.Or(ProfileString(service, "credential-helper"), viper.GetString("credential-helper")); h != "" {
+		return credentialHelper(h, service)
+	}
+	return ""
+}
+
+// credentialHelper runs the command and returns its output as token.
+// The command is not run by a shell, but it gets the service and the profile
+// as environment variables HEIMDALL_SERVICE and HEIMDALL_PROFILE.
+func credentialHelper(command, service string) string {
+	args := strings.Fields(command)
+	cmd := exec.CommandContext(Context(), args[0], args[1:]...)
+	cmd.Env = append(os.Environ(), "HEIMDALL_SERVICE="+service, "HEIMDALL_PROFILE="+cmp.Or(profile, DefaultProfile))
+	cmd.Stderr = os.Stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		errs.Abort(errs.Newf(errs.Auth, "credential helper '%s' failed: %w", args[0], err))
+	}
+	return string(bytes.TrimSpace(out))
+}
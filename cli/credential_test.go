@@ -0,0 +1,50 @@
+// Copyright 2025 The Heimdall authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+	"github.com/zalando/go-keyring"
+)
+
+func TestToken(t *testing.T) {
+	keyring.MockInit()
+	kr = sync.OnceValue(selectKeyring)
+	defer func() { kr = sync.OnceValue(selectKeyring) }()
+	cmd := &cobra.Command{}
+	AddTokenFlag(cmd, "test", "TEST_TOKEN")
+	defer delete(tokenSources, "test")
+	defer viper.Reset()
+
+	require.Empty(t, Token("test"))
+
+	viper.Set("credential-helper", "echo helper-token")
+	require.Equal(t, "helper-token", Token("test"))
+
+	require.NoError(t, keyring.Set(KeyringService, "default/test", "keyring-token"))
+	require.Equal(t, "keyring-token", Token("test"))
+
+	t.Setenv("TEST_TOKEN", "env-token")
+	require.Equal(t, "env-token", Token("test"))
+
+	require.NoError(t, cmd.PersistentFlags().Set("token", "flag-token"))
+	require.Equal(t, "flag-token", Token("test"))
+	require.Equal(t, []string{"test"}, Services())
+}
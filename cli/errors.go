@@ -0,0 +1,56 @@
+// Copyright 2025 The Heimdall authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"errors"
+
+	"github.com/abc-inc/heimdall/errs"
+	"github.com/spf13/cobra"
+)
+
+// HandleErrors replaces PersistentPreRun, PreRun, Run and PersistentPostRun by
+// their error-returning variants for the command and all of its subcommands,
+// so that errors raised by internal.Must or errs.Abort
+// are returned by the command instead of terminating the program.
+// Returned errors are classified by errs.KindOf, unless they have a kind.
+func HandleErrors(cmd *cobra.Command) {
+	cmd.PersistentPreRun, cmd.PersistentPreRunE = nil, withErrors(cmd.PersistentPreRun, cmd.PersistentPreRunE)
+	cmd.PreRun, cmd.PreRunE = nil, withErrors(cmd.PreRun, cmd.PreRunE)
+	cmd.Run, cmd.RunE = nil, withErrors(cmd.Run, cmd.RunE)
+	cmd.PersistentPostRun, cmd.PersistentPostRunE = nil, withErrors(cmd.PersistentPostRun, cmd.PersistentPostRunE)
+	for _, c := range cmd.Commands() {
+		HandleErrors(c)
+	}
+}
+
+func withErrors(run func(*cobra.Command, []string), runE func(*cobra.Command, []string) error) func(*cobra.Command, []string) error {
+	if run == nil && runE == nil {
+		return nil
+	}
+	return func(cmd *cobra.Command, args []string) (err error) {
+		defer func() {
+			if err != nil && !errors.As(err, new(*errs.Error)) {
+				err = errs.New(errs.KindOf(err), err)
+			}
+		}()
+		defer errs.Recover(&err)
+		if runE != nil {
+			return runE(cmd, args)
+		}
+		run(cmd, args)
+		return nil
+	}
+}
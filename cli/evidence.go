@@ -0,0 +1,247 @@
+// Copyright 2025 The Heimdall authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/abc-inc/heimdall/res"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// Files contained in an evidence bundle.
+const (
+	BundleCommandFile  = "command.json"
+	BundleOutputFile   = "output"
+	BundleManifestFile = "manifest.sha256"
+)
+
+// Bundle describes a single invocation of Heimdall.
+type Bundle struct {
+	Command   []string    `json:"command" yaml:"command"`
+	WorkDir   string      `json:"work_dir" yaml:"work_dir"`
+	Version   string      `json:"version" yaml:"version"`
+	Timestamp time.Time   `json:"timestamp" yaml:"timestamp"`
+	Duration  string      `json:"duration" yaml:"duration"`
+	ExitCode  int         `json:"exit_code" yaml:"exit_code"`
+	Inputs    []InputFile `json:"inputs" yaml:"inputs"`
+}
+
+// InputFile is a file or URL, which was read during the invocation.
+type InputFile struct {
+	URI    string `json:"uri" yaml:"uri"`
+	SHA256 string `json:"sha256" yaml:"sha256"`
+	Size   int64  `json:"size" yaml:"size"`
+}
+
+var secretFlag = regexp.MustCompile(`(?i)token|passw|secret|key|auth|credential|cookie`)
+
+var recorder *evidence
+
+type evidence struct {
+	mu     sync.Mutex
+	dir    string
+	name   string
+	bundle Bundle
+	inputs []*inputRecorder
+	out    bytes.Buffer
+	stdout fileWriter
+}
+
+// RecordEvidence starts recording the inputs and the output of the current
+// invocation of cmd with the given command line. The bundle is written to a new
+// subdirectory of dir, when WriteEvidence is called.
+func RecordEvidence(dir string, cmd *cobra.Command, args []string) {
+	wd, _ := os.Getwd()
+	e := &evidence{
+		dir:    dir,
+		name:   strings.ReplaceAll(cmd.CommandPath(), " ", "-"),
+		bundle: Bundle{Command: Redact(cmd.Flags(), args), WorkDir: wd, Version: Version, Timestamp: time.Now().UTC()},
+		stdout: IO.Out,
+	}
+	IO.Out = &fdWriter{fd: e.stdout.Fd(), Writer: io.MultiWriter(e.stdout, &e.out)}
+	writer = nil
+
+	res.OpenHook = func(uri string, r io.ReadCloser) io.ReadCloser {
+		ir := &inputRecorder{ReadCloser: r, uri: redactURL(uri), h: sha256.New()}
+		e.mu.Lock()
+		defer e.mu.Unlock()
+		e.inputs = append(e.inputs, ir)
+		return ir
+	}
+	recorder = e
+}
+
+// WriteEvidence writes the evidence bundle, if recording was started.
+// It returns the directory of the bundle.
+func WriteEvidence(exitCode int) (string, error) {
+	e := recorder
+	if e == nil {
+		return "", nil
+	}
+	recorder, res.OpenHook, IO.Out, writer = nil, nil, e.stdout, nil
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	b := e.bundle
+	b.Duration = time.Since(b.Timestamp).Round(time.Millisecond).String()
+	b.ExitCode = exitCode
+	b.Inputs = []InputFile{}
+	for _, ir := range e.inputs {
+		in := ir.digest()
+		if !slices.Contains(b.Inputs, in) {
+			b.Inputs = append(b.Inputs, in)
+		}
+	}
+
+	dir := filepath.Join(e.dir, b.Timestamp.Format("20060102T150405.000Z")+"-"+e.name)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", err
+	}
+
+	cmd, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	files := map[string][]byte{BundleCommandFile: append(cmd, '\n'), BundleOutputFile: e.out.Bytes()}
+
+	manifest := &strings.Builder{}
+	for _, n := range []string{BundleCommandFile, BundleOutputFile} {
+		if err = os.WriteFile(filepath.Join(dir, n), files[n], 0o644); err != nil {
+			return "", err
+		}
+		sum := sha256.Sum256(files[n])
+		_, _ = fmt.Fprintf(manifest, "%s  %s\n", hex.EncodeToString(sum[:]), n)
+	}
+	return dir, os.WriteFile(filepath.Join(dir, BundleManifestFile), []byte(manifest.String()), 0o644)
+}
+
+// Exit writes the evidence bundle, if recording was started, and terminates
+// the program with the given status code.
+func Exit(code int) {
+	if _, err := WriteEvidence(code); err != nil {
+		_, _ = fmt.Fprintln(IO.ErrOut, "Cannot write evidence:", err)
+	}
+	os.Exit(code)
+}
+
+// Redact replaces the values of flags, which may contain secrets (e.g.,
+// --token), and passwords in URLs with "***".
+func Redact(fs *pflag.FlagSet, args []string) []string {
+	red := make([]string, len(args))
+	for i := 0; i < len(args); i++ {
+		a := args[i]
+		red[i] = redactURL(a)
+
+		var f *pflag.Flag
+		name, val, hasVal := "", "", false
+		if strings.HasPrefix(a, "--") {
+			name, val, hasVal = strings.Cut(a[2:], "=")
+			f = fs.Lookup(name)
+		} else if strings.HasPrefix(a, "-") && len(a) > 1 {
+			name, val = a[1:2], a[2:]
+			hasVal = val != ""
+			f = fs.ShorthandLookup(name)
+		}
+		if f == nil || !secretFlag.MatchString(f.Name) || f.Value.Type() == "bool" {
+			continue
+		}
+
+		if hasVal {
+			red[i] = strings.TrimSuffix(a, val) + "***"
+		} else if i+1 < len(args) {
+			i++
+			red[i] = "***"
+		}
+	}
+	return red
+}
+
+// redactURL replaces the password and the values of query parameters, which
+// may contain secrets (e.g., access_token), in a URL with "xxxxx".
+func redactURL(s string) string {
+	if !strings.Contains(s, "://") {
+		return s
+	}
+	u, err := url.Parse(s)
+	if err != nil {
+		return s
+	}
+	q, red := u.Query(), false
+	for k := range q {
+		if secretFlag.MatchString(k) {
+			q[k], red = []string{"xxxxx"}, true
+		}
+	}
+	if red {
+		u.RawQuery = q.Encode()
+	} else if u.User == nil {
+		return s
+	}
+	return u.Redacted()
+}
+
+// inputRecorder computes the digest of a resource while it is read.
+type inputRecorder struct {
+	io.ReadCloser
+	uri    string
+	h      hash.Hash
+	n      int64
+	closed bool
+}
+
+func (r *inputRecorder) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	r.h.Write(p[:n])
+	r.n += int64(n)
+	return n, err
+}
+
+func (r *inputRecorder) Close() error {
+	r.drain()
+	r.closed = true
+	return r.ReadCloser.Close()
+}
+
+// drain reads the remaining content, so that the digest covers the whole
+// resource even if it was only read partially.
+func (r *inputRecorder) drain() {
+	if !r.closed && r.uri != "-" {
+		n, _ := io.Copy(r.h, r.ReadCloser)
+		r.n += n
+	}
+}
+
+func (r *inputRecorder) digest() InputFile {
+	r.drain()
+	r.closed = true
+	return InputFile{URI: r.uri, SHA256: hex.EncodeToString(r.h.Sum(nil)), Size: r.n}
+}
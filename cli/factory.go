@@ -17,15 +17,16 @@ package cli
 import (
 	"fmt"
 	"io"
-	"os"
+	"maps"
 	"strings"
+	"sync"
 	"text/template"
 
 	"github.com/Masterminds/sprig/v3"
 	"github.com/abc-inc/gutenfmt/gfmt"
+	"github.com/abc-inc/heimdall/errs"
 	"github.com/abc-inc/heimdall/internal"
 	"github.com/abc-inc/heimdall/res"
-	"github.com/rs/zerolog/log"
 	"github.com/spf13/pflag"
 )
 
@@ -33,30 +34,55 @@ var Version string
 
 var IO *IOStreams
 var writer gfmt.Writer
-var options = map[string]string{
+var options = maps.Clone(defaultOptions)
+var defaultOptions = map[string]string{
 	"output": "json",
 	"pretty": "false",
 	"query":  "",
 	"jq":     "",
 }
 
+// captureMu serializes Capture, because the output state is global.
+var captureMu sync.Mutex
+
 func init() {
 	IO = System()
 }
 
+// Capture runs f with the output redirected to buffers, and returns what was
+// written to the standard output and the error output. The output options are
+// reset before and restored after f, so that every call starts from a clean
+// state. Concurrent calls are serialized.
+func Capture(f func()) (out, errOut []byte) {
+	captureMu.Lock()
+	defer captureMu.Unlock()
+
+	prevIO, prevOpts, prevWriter := IO, options, writer
+	defer func() { IO, options, writer = prevIO, prevOpts, prevWriter }()
+
+	s, _, o, e := Test()
+	IO, options, writer = s, maps.Clone(defaultOptions), nil
+	f()
+	return o.Bytes(), e.Bytes()
+}
+
 func Fmt(a any) {
 	if _, err := getWriter().Write(a); err != nil {
-		defer func() {
-			// Sometimes it is not possible to log an error by gojq (see gojq.TypeOf).
-			// In this case, the message is logged without error.
-			if r := recover(); r != nil {
-				log.Fatal().Any("output", a).Type("type", a).Msg("Cannot write output")
-			}
-		}()
-		log.Fatal().AnErr(ErrKey, err).Msg("Cannot write output")
+		errs.Abort(outputError(a, err))
 	}
 }
 
+func outputError(a any, err error) (outErr error) {
+	defer func() {
+		// Sometimes it is not possible to get the message of an error by gojq
+		// (see gojq.TypeOf). In this case, the type of the output is reported.
+		if r := recover(); r != nil {
+			outErr = fmt.Errorf("cannot write output of type %T", a)
+		}
+	}()
+	return fmt.Errorf("cannot write output: %s", err.Error())
+}
+
 func Fmtln(a any) {
 	Fmt(a)
 	_ = internal.Must(Msg("\n"))
@@ -118,6 +144,10 @@ func getWriter() gfmt.Writer {
 		} else {
 			writer = gfmt.NewJSON(IO.Out)
 		}
+	case "junit":
+		writer = newJUnit(IO.Out, options["pretty"] == "true")
+	case "sarif":
+		writer = newSARIF(IO.Out, options["pretty"] == "true")
 	case "table":
 		writer = gfmt.NewTab(IO.Out)
 	case "template":
@@ -142,8 +172,7 @@ func getWriter() gfmt.Writer {
 			writer = gfmt.NewYAML(IO.Out)
 		}
 	default:
-		log.Fatal().Str("output", options["output"]).Msg("Invalid output format")
-		os.Exit(1)
+		errs.Abortf(errs.Usage, "invalid output format: %s", options["output"])
 	}
 
 	if q := options["query"]; q != "" {
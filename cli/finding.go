@@ -0,0 +1,211 @@
+// Copyright 2025 The Heimdall authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Finding is the result of a check, which refers to a rule and optionally to
+// locations in files.
+type Finding struct {
+	RuleID    string     `json:"rule_id" yaml:"rule_id"`
+	Level     string     `json:"level" yaml:"level"`
+	Message   string     `json:"message" yaml:"message"`
+	Evidence  string     `json:"evidence,omitempty" yaml:"evidence,omitempty"`
+	Locations []Location `json:"locations,omitempty" yaml:"locations,omitempty"`
+}
+
+// Location refers to a file and optionally to a range of lines in it.
+type Location struct {
+	File      string `json:"file" yaml:"file"`
+	StartLine int    `json:"start_line,omitempty" yaml:"start_line,omitempty"`
+	EndLine   int    `json:"end_line,omitempty" yaml:"end_line,omitempty"`
+}
+
+// FindingsConverter is implemented by values, which convert themselves to
+// findings, e.g., to refer to the file they were read from.
+type FindingsConverter interface {
+	Findings() []Finding
+}
+
+// Finding levels as defined by SARIF.
+const (
+	LevelError   = "error"
+	LevelWarning = "warning"
+	LevelNote    = "note"
+	LevelNone    = "none"
+)
+
+var (
+	ruleKeys    = []string{"rule_id", "ruleId", "rule.id", "id", "cmd", "name"}
+	statusKeys  = []string{"status", "state"}
+	levelKeys   = []string{"level", "rule.severity", "rule_severity", "severity", "rule.security_severity_level"}
+	messageKeys = []string{"message.text", "message", "most_recent_instance.message.text",
+		"rule.description", "description", "line", "value"}
+	evidenceKeys  = []string{"evidence", "value"}
+	fileKeys      = []string{"file", "path", "location.path", "most_recent_instance.location.path", "inputs"}
+	startLineKeys = []string{"start_line", "location.start_line", "most_recent_instance.location.start_line"}
+	endLineKeys   = []string{"end_line", "location.end_line", "most_recent_instance.location.end_line"}
+)
+
+// ToFindings converts finding-shaped values to findings.
+// Apart from findings, it accepts check results, Dockerfile instructions,
+// code scanning alerts, values implementing FindingsConverter and arbitrary
+// objects with similar properties.
+// Lists are converted element-wise and objects containing a "results" list
+// (e.g., a check report) are unwrapped.
+func ToFindings(a any) ([]Finding, error) {
+	switch v := a.(type) {
+	case []Finding:
+		return v, nil
+	case Finding:
+		return []Finding{v}, nil
+	case FindingsConverter:
+		return v.Findings(), nil
+	case string:
+		return []Finding{{RuleID: "heimdall", Level: LevelNote, Message: v}}, nil
+	}
+
+	b, err := json.Marshal(a)
+	if err != nil {
+		return nil, err
+	}
+	var doc any
+	if err = json.Unmarshal(b, &doc); err != nil {
+		return nil, err
+	}
+
+	if m, ok := doc.(map[string]any); ok {
+		if rs, ok := m["results"].([]any); ok {
+			doc = rs
+		}
+	}
+
+	var es []any
+	switch v := doc.(type) {
+	case []any:
+		es = v
+	case nil:
+		return nil, nil
+	default:
+		es = []any{v}
+	}
+
+	fs := make([]Finding, 0, len(es))
+	for i, e := range es {
+		m, ok := e.(map[string]any)
+		if !ok {
+			fs = append(fs, Finding{RuleID: "heimdall", Level: LevelNote, Message: fmt.Sprint(e)})
+			continue
+		}
+		f := Finding{
+			RuleID:    str(lookup(m, ruleKeys...)),
+			Level:     findingLevel(m),
+			Message:   str(lookup(m, messageKeys...)),
+			Evidence:  str(lookup(m, evidenceKeys...)),
+			Locations: findingLocations(m),
+		}
+		if f.RuleID == "" {
+			f.RuleID = fmt.Sprintf("heimdall-%d", i+1)
+		}
+		fs = append(fs, f)
+	}
+	return fs, nil
+}
+
+func findingLevel(m map[string]any) string {
+	switch strings.ToLower(str(lookup(m, statusKeys...))) {
+	case "pass", "passed", "success", "ok", "fixed", "dismissed", "closed", "resolved":
+		return LevelNone
+	case "error":
+		return LevelError
+	}
+
+	switch strings.ToLower(str(lookup(m, levelKeys...))) {
+	case "error", "critical", "high", "fail", "failure":
+		return LevelError
+	case "warning", "warn", "medium", "moderate":
+		return LevelWarning
+	case "note", "info", "low":
+		return LevelNote
+	case "none":
+		return LevelNone
+	}
+	return LevelWarning
+}
+
+func findingLocations(m map[string]any) (ls []Location) {
+	if es, ok := m["locations"].([]any); ok {
+		for _, e := range es {
+			if l, ok := e.(map[string]any); ok {
+				ls = append(ls, findingLocations(l)...)
+			}
+		}
+		return ls
+	}
+
+	start, end := num(lookup(m, startLineKeys...)), num(lookup(m, endLineKeys...))
+	switch f := lookup(m, fileKeys...).(type) {
+	case string:
+		ls = append(ls, Location{File: f, StartLine: start, EndLine: end})
+	case []any:
+		for _, e := range f {
+			if s, ok := e.(string); ok && s != "" {
+				ls = append(ls, Location{File: s})
+			}
+		}
+	}
+	return ls
+}
+
+// lookup returns the first non-empty value of the given dot-separated paths.
+func lookup(m map[string]any, paths ...string) any {
+	for _, p := range paths {
+		var v any = m
+		for _, k := range strings.Split(p, ".") {
+			if o, ok := v.(map[string]any); ok {
+				v = o[k]
+			} else {
+				v = nil
+				break
+			}
+		}
+		if v != nil && v != "" {
+			return v
+		}
+	}
+	return nil
+}
+
+func str(a any) string {
+	switch v := a.(type) {
+	case nil, map[string]any, []any:
+		return ""
+	case string:
+		return v
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+func num(a any) int {
+	if f, ok := a.(float64); ok {
+		return int(f)
+	}
+	return 0
+}
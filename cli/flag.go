@@ -16,10 +16,12 @@ package cli
 
 import (
 	"os"
+	"slices"
 
 	"github.com/abc-inc/heimdall/internal"
 	"github.com/mattn/go-isatty"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 )
 
 const (
@@ -28,6 +30,7 @@ const (
 	ServiceGroup  = "Service Commands"
 	SoftwareGroup = "Software Commands"
 	HeimdallGroup = "Heimdall Commands"
+	PluginGroup   = "Plugin Commands"
 )
 
 type OutCfg struct {
@@ -64,7 +67,7 @@ func addJQFlag(cmd *cobra.Command, v *string) {
 }
 
 func AddOutputFlag(cmd *cobra.Command, v *string) {
-	cmd.PersistentFlags().StringVarP(v, "output", "o", *v, "Output format (csv, json, table, template, template-file, text, tsv, yaml)")
+	cmd.PersistentFlags().StringVarP(v, "output", "o", *v, "Output format (csv, json, junit, sarif, table, template, template-file, text, tsv, yaml)")
 }
 
 func addPrettyFlag(cmd *cobra.Command, v *bool) {
@@ -75,3 +78,41 @@ func addPrettyFlag(cmd *cobra.Command, v *bool) {
 func addQueryFlag(cmd *cobra.Command, v *string) {
 	cmd.PersistentFlags().StringVarP(v, "query", "q", *v, "Specify a JMESPath query to use in filtering the output")
 }
+
+// SaveFlags records the values of the flags of cmd and all its subcommands, and
+// returns a function, which restores them. This allows executing commands
+// multiple times in the same process without leaking flags into the next run.
+func SaveFlags(cmd *cobra.Command) (restore func()) {
+	type flagState struct {
+		value   []string
+		changed bool
+	}
+
+	states := map[*pflag.Flag]flagState{}
+	var walk func(c *cobra.Command)
+	walk = func(c *cobra.Command) {
+		_ = c.InheritedFlags() // merge persistent flags of the parents
+		c.Flags().VisitAll(func(f *pflag.Flag) {
+			if sv, ok := f.Value.(pflag.SliceValue); ok {
+				states[f] = flagState{value: slices.Clone(sv.GetSlice()), changed: f.Changed}
+			} else {
+				states[f] = flagState{value: []string{f.Value.String()}, changed: f.Changed}
+			}
+		})
+		for _, sub := range c.Commands() {
+			walk(sub)
+		}
+	}
+	walk(cmd)
+
+	return func() {
+		for f, st := range states {
+			if sv, ok := f.Value.(pflag.SliceValue); ok {
+				_ = sv.Replace(st.value)
+			} else {
+				_ = f.Value.Set(st.value[0])
+			}
+			f.Changed = st.changed
+		}
+	}
+}
@@ -0,0 +1,113 @@
+// Copyright 2025 The Heimdall authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+)
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name     string          `xml:"name,attr"`
+	Tests    int             `xml:"tests,attr"`
+	Failures int             `xml:"failures,attr"`
+	Cases    []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// junitWriter formats finding-shaped values as JUnit XML report.
+// Every finding becomes a test case, which fails unless its level is "none".
+type junitWriter struct {
+	w      io.Writer
+	pretty bool
+}
+
+func newJUnit(w io.Writer, pretty bool) *junitWriter {
+	return &junitWriter{w: w, pretty: pretty}
+}
+
+func (j *junitWriter) Write(a any) (int, error) {
+	fs, err := ToFindings(a)
+	if err != nil {
+		return 0, err
+	}
+
+	var b []byte
+	if j.pretty {
+		b, err = xml.MarshalIndent(toJUnit(fs), "", "  ")
+	} else {
+		b, err = xml.Marshal(toJUnit(fs))
+	}
+	if err != nil {
+		return 0, err
+	}
+	return j.w.Write(append([]byte(xml.Header), b...))
+}
+
+func toJUnit(fs []Finding) junitTestSuites {
+	s := junitTestSuite{Name: "heimdall", Tests: len(fs), Cases: make([]junitTestCase, 0, len(fs))}
+	for _, f := range fs {
+		c := junitTestCase{Name: f.RuleID, ClassName: "heimdall"}
+		if len(f.Locations) > 0 {
+			c.ClassName = f.Locations[0].File
+		}
+		if f.Level != LevelNone {
+			s.Failures++
+			c.Failure = &junitFailure{Message: f.Message, Type: f.Level, Text: failureText(f)}
+		}
+		s.Cases = append(s.Cases, c)
+	}
+	return junitTestSuites{Name: "Heimdall", Tests: s.Tests, Failures: s.Failures, Suites: []junitTestSuite{s}}
+}
+
+// failureText returns the evaluated value and the locations of a finding.
+func failureText(f Finding) string {
+	var sb strings.Builder
+	if f.Evidence != "" && f.Evidence != f.Message {
+		sb.WriteString(f.Evidence + "\n")
+	}
+	for _, l := range f.Locations {
+		sb.WriteString(l.File)
+		if l.StartLine > 0 {
+			_, _ = fmt.Fprintf(&sb, ":%d", l.StartLine)
+		}
+		if l.EndLine > l.StartLine {
+			_, _ = fmt.Fprintf(&sb, "-%d", l.EndLine)
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
@@ -0,0 +1,42 @@
+// Copyright 2025 The Heimdall authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/abc-inc/heimdall/internal"
+	"github.com/joshdk/go-junit"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJUnit(t *testing.T) {
+	out := &bytes.Buffer{}
+	_ = internal.Must(newJUnit(out, true).Write(map[string]any{"results": []any{
+		map[string]any{"id": "r1", "severity": "high", "status": "pass", "inputs": []string{"a"}},
+		map[string]any{"id": "r2", "severity": "low", "status": "fail", "message": "m", "value": "false", "inputs": []string{"b"}},
+	}}))
+
+	ss := internal.Must(junit.Ingest(out.Bytes()))
+	require.Len(t, ss, 1)
+	require.Equal(t, 2, ss[0].Totals.Tests)
+	require.Equal(t, 1, ss[0].Totals.Failed)
+	require.Equal(t, "r1", ss[0].Tests[0].Name)
+	require.Equal(t, junit.StatusPassed, ss[0].Tests[0].Status)
+	require.Equal(t, "b", ss[0].Tests[1].Classname)
+	require.Equal(t, junit.StatusFailed, ss[0].Tests[1].Status)
+	require.Equal(t, junit.Error{Message: "m", Type: "note", Body: "false\nb\n"}, ss[0].Tests[1].Error)
+}
@@ -0,0 +1,287 @@
+// Copyright 2025 The Heimdall authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/abc-inc/heimdall/errs"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/viper"
+	"github.com/zalando/go-keyring"
+	"golang.org/x/crypto/scrypt"
+)
+
+// kr selects the keyring returned by Keyring once.
+var kr = sync.OnceValue(selectKeyring)
+
+// Keyring returns the system keyring, or an encrypted file keyring, if the
+// system keyring is not available, e.g., on Linux without a Secret Service.
+// HEIMDALL_KEYRING_BACKEND ("system" or "file") overrides the selection.
+// It is safe for concurrent use.
+func Keyring() keyring.Keyring {
+	return kr()
+}
+
+func selectKeyring() keyring.Keyring {
+	switch b := os.Getenv("HEIMDALL_KEYRING_BACKEND"); b {
+	case "system":
+		return systemKeyring{}
+	case "file":
+		return newFileKeyring()
+	case "":
+		// Probing an entry, which does not exist, fails if the keyring is not reachable.
+		_, err := keyring.Get(KeyringService, ".probe")
+		if err == nil || errors.Is(err, keyring.ErrNotFound) {
+			return systemKeyring{}
+		}
+		log.Debug().Err(err).Msg("System keyring not available, falling back to file keyring")
+		return newFileKeyring()
+	default:
+		errs.Abortf(errs.Usage, "invalid keyring backend '%s' (supported: system, file)", b)
+		return nil
+	}
+}
+
+// systemKeyring delegates to the keyring of the operating system.
+type systemKeyring struct{}
+
+func (systemKeyring) Set(service, user, password string) error {
+	return keyring.Set(service, user, password)
+}
+
+func (systemKeyring) Get(service, user string) (string, error) {
+	return keyring.Get(service, user)
+}
+
+func (systemKeyring) Delete(service, user string) error {
+	return keyring.Delete(service, user)
+}
+
+func (systemKeyring) DeleteAll(service string) error {
+	return keyring.DeleteAll(service)
+}
+
+// fileKeyring stores secrets in a file, which is encrypted with AES-256-GCM
+// using a key derived from a passphrase by scrypt. The derived key is cached,
+// because scrypt is deliberately slow.
+type fileKeyring struct {
+	path       string
+	passphrase func() ([]byte, error)
+	mu         sync.Mutex
+	derived    *derivedKey
+}
+
+// derivedKey is a key derived from the passphrase with the given hash, salt and
+// scrypt parameters.
+type derivedKey struct {
+	pass    [sha256.Size]byte
+	salt    []byte
+	n, r, p int
+	key     []byte
+}
+
+// fileKeyringData is the content of the keyring file.
+type fileKeyringData struct {
+	Version int    `json:"version"`
+	N       int    `json:"n"`
+	R       int    `json:"r"`
+	P       int    `json:"p"`
+	Salt    []byte `json:"salt"`
+	Nonce   []byte `json:"nonce"`
+	Data    []byte `json:"data"`
+}
+
+// newFileKeyring returns a keyring stored as "keyring.enc" in the config dir.
+// It is unlocked by HEIMDALL_KEYRING_PASSPHRASE or the content of the file
+// HEIMDALL_KEYRING_PASSPHRASE_FILE.
+func newFileKeyring() *fileKeyring {
+	dir := viper.GetString("config")
+	if dir == "" {
+		if d, err := os.UserConfigDir(); err == nil {
+			dir = filepath.Join(d, "heimdall")
+		}
+	}
+	return &fileKeyring{path: filepath.Join(dir, "keyring.enc"), passphrase: envPassphrase}
+}
+
+func envPassphrase() ([]byte, error) {
+	if p := os.Getenv("HEIMDALL_KEYRING_PASSPHRASE"); p != "" {
+		return []byte(p), nil
+	} else if f := os.Getenv("HEIMDALL_KEYRING_PASSPHRASE_FILE"); f != "" {
+		b, err := os.ReadFile(f)
+		return []byte(strings.TrimRight(string(b), "\r\n")), err
+	}
+	return nil, errs.Newf(errs.Auth, "file keyring requires %s or %s",
+		"HEIMDALL_KEYRING_PASSPHRASE", "HEIMDALL_KEYRING_PASSPHRASE_FILE")
+}
+
+func (k *fileKeyring) Set(service, user, password string) error {
+	return k.update(func(s map[string]map[string]string) error {
+		if s[service] == nil {
+			s[service] = map[string]string{}
+		}
+		s[service][user] = password
+		return nil
+	})
+}
+
+func (k *fileKeyring) Get(service, user string) (string, error) {
+	s, err := k.load()
+	if err != nil {
+		return "", err
+	}
+	if p, ok := s[service][user]; ok {
+		return p, nil
+	}
+	return "", keyring.ErrNotFound
+}
+
+func (k *fileKeyring) Delete(service, user string) error {
+	return k.update(func(s map[string]map[string]string) error {
+		if _, ok := s[service][user]; !ok {
+			return keyring.ErrNotFound
+		}
+		delete(s[service], user)
+		return nil
+	})
+}
+
+func (k *fileKeyring) DeleteAll(service string) error {
+	return k.update(func(s map[string]map[string]string) error {
+		delete(s, service)
+		return nil
+	})
+}
+
+func (k *fileKeyring) update(f func(s map[string]map[string]string) error) error {
+	s, err := k.load()
+	if err != nil {
+		return err
+	} else if err = f(s); err != nil {
+		return err
+	}
+	return k.save(s)
+}
+
+// load decrypts the secrets (by service and user), or returns an empty map if
+// the file does not exist.
+func (k *fileKeyring) load() (map[string]map[string]string, error) {
+	s := map[string]map[string]string{}
+	b, err := os.ReadFile(k.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	var d fileKeyringData
+	if err = json.Unmarshal(b, &d); err != nil {
+		return nil, fmt.Errorf("invalid keyring file %s: %w", k.path, err)
+	} else if d.Version != 1 {
+		return nil, fmt.Errorf("unsupported version %d of keyring file %s", d.Version, k.path)
+	}
+	gcm, err := k.cipher(d)
+	if err != nil {
+		return nil, err
+	}
+	b, err = gcm.Open(nil, d.Nonce, d.Data, nil)
+	if err != nil {
+		return nil, errs.Newf(errs.Auth, "cannot decrypt keyring file %s: wrong passphrase or corrupted file", k.path)
+	}
+	return s, json.Unmarshal(b, &s)
+}
+
+// save encrypts the secrets with a new nonce, and replaces the file. The salt
+// of the cached key is reused, if any, to avoid deriving another key.
+func (k *fileKeyring) save(s map[string]map[string]string) error {
+	d := fileKeyringData{Version: 1, N: 1 << 15, R: 8, P: 1, Salt: make([]byte, 16)}
+	k.mu.Lock()
+	if dk := k.derived; dk != nil {
+		d.N, d.R, d.P, d.Salt = dk.n, dk.r, dk.p, dk.salt
+	} else {
+		_, _ = rand.Read(d.Salt)
+	}
+	k.mu.Unlock()
+	gcm, err := k.cipher(d)
+	if err != nil {
+		return err
+	}
+	d.Nonce = make([]byte, gcm.NonceSize())
+	_, _ = rand.Read(d.Nonce)
+
+	b, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	d.Data = gcm.Seal(nil, d.Nonce, b, nil)
+	if b, err = json.Marshal(d); err != nil {
+		return err
+	}
+
+	if err = os.MkdirAll(filepath.Dir(k.path), 0700); err != nil {
+		return err
+	}
+	tmp := k.path + ".tmp"
+	if err = os.WriteFile(tmp, b, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, k.path)
+}
+
+func (k *fileKeyring) cipher(d fileKeyringData) (cipher.AEAD, error) {
+	pass, err := k.passphrase()
+	if err != nil {
+		return nil, err
+	}
+	key, err := k.key(pass, d)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// key returns the cached key, if it was derived from the same passphrase and
+// parameters, or derives a new one.
+func (k *fileKeyring) key(pass []byte, d fileKeyringData) ([]byte, error) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	h := sha256.Sum256(pass)
+	if dk := k.derived; dk != nil && dk.pass == h && bytes.Equal(dk.salt, d.Salt) && dk.n == d.N && dk.r == d.R && dk.p == d.P {
+		return dk.key, nil
+	}
+
+	key, err := scrypt.Key(pass, d.Salt, d.N, d.R, d.P, 32)
+	if err != nil {
+		return nil, err
+	}
+	k.derived = &derivedKey{pass: h, salt: d.Salt, n: d.N, r: d.R, p: d.P, key: key}
+	return key, nil
+}
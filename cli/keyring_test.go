@@ -0,0 +1,74 @@
+// Copyright 2025 The Heimdall authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/abc-inc/heimdall/errs"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+	"github.com/zalando/go-keyring"
+)
+
+func TestFileKeyring(t *testing.T) {
+	dir := t.TempDir()
+	viper.Set("config", dir)
+	defer viper.Reset()
+	t.Setenv("HEIMDALL_KEYRING_BACKEND", "file")
+	t.Setenv("HEIMDALL_KEYRING_PASSPHRASE", "passphrase")
+	kr = sync.OnceValue(selectKeyring)
+	defer func() { kr = sync.OnceValue(selectKeyring) }()
+
+	k := Keyring()
+	require.IsType(t, &fileKeyring{}, k)
+	_, err := k.Get(KeyringService, "default/github")
+	require.ErrorIs(t, err, keyring.ErrNotFound)
+
+	require.NoError(t, k.Set(KeyringService, "default/github", "secret"))
+	require.NoError(t, k.Set(KeyringService, "default/jira", "other"))
+	require.Equal(t, "secret", mustGet(t, k, "default/github"))
+
+	// the key is derived once and reused for reading and writing
+	dk := k.(*fileKeyring).derived
+	require.NotNil(t, dk)
+	require.Equal(t, "other", mustGet(t, k, "default/jira"))
+	require.Same(t, dk, k.(*fileKeyring).derived)
+	require.NoError(t, k.Delete(KeyringService, "default/jira"))
+	require.ErrorIs(t, k.Delete(KeyringService, "default/jira"), keyring.ErrNotFound)
+
+	b, err := os.ReadFile(filepath.Join(dir, "keyring.enc"))
+	require.NoError(t, err)
+	require.NotContains(t, string(b), "secret")
+
+	t.Setenv("HEIMDALL_KEYRING_PASSPHRASE", "wrong")
+	_, err = k.Get(KeyringService, "default/github")
+	require.Equal(t, errs.Auth, errs.KindOf(err))
+
+	f := filepath.Join(dir, "passphrase")
+	require.NoError(t, os.WriteFile(f, []byte("passphrase\n"), 0600))
+	t.Setenv("HEIMDALL_KEYRING_PASSPHRASE", "")
+	t.Setenv("HEIMDALL_KEYRING_PASSPHRASE_FILE", f)
+	require.Equal(t, "secret", mustGet(t, k, "default/github"))
+}
+
+func mustGet(t *testing.T, k keyring.Keyring, user string) string {
+	s, err := k.Get(KeyringService, user)
+	require.NoError(t, err)
+	return s
+}
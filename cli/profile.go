@@ -0,0 +1,101 @@
+// Copyright 2025 The Heimdall authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"os"
+	"strings"
+
+	"github.com/abc-inc/heimdall/errs"
+	"github.com/spf13/viper"
+)
+
+// profile is the name of the active profile, or empty if none is active.
+var profile string
+
+// Profile returns the name of the active profile.
+//
+// Profiles are sections in heimdall.yaml, which bundle the settings of the
+// services, e.g., the URL, the token and default flag values:
+//
+//	profiles:
+//	  ghes:
+//	    github:
+//	      url: https://github.company.corp/api/v3
+//	      token-env: GHES_TOKEN
+//	      owner: abc-inc
+func Profile() string {
+	return profile
+}
+
+// SetProfile activates the profile with the given name.
+// An empty name deactivates profiles.
+func SetProfile(name string) error {
+	if name != "" && !viper.IsSet("profiles."+name) {
+		return errs.Newf(errs.Usage, "profile '%s' does not exist", name)
+	}
+	profile = name
+	return nil
+}
+
+// ProfileSettings returns the names of the settings of a service in the active profile.
+func ProfileSettings(service string) []string {
+	if profile == "" {
+		return nil
+	}
+	var ks []string
+	for k := range viper.GetStringMap(profileKey(service)) {
+		ks = append(ks, k)
+	}
+	return ks
+}
+
+// ProfileString returns a setting of a service in the active profile.
+func ProfileString(service, key string) string {
+	if profile == "" {
+		return ""
+	}
+	return viper.GetString(profileKey(service, key))
+}
+
+// ProfileStrings returns a list setting of a service in the active profile.
+func ProfileStrings(service, key string) []string {
+	if profile == "" {
+		return nil
+	}
+	return viper.GetStringSlice(profileKey(service, key))
+}
+
+// Setting returns a setting of a service from the active profile, or else the
+// value of the first non-empty environment variable.
+func Setting(service, key string, envs ...string) string {
+	if v := ProfileString(service, key); v != "" {
+		return v
+	}
+	return firstEnv(envs...)
+}
+
+func profileKey(service string, key ...string) string {
+	return strings.Join(append([]string{"profiles", profile, service}, key...), ".")
+}
+
+func firstEnv(envs ...string) string {
+	for _, e := range envs {
+		if v := os.Getenv(e); v != "" {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,57 @@
+// Copyright 2025 The Heimdall authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/abc-inc/heimdall/errs"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProfile(t *testing.T) {
+	tok := filepath.Join(t.TempDir(), "token")
+	require.NoError(t, os.WriteFile(tok, []byte("file-token\n"), 0600))
+	viper.Set("profiles", map[string]any{
+		"ghes": map[string]any{
+			"github": map[string]any{"url": "https://github.company.corp/api/v3", "token-env": "GHES_TOKEN", "owner": "abc-inc"},
+			"jira":   map[string]any{"token-file": tok},
+		},
+	})
+	defer viper.Reset()
+	defer func() { profile = "" }()
+	AddTokenFlag(&cobra.Command{}, "github", "GITHUB_TOKEN")
+	defer delete(tokenSources, "github")
+	t.Setenv("GITHUB_API_URL", "https://api.github.com")
+	t.Setenv("GITHUB_TOKEN", "env-token")
+	t.Setenv("GHES_TOKEN", "ghes-token")
+
+	require.Equal(t, "https://api.github.com", Setting("github", "url", "GITHUB_API_URL"))
+	require.Equal(t, "env-token", Token("github"))
+	require.Empty(t, ProfileSettings("github"))
+
+	require.NoError(t, SetProfile("ghes"))
+	require.Equal(t, "https://github.company.corp/api/v3", Setting("github", "url", "GITHUB_API_URL"))
+	require.Equal(t, "ghes-token", Token("github"))
+	require.ElementsMatch(t, []string{"url", "token-env", "owner"}, ProfileSettings("github"))
+	require.Equal(t, "file-token", Token("jira"))
+
+	require.Equal(t, errs.Usage, errs.KindOf(SetProfile("missing")))
+	require.Equal(t, "ghes", Profile())
+}
@@ -0,0 +1,185 @@
+// Copyright 2025 The Heimdall authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"encoding/json"
+	"io"
+	"slices"
+)
+
+const (
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+	sarifVersion = "2.1.0"
+)
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	Version        string      `json:"version,omitempty"`
+	InformationURI string      `json:"informationUri,omitempty"`
+	Rules          []sarifRule `json:"rules,omitempty"`
+}
+
+type sarifRule struct {
+	ID string `json:"id"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations,omitempty"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine int `json:"startLine,omitempty"`
+	EndLine   int `json:"endLine,omitempty"`
+}
+
+// sarifWriter formats finding-shaped values as SARIF 2.1.0 log.
+// Existing SARIF logs are passed through, and a list of them is merged into
+// a single log.
+type sarifWriter struct {
+	w      io.Writer
+	pretty bool
+}
+
+func newSARIF(w io.Writer, pretty bool) *sarifWriter {
+	return &sarifWriter{w: w, pretty: pretty}
+}
+
+func (s *sarifWriter) Write(a any) (int, error) {
+	doc, err := s.toSARIF(a)
+	if err != nil {
+		return 0, err
+	}
+
+	var b []byte
+	if s.pretty {
+		b, err = json.MarshalIndent(doc, "", "  ")
+	} else {
+		b, err = json.Marshal(doc)
+	}
+	if err != nil {
+		return 0, err
+	}
+	return s.w.Write(b)
+}
+
+func (s *sarifWriter) toSARIF(a any) (any, error) {
+	if l, ok := mergeSARIF(a); ok {
+		return l, nil
+	}
+
+	fs, err := ToFindings(a)
+	if err != nil {
+		return nil, err
+	}
+
+	run := sarifRun{
+		Tool: sarifTool{Driver: sarifDriver{
+			Name:           "Heimdall",
+			Version:        Version,
+			InformationURI: "https://github.com/abc-inc/heimdall",
+		}},
+		Results: make([]sarifResult, 0, len(fs)),
+	}
+	for _, f := range fs {
+		if !slices.ContainsFunc(run.Tool.Driver.Rules, func(r sarifRule) bool { return r.ID == f.RuleID }) {
+			run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, sarifRule{ID: f.RuleID})
+		}
+		r := sarifResult{RuleID: f.RuleID, Level: f.Level, Message: sarifMessage{Text: f.Message}}
+		for _, l := range f.Locations {
+			pl := sarifPhysicalLocation{ArtifactLocation: sarifArtifactLocation{URI: l.File}}
+			if l.StartLine > 0 {
+				pl.Region = &sarifRegion{StartLine: l.StartLine, EndLine: l.EndLine}
+			}
+			r.Locations = append(r.Locations, sarifLocation{PhysicalLocation: pl})
+		}
+		run.Results = append(run.Results, r)
+	}
+
+	return sarifLog{Schema: sarifSchema, Version: sarifVersion, Runs: []sarifRun{run}}, nil
+}
+
+// mergeSARIF returns a single SARIF log, if the value is a SARIF log or a list
+// of SARIF logs. Runs are kept as they are, so that no information is lost.
+func mergeSARIF(a any) (map[string]any, bool) {
+	b, err := json.Marshal(a)
+	if err != nil {
+		return nil, false
+	}
+	var doc any
+	if err = json.Unmarshal(b, &doc); err != nil {
+		return nil, false
+	}
+
+	var ls []any
+	switch v := doc.(type) {
+	case map[string]any:
+		ls = []any{v}
+	case []any:
+		ls = v
+	}
+
+	var runs []any
+	for _, l := range ls {
+		m, ok := l.(map[string]any)
+		if !ok || m["version"] != sarifVersion {
+			return nil, false
+		}
+		rs, ok := m["runs"].([]any)
+		if !ok {
+			return nil, false
+		}
+		runs = append(runs, rs...)
+	}
+	if len(ls) == 0 {
+		return nil, false
+	}
+	return map[string]any{"$schema": sarifSchema, "version": sarifVersion, "runs": runs}, true
+}
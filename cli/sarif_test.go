@@ -0,0 +1,63 @@
+// Copyright 2025 The Heimdall authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/abc-inc/heimdall/internal"
+	"github.com/cli/go-gh/v2/pkg/jq"
+	"github.com/stretchr/testify/require"
+)
+
+func TestToFindings(t *testing.T) {
+	alert := map[string]any{
+		"rule":  map[string]any{"id": "go/sql-injection", "severity": "error"},
+		"state": "open",
+		"most_recent_instance": map[string]any{
+			"message":  map[string]any{"text": "Query depends on user input"},
+			"location": map[string]any{"path": "main.go", "start_line": 12, "end_line": 13},
+		},
+	}
+	check := map[string]any{"id": "c1", "severity": "low", "status": "pass", "description": "d", "inputs": []string{"a", "b"}}
+
+	fs := internal.Must(ToFindings([]any{alert, check}))
+	require.Equal(t, []Finding{
+		{RuleID: "go/sql-injection", Level: LevelError, Message: "Query depends on user input",
+			Locations: []Location{{File: "main.go", StartLine: 12, EndLine: 13}}},
+		{RuleID: "c1", Level: LevelNone, Message: "d", Locations: []Location{{File: "a"}, {File: "b"}}},
+	}, fs)
+}
+
+func TestSARIF(t *testing.T) {
+	out := &bytes.Buffer{}
+	_ = internal.Must(newSARIF(out, false).Write(map[string]any{"results": []any{
+		map[string]any{"id": "r1", "severity": "high", "status": "fail", "message": "m", "inputs": []string{"f"}},
+	}}))
+
+	res := &bytes.Buffer{}
+	internal.MustNoErr(jq.Evaluate(bytes.NewReader(out.Bytes()), res, `.version, (.runs[0].results[0] | .ruleId, .level, .locations[0].physicalLocation.artifactLocation.uri)`))
+	require.Equal(t, "2.1.0\nr1\nerror\nf\n", res.String())
+
+	merged := &bytes.Buffer{}
+	_ = internal.Must(newSARIF(merged, false).Write([]any{
+		map[string]any{"version": "2.1.0", "runs": []any{map[string]any{"tool": "a"}}},
+		map[string]any{"version": "2.1.0", "runs": []any{map[string]any{"tool": "b"}}},
+	}))
+	res.Reset()
+	internal.MustNoErr(jq.Evaluate(bytes.NewReader(merged.Bytes()), res, `[.runs[].tool] | join(",")`))
+	require.Equal(t, "a,b\n", res.String())
+}
@@ -15,6 +15,7 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -23,9 +24,12 @@ import (
 	"strings"
 
 	"github.com/abc-inc/heimdall/cli"
+	"github.com/abc-inc/heimdall/errs"
 	"github.com/abc-inc/heimdall/internal"
+	"github.com/abc-inc/heimdall/plugin/external"
 	_ "github.com/abc-inc/heimdall/plugin/github"
 	"github.com/abc-inc/heimdall/plugin/root"
+	"github.com/abc-inc/heimdall/res"
 	"github.com/joho/godotenv"
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
@@ -55,22 +59,38 @@ func main() {
 		os.Args = slices.Insert(os.Args, 1, parts...)
 	}
 
-	if err := rootCmd.Execute(); err != nil &&
-		!strings.HasPrefix(err.Error(), "unknown command ") &&
-		!strings.HasPrefix(err.Error(), "unknown shorthand flag: ") &&
-		!strings.HasPrefix(err.Error(), "no flags provided") &&
-		!strings.HasPrefix(err.Error(), "unknown flag: ") &&
-		!strings.HasPrefix(err.Error(), "required flag(s) ") &&
-		!strings.HasPrefix(err.Error(), "accepts 1 arg(s),") {
+	// External plugins are added last, so that they cannot shadow built-in commands.
+	root.AddPluginCmds()
+	os.Args = external.Args(rootCmd, os.Args)
+
+	cli.HandleErrors(rootCmd)
+	if cmd, err := execute(rootCmd); err != nil {
+		if errs.KindOf(err) == errs.Usage && cmd != nil {
+			cmd.PrintErrln("Error:", err)
+			cmd.PrintErrf("Run '%s --help' for usage.\n", cmd.CommandPath())
+		} else if !errors.Is(err, errs.ErrViolation) {
+			log.WithLevel(zerolog.FatalLevel).AnErr(cli.ErrKey, err).Str("kind", errs.KindOf(err).String()).Send()
+		}
+		cli.Exit(errs.ExitCode(err))
+	}
+}
 
-		internal.MustNoErr(err)
+// execute runs the command and returns errors including those raised outside of
+// commands, e.g., while loading the config.
+func execute(rootCmd *cobra.Command) (cmd *cobra.Command, err error) {
+	defer errs.Recover(&err)
+	if cmd, err = rootCmd.ExecuteC(); err != nil && !errors.As(err, new(*errs.Error)) {
+		// Errors returned by commands have a kind (see cli.HandleErrors).
+		// Others are raised by cobra, e.g., unknown flags or missing arguments.
+		err = errs.New(errs.Usage, err)
 	}
+	return cmd, err
 }
 
 // initConfig reads the config file and environment variables, if set.
 func initConfig(rootCmd *cobra.Command) {
 	// Attempt to load environment variables from files.
-	cfgDir := internal.Must(rootCmd.PersistentFlags().GetString("config"))
+	cfgDir := root.ConfigDir()
 	_ = godotenv.Overload("/etc/heimdall/heimdall.env")
 	_ = godotenv.Load(".heimdall.env")
 	_ = godotenv.Load(internal.Must(filepath.Glob(filepath.Join(cfgDir, "*.env")))...)
@@ -97,12 +117,45 @@ func initConfig(rootCmd *cobra.Command) {
 		log.Info().Str("file", viper.ConfigFileUsed()).Msg("Loaded config")
 	}
 
+	initNetwork(envPrefix)
 	postInitCommands(rootCmd)
 
+	if err := cli.SetProfile(internal.Must(rootCmd.PersistentFlags().GetString("profile"))); err != nil {
+		errs.Abort(err)
+	}
+	for _, cmd := range rootCmd.Commands() {
+		presetProfileFlags(cmd.Name(), cmd)
+	}
+
 	l := internal.Must(rootCmd.PersistentFlags().GetString("log-level"))
 	zerolog.SetGlobalLevel(internal.Must(zerolog.ParseLevel(l)))
 }
 
+// initNetwork applies the network settings, e.g., HEIMDALL_CA_FILE or "ca-file"
+// in the config file, to the clients of all remote services.
+func initNetwork(envPrefix string) {
+	for _, k := range []string{"ca-file", "client-cert", "client-key", "insecure-skip-verify", "proxy"} {
+		internal.MustNoErr(viper.BindEnv(k, envPrefix+"_"+strings.ToUpper(strings.ReplaceAll(k, "-", "_"))))
+	}
+
+	// A proxy is either a single URL for all hosts, or a map of host patterns.
+	proxies := viper.GetStringMapString("proxy")
+	if p := viper.GetString("proxy"); p != "" {
+		proxies = map[string]string{"*": p}
+	}
+
+	n := res.Network{
+		CAFile:             viper.GetString("ca-file"),
+		CertFile:           viper.GetString("client-cert"),
+		KeyFile:            viper.GetString("client-key"),
+		Proxies:            proxies,
+		InsecureSkipVerify: viper.GetBool("insecure-skip-verify"),
+	}
+	if err := res.Configure(n); err != nil {
+		errs.Abort(errs.New(errs.Input, err))
+	}
+}
+
 func postInitCommands(cmds ...*cobra.Command) {
 	for _, cmd := range cmds {
 		presetFlags(cmd)
@@ -124,6 +177,34 @@ func presetFlags(cmd *cobra.Command) {
 	})
 }
 
+// presetProfileFlags sets the values of flags, which are not set explicitly, to
+// the settings of the service in the active profile, e.g., "owner" for github.
+// In contrast to presetFlags, the flags are not marked as changed, so that they
+// do not conflict with mutually exclusive flags. Instead, required flags are no
+// longer required, because the profile provides the value.
+func presetProfileFlags(service string, cmds ...*cobra.Command) {
+	ks := cli.ProfileSettings(service)
+	for _, cmd := range cmds {
+		for _, k := range ks {
+			f := cmd.Flags().Lookup(k)
+			if f == nil || f.Changed {
+				continue
+			}
+			var err error
+			if sv, ok := f.Value.(pflag.SliceValue); ok {
+				err = sv.Replace(cli.ProfileStrings(service, k))
+			} else {
+				err = f.Value.Set(cli.ProfileString(service, k))
+			}
+			if err != nil {
+				errs.Abortf(errs.Usage, "invalid setting '%s.%s' in profile '%s': %v", service, k, cli.Profile(), err)
+			}
+			delete(f.Annotations, cobra.BashCompOneRequiredFlag)
+		}
+		presetProfileFlags(service, cmd.Commands()...)
+	}
+}
+
 func versionCmd() *cobra.Command {
 	return &cobra.Command{
 		Use:     "version",
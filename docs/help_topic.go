@@ -24,6 +24,7 @@ import (
 	"strings"
 
 	"github.com/abc-inc/heimdall"
+	"github.com/abc-inc/heimdall/errs"
 	"github.com/abc-inc/heimdall/internal"
 	"github.com/alecthomas/chroma/v2"
 	"github.com/alecthomas/chroma/v2/formatters"
@@ -34,7 +35,6 @@ import (
 	"github.com/charmbracelet/gum/style"
 	"github.com/fatih/color"
 	"github.com/mattn/go-isatty"
-	"github.com/rs/zerolog/log"
 	"github.com/spf13/cobra"
 )
 
@@ -46,7 +46,14 @@ type Topic struct {
 var Topics = []Topic{
 	{File: "man/expr-lang.md", Desc: "Overview for expr (built-in expression language)"},
 	{File: "contributing.md", Desc: "Information for improving Heimdall"},
+	{File: "credentials.md", Desc: "Resolution of service tokens and the keyring"},
+	{File: "evidence.md", Desc: "Recording evidence bundles for audits"},
+	{File: "exit-codes.md", Desc: "Description of exit codes"},
 	{File: "formatting.md", Desc: "Description of output formats and filters"},
+	{File: "network.md", Desc: "Proxy, CA and client certificate settings"},
+	{File: "plugins.md", Desc: "Extending Heimdall with external commands"},
+	{File: "profiles.md", Desc: "Settings for multiple instances of remote services"},
+	{File: "serve.md", Desc: "Running commands via a local REST API"},
 	{File: "source.md", Desc: "Instructions for building Heimdall from source"},
 	{File: "themes", Desc: "Display a list of supported themes for syntax highlighting"},
 	{File: "why.md", Desc: fmt.Sprintf("Why %s?", color.New(color.Italic).Sprint("Heimdall"))},
@@ -126,7 +133,7 @@ func MustStyle(n string) string {
 	s := styles.Get(n)
 	if s == styles.Fallback {
 		ns := maps.Keys(styles.Registry)
-		internal.MustOkMsgf(n, s != styles.Fallback, "Style '%s' does not exist. Valid styles are: %s", n, strings.Join(slices.Sorted(ns), " "))
+		errs.Abortf(errs.Usage, "style '%s' does not exist, valid styles are: %s", n, strings.Join(slices.Sorted(ns), " "))
 	}
 	return n
 }
@@ -141,9 +148,7 @@ func listThemes() string {
 		for _, s := range styles.Names() {
 			_ = internal.Must(fmt.Fprint(b, "Theme: "+s+"\n    "))
 			it, _ := l.Tokenise(nil, ex)
-			if err := formatters.TTY.Format(b, styles.Get(s), it); err != nil {
-				log.Fatal().Err(err).Send()
-			}
+			internal.MustNoErr(formatters.TTY.Format(b, styles.Get(s), it))
 			b.WriteString("\n\n")
 		}
 		return b.String()
@@ -0,0 +1,181 @@
+// Copyright 2025 The Heimdall authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package errs defines the kinds of errors reported by Heimdall and maps them
+// to exit codes, so that scripts can tell them apart.
+package errs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"net/http"
+	"net/url"
+)
+
+// Kind classifies an error. Its value is the exit code of the program.
+type Kind int
+
+const (
+	// Violation means that a policy was violated, e.g., a rule failed.
+	Violation Kind = iota + 1
+	// Usage means that the command line is invalid, e.g., an unknown flag.
+	Usage
+	// Input means that an input file cannot be read or parsed.
+	Input
+	// Auth means that credentials are missing or invalid.
+	Auth
+	// Remote means that a remote service failed or cannot be reached.
+	Remote
+	// Failure is any other error.
+	Failure
+)
+
+var kindNames = map[Kind]string{
+	Violation: "policy violation",
+	Usage:     "usage error",
+	Input:     "input error",
+	Auth:      "auth error",
+	Remote:    "remote service error",
+	Failure:   "failure",
+}
+
+// Sentinel errors for use with errors.Is, e.g., errors.Is(err, errs.ErrAuth).
+var (
+	ErrViolation = &Error{Kind: Violation}
+	ErrUsage     = &Error{Kind: Usage}
+	ErrInput     = &Error{Kind: Input}
+	ErrAuth      = &Error{Kind: Auth}
+	ErrRemote    = &Error{Kind: Remote}
+	ErrFailure   = &Error{Kind: Failure}
+)
+
+func (k Kind) String() string {
+	return kindNames[k]
+}
+
+// ExitCode returns the exit code of the program for errors of this kind.
+func (k Kind) ExitCode() int {
+	return int(k)
+}
+
+// Error is an error of a certain kind.
+type Error struct {
+	Kind Kind
+	Err  error
+	// Code overrides the exit code of the kind, if non-zero. It is reserved for
+	// commands, which documented a different exit code before kinds existed.
+	Code int
+}
+
+func (e *Error) Error() string {
+	if e.Err == nil {
+		return e.Kind.String()
+	}
+	return e.Err.Error()
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether the target is the sentinel error of the same kind.
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Err == nil && t.Kind == e.Kind
+}
+
+// New returns an error of the given kind, or nil if err is nil.
+func New(k Kind, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &Error{Kind: k, Err: err}
+}
+
+// Newf formats an error message and returns an error of the given kind.
+func Newf(k Kind, format string, args ...any) error {
+	return &Error{Kind: k, Err: fmt.Errorf(format, args...)}
+}
+
+// FromStatus returns an error of a kind derived from the HTTP status code of a
+// response, or nil if err is nil.
+// Unauthorized and forbidden responses are auth errors, others remote errors.
+func FromStatus(code int, err error) error {
+	if code == http.StatusUnauthorized || code == http.StatusForbidden {
+		return New(Auth, err)
+	}
+	return New(Remote, err)
+}
+
+// KindOf returns the kind of the error. Errors without a kind are classified
+// by their type, e.g., a *fs.PathError is an input error.
+func KindOf(err error) Kind {
+	var e *Error
+	var pe *fs.PathError
+	var ue *url.Error
+	var ne net.Error
+	switch {
+	case errors.As(err, &e):
+		return e.Kind
+	case errors.As(err, &pe), errors.Is(err, fs.ErrNotExist), errors.Is(err, fs.ErrPermission):
+		return Input
+	case errors.As(err, &ue), errors.As(err, &ne), errors.Is(err, context.DeadlineExceeded):
+		return Remote
+	default:
+		return Failure
+	}
+}
+
+// ExitCode returns the exit code of the program for the error.
+func ExitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+	if e := (*Error)(nil); errors.As(err, &e) && e.Code != 0 {
+		return e.Code
+	}
+	return KindOf(err).ExitCode()
+}
+
+// abort is the value of a panic raised by Abort.
+type abort struct {
+	err error
+}
+
+// Abort stops the execution of the current command by panicking. Recover
+// turns the panic into an error, which is returned by the command.
+func Abort(err error) {
+	panic(abort{err: err})
+}
+
+// Abortf is like Abort, but formats the error message.
+func Abortf(k Kind, format string, args ...any) {
+	Abort(Newf(k, format, args...))
+}
+
+// Recover stores the error passed to Abort, if the current goroutine is
+// panicking because of it. Other panics are propagated. It must be deferred
+// directly, e.g., defer errs.Recover(&err).
+func Recover(err *error) {
+	if r := recover(); r != nil {
+		a, ok := r.(abort)
+		if !ok {
+			panic(r)
+		}
+		*err = a.err
+	}
+}
@@ -0,0 +1,58 @@
+// Copyright 2025 The Heimdall authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package errs
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestExitCode(t *testing.T) {
+	_, err := os.Open("does-not-exist")
+	require.Equal(t, 0, ExitCode(nil))
+	require.Equal(t, 1, ExitCode(Newf(Violation, "rule failed")))
+	require.Equal(t, 2, ExitCode(fmt.Errorf("wrapped: %w", Newf(Usage, "unknown flag"))))
+	require.Equal(t, 3, ExitCode(err))
+	require.Equal(t, 4, ExitCode(FromStatus(http.StatusUnauthorized, errors.New("bad credentials"))))
+	require.Equal(t, 5, ExitCode(FromStatus(http.StatusBadGateway, errors.New("bad gateway"))))
+	require.Equal(t, 6, ExitCode(errors.New("unexpected")))
+	require.Equal(t, 2, ExitCode(fmt.Errorf("wrapped: %w", &Error{Kind: Violation, Code: 2})))
+
+	require.ErrorIs(t, New(Auth, errors.New("x")), ErrAuth)
+	require.NotErrorIs(t, New(Auth, errors.New("x")), ErrRemote)
+	require.NoError(t, New(Input, nil))
+}
+
+func TestRecover(t *testing.T) {
+	f := func() (err error) {
+		defer Recover(&err)
+		Abortf(Input, "cannot read %s", "x")
+		return nil
+	}
+	err := f()
+	require.ErrorIs(t, err, ErrInput)
+	require.EqualError(t, err, "cannot read x")
+
+	require.Panics(t, func() {
+		var err error
+		defer Recover(&err)
+		panic("unexpected")
+	})
+}
@@ -15,38 +15,41 @@
 package internal
 
 import (
-	"reflect"
+	"fmt"
+	"runtime/debug"
 
+	"github.com/abc-inc/heimdall/errs"
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
 )
 
-// Must panics if the error is non-nil. It is intended for use in variable
-// initializations such as
-// f := internal.MustNoErr(os.OpenFile("notes.txt", os.O_RDONLY, 0600))
+// Must aborts the current command if the error is non-nil. It is intended for
+// use in variable initializations such as
+// f := internal.Must(os.OpenFile("notes.txt", os.O_RDONLY, 0600))
 func Must[T any](a T, err error) T {
 	MustNoErr(err)
 	return a
 }
 
-// MustNoErr logs the error message and exits, if the error is non-nil. It is
+// MustNoErr aborts the current command if the error is non-nil. It is
 // intended for use in statements that could return an error and continued
-// execution is not meaningful.
+// execution is not meaningful. The error is returned by the command (see
+// errs.Recover).
 func MustNoErr(err error) {
 	if err != nil {
 		if zerolog.GlobalLevel() == zerolog.TraceLevel {
-			panic(err)
+			log.Trace().AnErr("ERROR", err).Msg(string(debug.Stack()))
 		}
-		log.Fatal().AnErr("ERROR", err).Str("type", reflect.TypeOf(err).String()).Send()
+		errs.Abort(err)
 	}
 }
 
-// MustOkMsgf logs the error message and exits, if the condition is false.
+// MustOkMsgf aborts the current command if the condition is false.
 // It is intended for use in statements that could return a flag and continued
 // execution is not meaningful.
 func MustOkMsgf[T any](a T, ok bool, msg string, args ...any) T {
 	if !ok {
-		log.Fatal().Msgf(msg, args...)
+		MustNoErr(fmt.Errorf(msg, args...))
 	}
 	return a
 }
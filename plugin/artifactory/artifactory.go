@@ -17,10 +17,13 @@
 package artifactory
 
 import (
+	"net/http"
 	"os"
 
 	"github.com/abc-inc/heimdall/cli"
+	"github.com/abc-inc/heimdall/errs"
 	"github.com/abc-inc/heimdall/internal"
+	"github.com/abc-inc/heimdall/res"
 	"github.com/jfrog/jfrog-client-go/artifactory"
 	"github.com/jfrog/jfrog-client-go/artifactory/auth"
 	"github.com/jfrog/jfrog-client-go/config"
@@ -46,22 +49,28 @@ func NewArtifactoryCmd() *cobra.Command {
 		NewDownloadCmd(),
 		NewListCmd(),
 	)
+	cli.AddTokenFlag(cmd, "artifactory", "ARTIFACTORY_TOKEN")
 
 	return cmd
 }
 
 func newRtManager() artifactory.ArtifactoryServicesManager {
-	url, ok := os.LookupEnv("ARTIFACTORY_BASE_URL")
-	internal.MustOkMsgf(url, ok, "environment variable '%s' must be set", "ARTIFACTORY_BASE_URL")
+	url := cli.Setting("artifactory", "url", "ARTIFACTORY_BASE_URL")
+	if url == "" {
+		errs.Abortf(errs.Usage, "environment variable '%s' must be set", "ARTIFACTORY_BASE_URL")
+	}
 
-	tok, ok := os.LookupEnv("ARTIFACTORY_TOKEN")
-	internal.MustOkMsgf(tok, ok, "environment variable '%s' must be set", "ARTIFACTORY_TOKEN")
+	tok := cli.Token("artifactory")
+	if tok == "" {
+		errs.Abortf(errs.Auth, "environment variable '%s' must be set", "ARTIFACTORY_TOKEN")
+	}
 
 	rtDetails := auth.NewArtifactoryDetails()
 	rtDetails.SetUrl(url)
 	rtDetails.SetAccessToken(tok)
 
-	svcCfg := internal.Must(config.NewConfigBuilder().SetServiceDetails(rtDetails).Build())
+	svcCfg := internal.Must(config.NewConfigBuilder().SetServiceDetails(rtDetails).SetContext(cli.Context()).
+		SetHttpClient(&http.Client{Transport: res.Transport(nil)}).SetHttpRetries(0).Build())
 	rtManager := internal.Must(artifactory.New(svcCfg))
 	return rtManager
 }
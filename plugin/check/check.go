@@ -0,0 +1,251 @@
+// Copyright 2025 The Heimdall authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !no_check
+
+package check
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/MakeNowJust/heredoc/v2"
+	"github.com/abc-inc/heimdall/cli"
+	"github.com/abc-inc/heimdall/errs"
+	"github.com/abc-inc/heimdall/plugin/eval"
+	"github.com/abc-inc/heimdall/res"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// Severities lists all supported severities in ascending order.
+var Severities = []string{"info", "low", "medium", "high", "critical"}
+
+type Status string
+
+const (
+	StatusPass  Status = "pass"
+	StatusFail  Status = "fail"
+	StatusError Status = "error"
+)
+
+// Policy is a set of named rules, which are evaluated independently.
+type Policy struct {
+	Rules []Rule `json:"rules" yaml:"rules"`
+}
+
+// Rule evaluates an expression against input files.
+// The rule passes, if the expression yields a truthy result.
+type Rule struct {
+	ID          string   `json:"id" yaml:"id"`
+	Description string   `json:"description,omitempty" yaml:"description,omitempty"`
+	Severity    string   `json:"severity,omitempty" yaml:"severity,omitempty"`
+	Files       []string `json:"files,omitempty" yaml:"files,omitempty"`
+	Engine      string   `json:"engine,omitempty" yaml:"engine,omitempty"`
+	Expression  string   `json:"expression" yaml:"expression"`
+	Message     string   `json:"message,omitempty" yaml:"message,omitempty"`
+}
+
+// Result is the outcome of a single rule.
+type Result struct {
+	ID          string   `json:"id" yaml:"id"`
+	Description string   `json:"description,omitempty" yaml:"description,omitempty"`
+	Severity    string   `json:"severity" yaml:"severity"`
+	Status      Status   `json:"status" yaml:"status"`
+	Message     string   `json:"message,omitempty" yaml:"message,omitempty"`
+	Value       string   `json:"value,omitempty" yaml:"value,omitempty"`
+	Inputs      []string `json:"inputs,omitempty" yaml:"inputs,omitempty"`
+}
+
+type Summary struct {
+	Total  int `json:"total" yaml:"total"`
+	Passed int `json:"passed" yaml:"passed"`
+	Failed int `json:"failed" yaml:"failed"`
+	Errors int `json:"errors" yaml:"errors"`
+}
+
+type Report struct {
+	Results []Result `json:"results" yaml:"results"`
+	Summary Summary  `json:"summary" yaml:"summary"`
+}
+
+type checkCfg struct {
+	cli.OutCfg
+	policies []string
+	failOn   string
+}
+
+func NewCheckCmd() *cobra.Command {
+	cfg := checkCfg{failOn: Severities[0]}
+	cmd := &cobra.Command{
+		Use:     "check [flags] <policy>...",
+		Short:   "Run the rules of a policy file and report the results",
+		GroupID: cli.HeimdallGroup,
+		Long: heredoc.Doc(`
+			Run the rules of one or more policy files and report the result of each rule.
+
+			A policy file is a YAML (or JSON) document containing a list of rules.
+			Every rule has a unique id, an optional description and severity (` + strings.Join(Severities, ", ") + `),
+			a list of input files, an eval engine (default: expr), an expression and an optional failure message.
+			Input files use the same syntax as the eval command, i.e., "<file>[:<alias>[:<type>]]".
+
+			The command exits with status 1, if any rule at or above the --fail-on severity fails or cannot be evaluated.
+			See 'heimdall help:exit-codes' for other exit codes.
+		`),
+		Example: heredoc.Doc(`
+			# policy.yaml
+			rules:
+			  - id: gradle-wrapper-version
+			    description: The Gradle wrapper must use Gradle 8 or later.
+			    severity: high
+			    files: [ gradle/wrapper/gradle-wrapper.properties ]
+			    expression: 'base(distributionUrl) matches "gradle-([89]|[1-9][0-9])[.]"'
+			    message: Outdated Gradle version
+
+			heimdall check --fail-on medium policy.yaml
+		`),
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if rank(cfg.failOn) < 0 {
+				return errs.Newf(errs.Usage, "invalid severity '%s', must be one of %s", cfg.failOn, strings.Join(Severities, ", "))
+			}
+
+			cfg.policies = args
+			p, err := loadPolicies(cfg.policies...)
+			if err != nil {
+				return err
+			}
+			rep := check(cfg, p)
+			cli.Fmtln(rep)
+			if failed(rep, cfg.failOn) {
+				return errs.Newf(errs.Violation, "%d of %d rules failed", rep.Summary.Failed+rep.Summary.Errors, rep.Summary.Total)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&cfg.failOn, "fail-on", cfg.failOn, "Minimum severity of failed rules causing a non-zero exit code ("+strings.Join(Severities, ", ")+")")
+
+	cli.AddOutputFlags(cmd, &cfg.OutCfg)
+	cmd.DisableFlagsInUseLine = true
+	return cmd
+}
+
+func loadPolicies(names ...string) (p Policy, err error) {
+	for _, n := range names {
+		np, err := LoadPolicy(n)
+		if err != nil {
+			return p, err
+		}
+		p.Rules = append(p.Rules, np.Rules...)
+	}
+	return p, nil
+}
+
+// LoadPolicy reads a policy file and validates its rules.
+func LoadPolicy(name string) (p Policy, err error) {
+	r, err := res.Open(name)
+	if err != nil {
+		return p, err
+	}
+	defer func() { _ = r.Close() }()
+
+	d := yaml.NewDecoder(r)
+	d.KnownFields(true)
+	if err = d.Decode(&p); err != nil {
+		return p, errs.Newf(errs.Input, "cannot read policy '%s': %w", name, err)
+	}
+
+	ids := make(map[string]bool)
+	for i := range p.Rules {
+		rule := &p.Rules[i]
+		if rule.ID == "" {
+			return p, errs.Newf(errs.Input, "rule #%d in '%s' has no id", i+1, name)
+		} else if ids[rule.ID] {
+			return p, errs.Newf(errs.Input, "duplicate rule id '%s' in '%s'", rule.ID, name)
+		}
+		ids[rule.ID] = true
+
+		if rule.Severity = strings.ToLower(rule.Severity); rule.Severity == "" {
+			rule.Severity = "medium"
+		} else if rank(rule.Severity) < 0 {
+			return p, errs.Newf(errs.Input, "rule '%s' has invalid severity '%s'", rule.ID, rule.Severity)
+		}
+		if rule.Engine == "" {
+			rule.Engine = "expr"
+		}
+	}
+	return p, nil
+}
+
+func check(cfg checkCfg, p Policy) (rep Report) {
+	rep.Results = make([]Result, 0, len(p.Rules))
+	for _, rule := range p.Rules {
+		r := Run(rule)
+		log.Debug().Str("id", r.ID).Str("status", string(r.Status)).Msg("Checked rule")
+
+		rep.Summary.Total++
+		switch r.Status {
+		case StatusPass:
+			rep.Summary.Passed++
+		case StatusFail:
+			rep.Summary.Failed++
+		case StatusError:
+			rep.Summary.Errors++
+		}
+		rep.Results = append(rep.Results, r)
+	}
+	return rep
+}
+
+// Run evaluates a single rule.
+func Run(rule Rule) Result {
+	r := Result{ID: rule.ID, Description: rule.Description, Severity: rule.Severity}
+
+	is, err := eval.ResolveFiles(rule.Files)
+	if err != nil {
+		r.Status, r.Message = StatusError, err.Error()
+		return r
+	}
+	for _, i := range is {
+		r.Inputs = append(r.Inputs, i.File)
+	}
+
+	vs, err := eval.Evaluate(rule.Engine, []string{rule.Expression}, is...)
+	if err != nil {
+		r.Status, r.Message = StatusError, err.Error()
+		return r
+	}
+
+	r.Value = strings.Join(vs, "\n")
+	if eval.Truthy(vs) {
+		r.Status = StatusPass
+	} else if r.Status, r.Message = StatusFail, rule.Message; r.Message == "" {
+		r.Message = fmt.Sprintf("expression evaluated to '%s'", r.Value)
+	}
+	return r
+}
+
+// failed reports whether any rule with at least the given severity did not pass.
+func failed(rep Report, minSeverity string) bool {
+	return slices.ContainsFunc(rep.Results, func(r Result) bool {
+		return r.Status != StatusPass && rank(r.Severity) >= rank(minSeverity)
+	})
+}
+
+func rank(severity string) int {
+	return slices.Index(Severities, strings.ToLower(severity))
+}
@@ -0,0 +1,53 @@
+// Copyright 2025 The Heimdall authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !no_check
+
+package check
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/abc-inc/heimdall/internal"
+	"github.com/abc-inc/heimdall/test"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheck(t *testing.T) {
+	wd := internal.Must(os.Getwd())
+	defer func() { internal.MustNoErr(os.Chdir(wd)) }()
+	internal.MustNoErr(os.Chdir(filepath.Join(test.GetRootDir(), "testdata")))
+
+	p := internal.Must(LoadPolicy("policy.yaml"))
+	rep := check(checkCfg{failOn: "info"}, p)
+
+	require.Equal(t, Summary{Total: 3, Passed: 2, Failed: 1}, rep.Summary)
+	require.Equal(t, StatusPass, rep.Results[0].Status)
+	require.Equal(t, "high", rep.Results[0].Severity)
+	require.Equal(t, []string{"gradle/wrapper/gradle-wrapper.properties"}, rep.Results[0].Inputs)
+	require.Equal(t, StatusFail, rep.Results[1].Status)
+	require.Equal(t, "The Gradle distribution is not stored in the project.", rep.Results[1].Message)
+	require.Equal(t, "2", rep.Results[2].Value)
+
+	require.True(t, failed(rep, "low"))
+	require.False(t, failed(rep, "medium"))
+}
+
+func TestRunError(t *testing.T) {
+	r := Run(Rule{ID: "invalid", Severity: "low", Engine: "expr", Expression: "1 +"})
+	require.Equal(t, StatusError, r.Status)
+	require.NotEmpty(t, r.Message)
+}
@@ -64,22 +64,33 @@ func NewConfluenceCmd() *cobra.Command {
 		NewUpdateCmd(),
 		NewSearchCmd(),
 	)
+	cli.AddTokenFlag(cmd, "confluence", "CONFLUENCE_TOKEN")
 
 	return cmd
 }
 
-// newClient creates a new Confluence client.
-func newClient(baseURL, token string) (*goconfluence.API, error) {
+// newClient creates a new Confluence client, which retries failed requests,
+// records or replays them, if enabled, and cancels them after the timeout, or
+// when the context of the command is done.
+func newClient(baseURL, token string, timeout time.Duration) (*goconfluence.API, error) {
 	if baseURL == "" || token == "" {
 		return nil, fmt.Errorf("CONFLUENCE_API_URL and CONFLUENCE_TOKEN must be defined")
 	}
 	api, err := goconfluence.NewAPI(baseURL, "", token)
 	if err == nil {
 		api.Client.Jar = internal.Must(cookiejar.New(nil))
+		api.Client.Timeout = timeout
+		api.Client.Transport = res.WithContext(res.Transport(nil))
 	}
 	return api, err
 }
 
+// loadSettings reads the URL and token from the active profile or environment.
+func (c *confluenceCfg) loadSettings() {
+	c.baseURL = cli.Setting("confluence", "url", "CONFLUENCE_API_URL")
+	c.token = cli.Token("confluence")
+}
+
 func addCommonFlags(cmd *cobra.Command, cfg *confluenceCfg) {
 	cmd.Flags().DurationVarP(&cfg.timeout, "timeout", "T", cfg.timeout, "Set the network timeout in seconds")
 }
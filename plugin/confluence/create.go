@@ -17,11 +17,10 @@
 package confluence
 
 import (
-	"log"
-	"os"
 	"time"
 
 	"github.com/abc-inc/heimdall/cli"
+	"github.com/abc-inc/heimdall/errs"
 	"github.com/abc-inc/heimdall/internal"
 	"github.com/rs/zerolog"
 	"github.com/spf13/cobra"
@@ -30,7 +29,7 @@ import (
 
 func NewCreateCmd() *cobra.Command {
 	cfg := confluenceUpdateCfg{
-		confluenceCfg: confluenceCfg{baseURL: os.Getenv("CONFLUENCE_API_URL"), timeout: 30 * time.Second},
+		confluenceCfg: confluenceCfg{timeout: 30 * time.Second},
 		expand:        "content.ancestors,content.body.storage,content.space",
 	}
 
@@ -39,9 +38,7 @@ func NewCreateCmd() *cobra.Command {
 		Short: "Create a Confluence page",
 		Args:  cobra.ExactArgs(1),
 		PreRun: func(cmd *cobra.Command, args []string) {
-			if cfg.token == "" {
-				cfg.token = os.Getenv("CONFLUENCE_TOKEN")
-			}
+			cfg.loadSettings()
 		},
 		Run: func(cmd *cobra.Command, args []string) {
 			if zerolog.GlobalLevel() == zerolog.TraceLevel || zerolog.GlobalLevel() == zerolog.DebugLevel {
@@ -72,12 +69,12 @@ func create(cfg confluenceUpdateCfg) *goconfluence.Content {
 		expand:        cfg.expand,
 	})
 	if s.Size != 1 {
-		log.Fatal("Exactly one page must be found")
+		errs.Abortf(errs.Usage, "exactly one page must be found, but found %d", s.Size)
 	}
 
 	p := s.Results[0]
 
-	api := internal.Must(newClient(cfg.baseURL, cfg.token))
+	api := internal.Must(newClient(cfg.baseURL, cfg.token, cfg.timeout))
 
 	data := &goconfluence.Content{
 		Type:      "page",
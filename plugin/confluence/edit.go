@@ -17,11 +17,10 @@
 package confluence
 
 import (
-	"log"
-	"os"
 	"time"
 
 	"github.com/abc-inc/heimdall/cli"
+	"github.com/abc-inc/heimdall/errs"
 	"github.com/abc-inc/heimdall/internal"
 	"github.com/rs/zerolog"
 	"github.com/spf13/cobra"
@@ -38,7 +37,7 @@ type confluenceUpdateCfg struct {
 
 func NewUpdateCmd() *cobra.Command {
 	cfg := confluenceUpdateCfg{
-		confluenceCfg: confluenceCfg{baseURL: os.Getenv("CONFLUENCE_API_URL"), timeout: 30 * time.Second},
+		confluenceCfg: confluenceCfg{timeout: 30 * time.Second},
 		expand:        "content.ancestors,content.body.storage,content.space,content.version",
 	}
 
@@ -47,9 +46,7 @@ func NewUpdateCmd() *cobra.Command {
 		Short: "Edit an existing Confluence page",
 		Args:  cobra.ExactArgs(1),
 		PreRun: func(cmd *cobra.Command, args []string) {
-			if cfg.token == "" {
-				cfg.token = os.Getenv("CONFLUENCE_TOKEN")
-			}
+			cfg.loadSettings()
 		},
 		Run: func(cmd *cobra.Command, args []string) {
 			if zerolog.GlobalLevel() == zerolog.TraceLevel || zerolog.GlobalLevel() == zerolog.DebugLevel {
@@ -79,7 +76,7 @@ func update(cfg confluenceUpdateCfg) *goconfluence.Content {
 		expand:        cfg.expand,
 	})
 	if s.Size != 1 {
-		log.Fatal("Exactly one page must be found")
+		errs.Abortf(errs.Usage, "exactly one page must be found, but found %d", s.Size)
 	}
 
 	p := s.Results[0]
@@ -87,7 +84,7 @@ func update(cfg confluenceUpdateCfg) *goconfluence.Content {
 		cfg.title = p.Title
 	}
 
-	api := internal.Must(newClient(cfg.baseURL, cfg.token))
+	api := internal.Must(newClient(cfg.baseURL, cfg.token, cfg.timeout))
 	goconfluence.SetDebug(true)
 
 	data := &goconfluence.Content{
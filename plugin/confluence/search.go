@@ -24,6 +24,7 @@ import (
 	"time"
 
 	"github.com/abc-inc/heimdall/cli"
+	"github.com/abc-inc/heimdall/errs"
 	"github.com/abc-inc/heimdall/internal"
 	"github.com/rs/zerolog"
 	"github.com/spf13/cobra"
@@ -41,9 +42,7 @@ type confluenceSearchCfg struct {
 }
 
 func NewSearchCmd() *cobra.Command {
-	cfg := confluenceSearchCfg{confluenceCfg: confluenceCfg{
-		baseURL: os.Getenv("CONFLUENCE_API_URL"),
-		timeout: 30 * time.Second},
+	cfg := confluenceSearchCfg{confluenceCfg: confluenceCfg{timeout: 30 * time.Second},
 		expand: "content.body.storage",
 		limit:  10,
 	}
@@ -53,9 +52,7 @@ func NewSearchCmd() *cobra.Command {
 		Short: "Search for Confluence pages",
 		Args:  cobra.ExactArgs(0),
 		PreRun: func(cmd *cobra.Command, args []string) {
-			if cfg.token == "" {
-				cfg.token = os.Getenv("CONFLUENCE_TOKEN")
-			}
+			cfg.loadSettings()
 		},
 		Run: func(cmd *cobra.Command, args []string) {
 			if zerolog.GlobalLevel() == zerolog.TraceLevel || zerolog.GlobalLevel() == zerolog.DebugLevel {
@@ -89,7 +86,7 @@ func NewSearchCmd() *cobra.Command {
 }
 
 func search(cfg confluenceSearchCfg) *goconfluence.Search {
-	api := internal.Must(newClient(cfg.baseURL, cfg.token))
+	api := internal.Must(newClient(cfg.baseURL, cfg.token, cfg.timeout))
 	s := internal.Must(api.Search(goconfluence.SearchQuery{
 		CQL:    cfg.cql,
 		Limit:  cfg.limit,
@@ -109,18 +106,18 @@ func export(s *goconfluence.Search, cfg confluenceSearchCfg) {
 	case "pdf":
 		exportPDF(cfg, s.Results[0])
 	default:
-		internal.MustOkMsgf(cfg.export, false, "invalid export format: %s", cfg.export)
+		errs.Abortf(errs.Usage, "invalid export format: %s", cfg.export)
 	}
 }
 
 func exportPDF(cfg confluenceSearchCfg, page goconfluence.Results) {
 	u := createPDFExportURL(cfg.baseURL, page.Content.ID)
 	req := internal.Must(http.NewRequest("GET", u, nil))
-	api := internal.Must(newClient(cfg.baseURL, cfg.token))
+	api := internal.Must(newClient(cfg.baseURL, cfg.token, cfg.timeout))
 	data := internal.Must(api.Request(req))
 
 	if cfg.file == "-" {
-		internal.Must(os.Stdout.Write(data))
+		internal.Must(cli.IO.Out.Write(data))
 		return
 	}
 
@@ -135,7 +132,7 @@ func exportHTML(cfg confluenceSearchCfg, page goconfluence.Results) {
 	data := page.Content.Body.View.Value
 
 	if cfg.file == "-" {
-		internal.Must(os.Stdout.WriteString(data))
+		internal.Must(io.WriteString(cli.IO.Out, data))
 		return
 	}
 
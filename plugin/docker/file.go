@@ -47,6 +47,7 @@ func NewFileCmd() *cobra.Command {
 }
 
 type line struct {
+	File      string   `json:"file,omitempty" yaml:"file,omitempty"`             // path of the Dockerfile
 	Cmd       string   `json:"cmd,omitempty" yaml:"cmd,omitempty"`               // lowercase command name, e.g., "from"
 	SubCmd    string   `json:"sub_cmd,omitempty" yaml:"sub_cmd,omitempty"`       // ONBUILD only, holds the sub-command
 	JSON      bool     `json:"json,omitempty" yaml:"json,omitempty"`             // whether the value is written in json form
@@ -61,10 +62,28 @@ func (l line) String() string {
 	return l.Original
 }
 
-func readDockerfile(cfg dockerCfg) []line {
+// lines are the instructions of a Dockerfile.
+type lines []line
+
+// Findings converts the instructions to findings, which refer to the Dockerfile.
+// Since instructions are no problems by themselves, they are notes.
+func (ls lines) Findings() []cli.Finding {
+	fs := make([]cli.Finding, len(ls))
+	for i, l := range ls {
+		fs[i] = cli.Finding{RuleID: l.Cmd, Level: cli.LevelNote, Message: l.Original,
+			Locations: []cli.Location{{File: l.File, StartLine: l.StartLine, EndLine: l.EndLine}}}
+	}
+	return fs
+}
+
+func readDockerfile(cfg dockerCfg) lines {
 	r := internal.Must(res.Open(cfg.file))
 	defer func() { _ = r.Close() }()
-	return parseDockerfile(r)
+	cmds := parseDockerfile(r)
+	for i := range cmds {
+		cmds[i].File = cfg.file
+	}
+	return cmds
 }
 
 func parseDockerfile(r io.Reader) (cmds []line) {
@@ -17,17 +17,31 @@
 package docker
 
 import (
+	"encoding/json"
 	"path/filepath"
 	"strings"
 	"testing"
 
+	"github.com/abc-inc/heimdall/cli"
+	"github.com/abc-inc/heimdall/internal"
 	"github.com/abc-inc/heimdall/test"
 	"github.com/stretchr/testify/require"
 )
 
 func TestParseDockerfile(t *testing.T) {
-	cmds := readDockerfile(dockerCfg{file: filepath.Join(test.GetRootDir(), "testdata", "Dockerfile")})
+	file := filepath.Join(test.GetRootDir(), "testdata", "Dockerfile")
+	cmds := readDockerfile(dockerCfg{file: file})
 	require.Equal(t, 9, len(cmds))
 	require.Equal(t, "FROM", cmds[0].Cmd)
 	require.True(t, strings.HasSuffix(cmds[1].Value[0], "rm -rf /var/lib/apt/lists/*"))
+
+	fs := internal.Must(cli.ToFindings(cmds))
+	require.Equal(t, cli.LevelNote, fs[0].Level)
+	require.Equal(t, cli.Location{File: file, StartLine: 7, EndLine: 7}, fs[0].Locations[0])
+
+	// the location is kept, if the output is filtered, e.g., with --jq
+	var v any
+	internal.MustNoErr(json.Unmarshal(internal.Must(json.Marshal(cmds[:1])), &v))
+	fs = internal.Must(cli.ToFindings(v))
+	require.Equal(t, cli.Location{File: file, StartLine: 7, EndLine: 7}, fs[0].Locations[0])
 }
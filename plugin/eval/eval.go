@@ -18,6 +18,8 @@ package eval
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 	"maps"
 	"net/url"
 	"os"
@@ -30,12 +32,12 @@ import (
 	"github.com/MakeNowJust/heredoc/v2"
 	"github.com/Masterminds/sprig/v3"
 	"github.com/abc-inc/heimdall/cli"
+	"github.com/abc-inc/heimdall/errs"
 	"github.com/abc-inc/heimdall/internal"
 	"github.com/abc-inc/heimdall/plugin/parse"
 	"github.com/abc-inc/heimdall/res"
 	"github.com/gobwas/glob"
 	"github.com/mattn/go-zglob"
-	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
 	"github.com/spf13/cobra"
 )
@@ -73,7 +75,9 @@ func NewEvalCmd() *cobra.Command {
 		Short: "Evaluate the given expression on all input files",
 		Long: heredoc.Doc(`
 			Evaluate the given expression on all input files.
-			The following file formats are supported: csv, json, properties, xml, yaml
+			The following file formats are supported: csv, hcl, json, properties, tf, xml, yaml
+			Files, which do not contain an object, e.g., CSV files or YAML files with multiple documents, are loaded as array.
+			The array is accessible via the alias of the file, e.g., "manifest.yaml:docs".
 		`),
 		Example: heredoc.Doc(`
 			# check whether the filename of the URL matches the given regular expression
@@ -83,6 +87,9 @@ func NewEvalCmd() *cobra.Command {
 			# (if a variable is defined multiple times, the last definition takes precedence)
 			heimdall eval --ignore-missing -e 'sortAlpha(keys(_))' ${GRADLE_USER_HOME:-~/.gradle}/gradle.properties gradle.properties
 
+			# check that every document of a Kubernetes manifest has a namespace
+			heimdall eval -e 'none(docs, #.kind != "Namespace" && #.metadata.namespace == nil)' manifest.yaml:docs
+
 			# first, get the summary of the JaCoCo code coverage report in JSON format
 			# then, feed it into the JavaScript interpreter to calculate coverage ratio
 			# (note the "-::json", which means: take standard input ("-"), use no variable prefix (""), and treat it as json)
@@ -90,8 +97,8 @@ func NewEvalCmd() *cobra.Command {
 			    heimdall eval -E javascript -e 'line_covered / (line_covered + line_missed)' -- -::json
 		`),
 		Args: cobra.MinimumNArgs(0),
-		Run: func(cmd *cobra.Command, args []string) {
-			eval(cfg, args)
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return eval(cfg, args)
 		},
 	}
 
@@ -107,9 +114,9 @@ func NewEvalCmd() *cobra.Command {
 	return cmd
 }
 
-func eval(cfg evalCfg, args []string) {
+func eval(cfg evalCfg, args []string) error {
 	if _, ok := engines[cfg.engine]; !ok {
-		log.Fatal().Msgf(`cannot find engine "%s", must be one of "%s"`,
+		return errs.Newf(errs.Usage, `cannot find engine "%s", must be one of "%s"`,
 			cfg.engine, strings.Join(slices.Sorted(maps.Keys(engines)), `", "`))
 	}
 	if t := os.Getenv("HEIMDALL_TEMPLATE_FILE"); t != "" && cfg.template == "" {
@@ -122,10 +129,11 @@ func eval(cfg evalCfg, args []string) {
 	cfg.files = args
 	result, err := doEval(cfg)
 	if err != nil {
-		log.WithLevel(zerolog.FatalLevel).Err(err).Send()
-		os.Exit(2)
+		return err
 	}
-	if !cfg.quiet {
+	if !cfg.quiet && (cfg.output == "sarif" || cfg.output == "junit") {
+		cli.Fmtln(findings(cfg, result))
+	} else if !cfg.quiet {
 		tmpl := internal.Must(template.New("result").Parse(cfg.template))
 		for _, r := range result {
 			if cfg.template != "" {
@@ -143,15 +151,70 @@ func eval(cfg evalCfg, args []string) {
 			}
 		}
 	}
-	all := strings.Join(result, "\n")
-	if all == "" || all == "0" || all == "false" {
-		os.Exit(1)
+	if !Truthy(result) {
+		return errs.Newf(errs.Violation, "expression evaluated to '%s'", strings.Join(result, "\n"))
+	}
+	return nil
+}
+
+// findings converts the result of every expression to a finding, which refers
+// to all input files.
+func findings(cfg evalCfg, result []string) []cli.Finding {
+	var ls []cli.Location
+	is, _ := ResolveFiles(cfg.files)
+	for _, i := range is {
+		ls = append(ls, cli.Location{File: i.File})
+	}
+
+	var exprs []string
+	for _, e := range cfg.expr {
+		if e = strings.TrimSpace(e); e != "" {
+			exprs = append(exprs, e)
+		}
+	}
+
+	fs := make([]cli.Finding, 0, len(result))
+	for i, r := range result {
+		f := cli.Finding{RuleID: fmt.Sprintf("eval-%d", i+1), Level: cli.LevelNone, Locations: ls}
+		if i < len(exprs) {
+			f.Message = fmt.Sprintf("'%s' evaluated to '%s'", exprs[i], r)
+		} else {
+			f.Message = r
+		}
+		if !Truthy([]string{r}) {
+			f.Level = cli.LevelError
+		}
+		fs = append(fs, f)
+	}
+	return fs
+}
+
+// Evaluate evaluates the expressions with the given engine against the
+// variables loaded from all input files.
+func Evaluate(engine string, exprs []string, inputs ...parse.Input) ([]string, error) {
+	if _, ok := engines[engine]; !ok {
+		return nil, errs.Newf(errs.Usage, `cannot find engine "%s", must be one of "%s"`,
+			engine, strings.Join(slices.Sorted(maps.Keys(engines)), `", "`))
 	}
+	return evalInputs(evalCfg{engine: engine, expr: exprs}, inputs)
+}
+
+// Truthy reports whether the evaluation result counts as success, i.e., it is
+// neither empty, nor "0", nor "false".
+func Truthy(result []string) bool {
+	all := strings.Join(result, "\n")
+	return all != "" && all != "0" && all != "false"
 }
 
 func doEval(cfg evalCfg) ([]string, error) {
-	fs := resolveFiles(cfg.files)
+	fs, err := ResolveFiles(cfg.files)
+	if err != nil {
+		return nil, err
+	}
+	return evalInputs(cfg, fs)
+}
 
+func evalInputs(cfg evalCfg, fs []parse.Input) ([]string, error) {
 	envMap := make(map[string]any)
 	for _, f := range fs {
 		if cfg.ignMiss && f.File != "-" {
@@ -159,7 +222,9 @@ func doEval(cfg evalCfg) ([]string, error) {
 				continue
 			}
 		}
-		load(f, envMap)
+		if err := load(f, envMap); err != nil {
+			return nil, err
+		}
 	}
 	if cfg.verbose {
 		v := internal.Must(json.Marshal(envMap))
@@ -174,14 +239,21 @@ func doEval(cfg evalCfg) ([]string, error) {
 	internal.MustNoErr(e.addFunc(map[string]any{"urlEncode": urlEncode, "urlDecode": urlDecode}))
 	internal.MustNoErr(e.addFunc(sprig.GenericFuncMap()))
 
-	return e.eval(cfg, envMap)
+	// Engines tell syntax errors (usage) from failures to evaluate the data (input).
+	vs, err := e.eval(cfg, envMap)
+	if err != nil && !errors.As(err, new(*errs.Error)) {
+		err = errs.New(errs.Input, err)
+	}
+	return vs, err
 }
 
 func urlDecode(str string) string { s, _ := url.QueryUnescape(str); return s }
 
 func urlEncode(str string) string { return url.QueryEscape(str) }
 
-func resolveFiles(fs []string) (list []parse.Input) {
+// ResolveFiles expands the glob patterns of the given file arguments, which may
+// carry an alias and a type (e.g., "build/*.json:b:json"), into inputs.
+func ResolveFiles(fs []string) (list []parse.Input, err error) {
 	for _, f := range fs {
 		n, post, _ := strings.Cut(f, ":")
 		if n == "-" {
@@ -190,28 +262,37 @@ func resolveFiles(fs []string) (list []parse.Input) {
 		}
 		log.Debug().Str("glob", n).Msg("Resolving files")
 		gs, err := zglob.Glob(n)
-		internal.MustOkMsgf(gs, err == nil, "cannot resolve any files matching glob '%s'", n)
-		for _, g := range internal.Must(gs, err) {
+		if err != nil {
+			return nil, errs.Newf(errs.Input, "cannot resolve any files matching glob '%s': %w", n, err)
+		}
+		for _, g := range gs {
 			list = append(list, parse.SplitNamePrefixType(g+":"+post))
 		}
 	}
-	return list
+	return list, nil
 }
 
-func load(i parse.Input, envMap map[string]any) {
+func load(i parse.Input, envMap map[string]any) error {
 	log.Debug().Str("file", i.File).Msg("Loading")
-	r := internal.Must(res.Open(i.File))
+	r, err := res.Open(i.File)
+	if err != nil {
+		return err
+	}
 	defer func() { _ = r.Close() }()
 
 	if d, ok := parse.Decoders[i.Type]; ok {
 		log.Debug().Str("type", i.Type).Msg("Using decoder")
-		v := internal.Must[any](d(r))
+		v, err := d(r)
+		if err != nil {
+			return errs.Newf(errs.Input, "cannot decode '%s': %w", i.File, err)
+		}
 		if reflect.TypeOf(v).Kind() == reflect.Map {
 			merge(envMap, i.Alias, v.(map[string]any))
 		} else {
 			envMap[i.Alias] = v
 		}
 	}
+	return nil
 }
 
 func merge(envMap map[string]any, alias string, varMap map[string]any) {
@@ -18,6 +18,7 @@ import (
 	"path/filepath"
 	"testing"
 
+	"github.com/abc-inc/heimdall/errs"
 	"github.com/abc-inc/heimdall/internal"
 	"github.com/abc-inc/heimdall/plugin/eval"
 	"github.com/abc-inc/heimdall/test"
@@ -47,3 +48,38 @@ func TestNewEvalCmdTemplate(t *testing.T) {
 	got := test.Run(``, cmd, []string{})
 	require.Equal(t, "A", got)
 }
+
+func TestNewEvalCmdErrors(t *testing.T) {
+	cmd := eval.NewEvalCmd()
+	internal.MustNoErr(cmd.Flags().Set("expression", `1 > 2`))
+	require.ErrorIs(t, cmd.RunE(cmd, []string{}), errs.ErrViolation)
+
+	internal.MustNoErr(cmd.Flags().Set("engine", "unknown"))
+	require.ErrorIs(t, cmd.RunE(cmd, []string{}), errs.ErrUsage)
+
+	internal.MustNoErr(cmd.Flags().Set("engine", "expr"))
+	require.ErrorIs(t, cmd.RunE(cmd, []string{"does-not-exist.json"}), errs.ErrInput)
+
+	// syntax errors are usage errors, but failures to evaluate the data are input errors
+	tests := map[string][2]string{
+		"expr":       {`1 +`, `[1][5]`},
+		"javascript": {`1 +`, `a.b.c + 1`},
+		"template":   {`{{ .a `, `{{ index .a 1 }}`},
+	}
+	for engine, exprs := range tests {
+		for i, want := range []error{errs.ErrUsage, errs.ErrInput} {
+			cmd = eval.NewEvalCmd()
+			internal.MustNoErr(cmd.Flags().Set("engine", engine))
+			internal.MustNoErr(cmd.Flags().Set("expression", exprs[i]))
+			require.ErrorIs(t, cmd.RunE(cmd, []string{}), want, engine+": "+exprs[i])
+		}
+	}
+}
+
+func TestNewEvalCmdMultiDocYAML(t *testing.T) {
+	cmd := eval.NewEvalCmd()
+	internal.MustNoErr(cmd.Flags().Set("engine", "expr"))
+	internal.MustNoErr(cmd.Flags().Set("expression", `join(",", map(docs, #.kind))`))
+	got := test.Run(``, cmd, []string{filepath.Join(test.GetRootDir(), "testdata", "k8s", "app.yaml:docs")})
+	require.Equal(t, "Namespace,Deployment,Service,CronJob", got)
+}
@@ -20,6 +20,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/abc-inc/heimdall/errs"
 	"github.com/abc-inc/heimdall/internal"
 	"github.com/expr-lang/expr"
 	"github.com/expr-lang/expr/conf"
@@ -46,8 +47,14 @@ func (e *exprEngine) eval(cfg evalCfg, envMap map[string]any) (res []string, err
 		}
 
 		e.AddOpt(AllowUndefinedVariables(!strings.Contains(str, "??")))
-		prg := internal.Must(expr.Compile(str, e.opts...))
-		out := internal.Must(expr.Run(prg, e.funcMap))
+		prg, err := expr.Compile(str, e.opts...)
+		if err != nil {
+			return res, errs.New(errs.Usage, err)
+		}
+		out, err := expr.Run(prg, e.funcMap)
+		if err != nil {
+			return res, errs.New(errs.Input, err)
+		}
 		res = append(res, fmt.Sprint(out))
 	}
 	return
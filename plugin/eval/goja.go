@@ -19,6 +19,7 @@ package eval
 import (
 	"fmt"
 
+	"github.com/abc-inc/heimdall/errs"
 	"github.com/abc-inc/heimdall/internal"
 	"github.com/dop251/goja"
 )
@@ -34,10 +35,13 @@ func newGojaEngine() engine {
 func (e *gojaEngine) eval(cfg evalCfg, envMap map[string]any) (res []string, err error) {
 	internal.MustNoErr(e.addFunc(envMap))
 	for _, str := range cfg.expr {
-		var v goja.Value
-		v, err = e.vm.RunString(str)
+		p, err := goja.Compile("", str, false)
 		if err != nil {
-			return res, err
+			return res, errs.New(errs.Usage, err)
+		}
+		v, err := e.vm.RunProgram(p)
+		if err != nil {
+			return res, errs.New(errs.Input, err)
 		}
 		res = append(res, fmt.Sprint(v.Export()))
 	}
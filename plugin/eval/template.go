@@ -21,6 +21,7 @@ import (
 	"strings"
 	"text/template"
 
+	"github.com/abc-inc/heimdall/errs"
 	"github.com/abc-inc/heimdall/internal"
 )
 
@@ -35,9 +36,13 @@ func newTmplEngine() engine {
 func (e *tmplEngine) eval(cfg evalCfg, envMap map[string]any) (res []string, err error) {
 	internal.MustNoErr(e.addFunc(envMap))
 	for _, str := range cfg.expr {
-		e.tmpl = internal.Must(e.tmpl.Parse(str))
+		if e.tmpl, err = e.tmpl.Parse(str); err != nil {
+			return res, errs.New(errs.Usage, err)
+		}
 		w := strings.Builder{}
-		internal.MustNoErr(e.tmpl.Execute(&w, envMap))
+		if err = e.tmpl.Execute(&w, envMap); err != nil {
+			return res, errs.New(errs.Input, err)
+		}
 		res = append(res, w.String())
 	}
 	return
@@ -19,13 +19,13 @@ package example
 import (
 	"fmt"
 	"io/fs"
-	"log"
 	"path/filepath"
 	"strings"
 
 	"github.com/MakeNowJust/heredoc/v2"
 	"github.com/abc-inc/heimdall"
 	"github.com/abc-inc/heimdall/cli"
+	"github.com/abc-inc/heimdall/errs"
 	"github.com/abc-inc/heimdall/internal"
 	"github.com/alecthomas/chroma/v2/styles"
 	"github.com/spf13/cobra"
@@ -74,7 +74,7 @@ func render(cfg exampleCfg, cmd *cobra.Command, args []string) {
 
 	es := internal.Must(fs.Glob(heimdall.StaticFS, "docs/examples/"+cfg.name+".yaml"))
 	if len(es) == 0 {
-		log.Fatalf("Example '%s' does not exist.", cfg.name)
+		errs.Abortf(errs.Usage, "example '%s' does not exist", cfg.name)
 	} else if cfg.list {
 		for i := range es {
 			es[i] = filepath.Base(strings.TrimSuffix(es[i], filepath.Ext(es[i])))
@@ -83,7 +83,7 @@ func render(cfg exampleCfg, cmd *cobra.Command, args []string) {
 		return
 	}
 	if len(es) > 1 {
-		log.Fatalf("Multiple examples found for '%s'.", cfg.name)
+		errs.Abortf(errs.Usage, "multiple examples found for '%s'", cfg.name)
 		return
 	}
 
@@ -0,0 +1,256 @@
+// Copyright 2025 The Heimdall authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !no_external
+
+// Package external runs executables named heimdall-<name> as subcommands, which
+// extend Heimdall without forking it.
+package external
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/abc-inc/heimdall/cli"
+	"github.com/abc-inc/heimdall/errs"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// Prefix is the prefix of the names of plugin executables.
+const Prefix = "heimdall-"
+
+// pathAnnotation is the annotation of plugin commands holding the executable.
+const pathAnnotation = "plugin:path"
+
+type pluginCfg struct {
+	cli.OutCfg
+	path     string
+	services []string
+}
+
+// Discover returns the plugin executables in the directories by name.
+// If the same name is found more than once, the first one wins.
+func Discover(dirs ...string) map[string]string {
+	self, _ := os.Executable()
+	ps := map[string]string{}
+	for _, d := range dirs {
+		es, err := os.ReadDir(d)
+		if err != nil {
+			continue
+		}
+		for _, e := range es {
+			n, ok := strings.CutPrefix(e.Name(), Prefix)
+			if runtime.GOOS == "windows" {
+				n = strings.TrimSuffix(n, ".exe")
+			}
+			if _, dup := ps[n]; !ok || dup || n == "" || e.IsDir() {
+				continue
+			}
+			p := filepath.Join(d, e.Name())
+			if !isExecutable(p) || isSame(p, self) {
+				continue
+			}
+			ps[n] = p
+		}
+	}
+	return ps
+}
+
+// NewPluginCmds returns the commands for the plugins in the directories,
+// unless the parent already has a command with the same name.
+func NewPluginCmds(parent *cobra.Command, dirs ...string) (cmds []*cobra.Command) {
+	ps := Discover(dirs...)
+	for _, n := range slices.Sorted(maps.Keys(ps)) {
+		if c, _, err := parent.Find([]string{n}); err == nil && c != parent {
+			continue // built-in commands take precedence
+		}
+		cmds = append(cmds, NewPluginCmd(n, ps[n]))
+	}
+	return cmds
+}
+
+func NewPluginCmd(name, path string) *cobra.Command {
+	cfg := pluginCfg{path: path}
+	cmd := &cobra.Command{
+		Use:   name + " [flags] [--] [<plugin args>...]",
+		Short: "Run the plugin " + path,
+		Long: "Run the plugin " + path + ".\n\n" +
+			"Flags of Heimdall are consumed, all other arguments are passed on to the plugin.\n" +
+			"Arguments after '--' are passed on as they are.\n" +
+			"The URLs and tokens of the services given by --services are passed on as environment variables.\n" +
+			"If the plugin writes JSON, it is formatted according to the output flags.",
+		GroupID:     cli.PluginGroup,
+		Args:        cobra.ArbitraryArgs,
+		Annotations: map[string]string{pathAnnotation: path},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return run(cfg, args)
+		},
+	}
+
+	cmd.Flags().StringSliceVar(&cfg.services, "services", cfg.services, "Pass the URLs and tokens of these services on to the plugin, e.g., github,jira")
+	cli.AddOutputFlags(cmd, &cfg.OutCfg)
+	return cmd
+}
+
+// Args rearranges the command line, if it runs a plugin. The arguments, which
+// are not flags of Heimdall, are moved behind "--", so that they are passed on
+// to the plugin instead of being rejected as unknown flags.
+func Args(root *cobra.Command, args []string) []string {
+	cmd, rest, err := root.Find(args[1:])
+	if err != nil || cmd.Annotations[pathAnnotation] == "" {
+		return args
+	}
+
+	_ = cmd.InheritedFlags() // merge persistent flags of the parents
+	own, pass := splitArgs(cmd.Flags(), rest)
+	out := append([]string{args[0], cmd.Name()}, own...)
+	return append(append(out, "--"), pass...)
+}
+
+// splitArgs separates the flags in fs (and their values) from other arguments.
+func splitArgs(fs *pflag.FlagSet, args []string) (own, pass []string) {
+	for i := 0; i < len(args); i++ {
+		a := args[i]
+		if a == "--" {
+			return own, append(pass, args[i+1:]...)
+		}
+
+		var f *pflag.Flag
+		n, _, hasVal := strings.Cut(strings.TrimLeft(a, "-"), "=")
+		if strings.HasPrefix(a, "--") {
+			f = fs.Lookup(n)
+		} else if strings.HasPrefix(a, "-") && len(n) == 1 {
+			f = fs.ShorthandLookup(n)
+		}
+		if f == nil {
+			pass = append(pass, a)
+			continue
+		}
+
+		own = append(own, a)
+		if !hasVal && f.NoOptDefVal == "" && i+1 < len(args) {
+			i++
+			own = append(own, args[i])
+		}
+	}
+	return own, pass
+}
+
+// run executes the plugin with the settings of Heimdall in the environment.
+// JSON output is formatted like the output of built-in commands, any other
+// output is written as it is.
+func run(cfg pluginCfg, args []string) error {
+	e, err := env(cfg)
+	if err != nil {
+		return err
+	}
+
+	stdout := &bytes.Buffer{}
+	c := exec.CommandContext(cli.Context(), cfg.path, args...)
+	c.Stdin, c.Stdout, c.Stderr = cli.IO.In, stdout, cli.IO.ErrOut
+	c.Env = append(os.Environ(), e...)
+
+	log.Debug().Str("plugin", cfg.path).Strs("args", args).Msg("Running plugin")
+	runErr := c.Run()
+
+	var a any
+	if b := bytes.TrimSpace(stdout.Bytes()); len(b) > 0 && json.Valid(b) {
+		d := json.NewDecoder(bytes.NewReader(b))
+		d.UseNumber()
+		if err := d.Decode(&a); err != nil {
+			return errs.New(errs.Failure, err)
+		}
+		cli.Fmtln(a)
+	} else if _, err := cli.IO.Out.Write(stdout.Bytes()); err != nil {
+		return err
+	}
+
+	var exitErr *exec.ExitError
+	if errors.As(runErr, &exitErr) {
+		// Plugins are expected to use the same exit codes as Heimdall.
+		k := errs.Kind(exitErr.ExitCode())
+		if k < errs.Violation || k > errs.Failure {
+			k = errs.Failure
+		}
+		return errs.Newf(k, "plugin %s exited with code %d", filepath.Base(cfg.path), exitErr.ExitCode())
+	}
+	return errs.New(errs.Failure, runErr)
+}
+
+// env returns the environment variables, which pass the output flags, the
+// active profile and the credentials of the requested services on to the
+// plugin. Tokens, which cannot be resolved, are skipped.
+func env(cfg pluginCfg) ([]string, error) {
+	e := []string{
+		"HEIMDALL_OUTPUT=" + cfg.Output,
+		"HEIMDALL_PRETTY=" + strconv.FormatBool(cfg.Pretty),
+		"HEIMDALL_QUERY=" + cfg.Query,
+		"HEIMDALL_JQ=" + cfg.JQFilter,
+		"HEIMDALL_PROFILE=" + cli.Profile(),
+		"HEIMDALL_VERSION=" + cli.Version,
+	}
+	for _, s := range cfg.services {
+		if !slices.Contains(cli.Services(), s) {
+			return nil, errs.Newf(errs.Usage, "unknown service '%s' (supported: %s)", s, strings.Join(cli.Services(), ", "))
+		}
+		p := "HEIMDALL_" + strings.ToUpper(s)
+		if u := cli.ProfileString(s, "url"); u != "" {
+			e = append(e, fmt.Sprintf("%s_URL=%s", p, u))
+		}
+		if t, err := token(s); err != nil {
+			log.Warn().Err(err).Str("service", s).Msg("Cannot resolve token for plugin")
+		} else if t != "" {
+			e = append(e, fmt.Sprintf("%s_TOKEN=%s", p, t))
+		}
+	}
+	return e, nil
+}
+
+// token resolves the token of the service like cli.Token, but returns an error
+// instead of aborting, e.g., if the credential helper fails.
+func token(service string) (t string, err error) {
+	defer errs.Recover(&err)
+	return cli.Token(service), nil
+}
+
+func isExecutable(path string) bool {
+	fi, err := os.Stat(path)
+	if err != nil || fi.IsDir() {
+		return false
+	}
+	return runtime.GOOS == "windows" || fi.Mode().Perm()&0111 != 0
+}
+
+// isSame reports whether both paths refer to the same file, e.g., a symlink
+// named heimdall-<name>, which would run Heimdall itself.
+func isSame(path, other string) bool {
+	fi, err := os.Stat(path)
+	if err != nil || other == "" {
+		return false
+	}
+	fo, err := os.Stat(other)
+	return err == nil && os.SameFile(fi, fo)
+}
@@ -0,0 +1,109 @@
+// Copyright 2025 The Heimdall authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !no_external
+
+package external
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/abc-inc/heimdall/cli"
+	"github.com/abc-inc/heimdall/errs"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+	"github.com/zalando/go-keyring"
+)
+
+func TestDiscover(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("plugins are shell scripts")
+	}
+	d1, d2 := t.TempDir(), t.TempDir()
+	writeScript(t, d1, "heimdall-hello", "echo 1")
+	writeScript(t, d2, "heimdall-hello", "echo 2")
+	writeScript(t, d2, "heimdall-version", "echo 3")
+	require.NoError(t, os.WriteFile(filepath.Join(d2, "heimdall-data"), nil, 0600))
+
+	ps := Discover(d1, d2)
+	require.Equal(t, map[string]string{
+		"hello":   filepath.Join(d1, "heimdall-hello"),
+		"version": filepath.Join(d2, "heimdall-version"),
+	}, ps)
+
+	root := &cobra.Command{Use: "heimdall"}
+	root.AddCommand(&cobra.Command{Use: "version"})
+	cmds := NewPluginCmds(root, d1, d2)
+	require.Len(t, cmds, 1)
+	require.Equal(t, "hello", cmds[0].Name())
+}
+
+func TestArgs(t *testing.T) {
+	root := &cobra.Command{Use: "heimdall"}
+	root.PersistentFlags().String("profile", "", "")
+	root.AddCommand(NewPluginCmd("hello", "/bin/heimdall-hello"))
+
+	args := Args(root, []string{"heimdall", "--profile", "p", "hello", "-x", "--jq=.a", "-o", "yaml", "a", "--", "--jq"})
+	require.Equal(t, []string{"heimdall", "hello", "--profile", "p", "--jq=.a", "-o", "yaml", "--", "-x", "a", "--jq"}, args)
+
+	args = []string{"heimdall", "version", "-x"}
+	require.Equal(t, args, Args(root, args))
+}
+
+func TestRun(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("plugins are shell scripts")
+	}
+	d := t.TempDir()
+	writeScript(t, d, "heimdall-hello", `echo "{\"args\": \"$*\", \"output\": \"$HEIMDALL_OUTPUT\"}"; exit 1`)
+
+	io, _, out, _ := cli.Test()
+	cli.IO, io = io, cli.IO
+	defer func() { cli.IO = io }()
+
+	err := run(pluginCfg{OutCfg: cli.OutCfg{Output: "json"}, path: filepath.Join(d, "heimdall-hello")}, []string{"a", "b"})
+	require.ErrorIs(t, err, errs.ErrViolation)
+	require.JSONEq(t, `{"args": "a b", "output": "json"}`, out.String())
+}
+
+func TestEnv(t *testing.T) {
+	keyring.MockInit()
+	cli.AddTokenFlag(&cobra.Command{}, "exttoken", "EXTTOKEN_TOKEN")
+	cli.AddTokenFlag(&cobra.Command{}, "exthelper")
+	t.Setenv("EXTTOKEN_TOKEN", "secret")
+	viper.Set("credential-helper", "false")
+	defer viper.Reset()
+
+	e, err := env(pluginCfg{})
+	require.NoError(t, err)
+	require.NotContains(t, e, "HEIMDALL_EXTTOKEN_TOKEN=secret")
+
+	// the failing credential helper of one service does not abort the plugin
+	e, err = env(pluginCfg{services: []string{"exttoken", "exthelper"}})
+	require.NoError(t, err)
+	require.Contains(t, e, "HEIMDALL_EXTTOKEN_TOKEN=secret")
+	require.NotContains(t, strings.Join(e, "\n"), "HEIMDALL_EXTHELPER_TOKEN")
+
+	_, err = env(pluginCfg{services: []string{"unknown"}})
+	require.ErrorIs(t, err, errs.ErrUsage)
+}
+
+func writeScript(t *testing.T, dir, name, body string) {
+	require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte("#!/bin/sh\n"+body+"\n"), 0700))
+}
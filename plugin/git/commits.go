@@ -22,6 +22,7 @@ import (
 
 	"github.com/MakeNowJust/heredoc/v2"
 	"github.com/abc-inc/heimdall/cli"
+	"github.com/abc-inc/heimdall/errs"
 	"github.com/abc-inc/heimdall/internal"
 	"github.com/abc-inc/heimdall/res"
 	"github.com/go-git/go-git/v5"
@@ -53,13 +54,13 @@ func NewCommitsCmd() *cobra.Command {
 			heimdall git commits --merge-base --start-ref release/3.14
 		`),
 		Args: cobra.MaximumNArgs(1),
-		PreRun: func(cmd *cobra.Command, args []string) {
+		PreRunE: func(cmd *cobra.Command, args []string) error {
 			if len(args) > 0 && res.IsURL(args[0]) {
 				if cfg.user = os.Getenv("GIT_USERNAME"); cfg.user == "" {
-					log.Fatal().Str("name", "GIT_USERNAME").Msg("undefined environment variable")
+					return errs.Newf(errs.Auth, "undefined environment variable: %s", "GIT_USERNAME")
 				}
 				if cfg.pass = os.Getenv("GIT_PASSWORD"); cfg.pass == "" {
-					log.Fatal().Str("name", "GIT_PASSWORD").Msg("undefined environment variable")
+					return errs.Newf(errs.Auth, "undefined environment variable: %s", "GIT_PASSWORD")
 				}
 			}
 
@@ -68,6 +69,7 @@ func NewCommitsCmd() *cobra.Command {
 			} else {
 				cfg.repo = internal.Must(os.Getwd())
 			}
+			return nil
 		},
 		Run: func(cmd *cobra.Command, args []string) {
 			cli.Fmtln(listCommits(cfg))
@@ -95,7 +97,7 @@ func listCommits(cfg gitCfg) []*Commit {
 func open(uri, user, pass string) (*git.Repository, error) {
 	if strings.HasPrefix(uri, "https://") {
 		log.Info().Str("URL", uri).Msg("Cloning Git repository")
-		return git.Clone(memory.NewStorage(), nil, &git.CloneOptions{
+		return git.CloneContext(cli.Context(), memory.NewStorage(), nil, &git.CloneOptions{
 			URL:  uri,
 			Auth: &http.BasicAuth{Username: user, Password: pass},
 		})
@@ -139,7 +141,7 @@ func resolve(r *git.Repository, revOrHash string) (h plumbing.Hash) {
 		}))
 		if h.IsZero() {
 			iter.Close()
-			log.Fatal().Err(err).Str("rev", revOrHash).Msg("Failed to resolve hash")
+			errs.Abortf(errs.Input, "cannot resolve revision '%s'", revOrHash)
 		}
 	} else {
 		h = *internal.Must(rev, err)
@@ -152,7 +154,7 @@ func loadCommits(r *git.Repository, startHash, endHash plumbing.Hash) []*Commit
 	start := internal.Must(r.CommitObject(startHash))
 	end := internal.Must(r.CommitObject(endHash))
 	if ok, err := start.IsAncestor(end); err != nil || !ok {
-		log.Fatal().Stringer("startHash", startHash).Stringer("endHash", endHash).Msg("Start commit is not an ancestor of end commit")
+		errs.Abortf(errs.Input, "start commit %s is not an ancestor of end commit %s", startHash, endHash)
 	}
 
 	log.Debug().Stringer("start-ref", startHash).Stringer("end-ref", endHash).Msg("Loading commits")
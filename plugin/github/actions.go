@@ -0,0 +1,249 @@
+// Copyright 2023 The Heimdall authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !no_github
+
+//go:generate go run github.com/abc-inc/heimdall/tools/cmddoc github.com/google/go-github/v69@v69.2.0/github/actions_\*.go ../../docs
+
+package github
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"reflect"
+
+	"github.com/MakeNowJust/heredoc/v2"
+	"github.com/abc-inc/heimdall/cli"
+	"github.com/abc-inc/heimdall/errs"
+	"github.com/abc-inc/heimdall/internal"
+	"github.com/abc-inc/heimdall/res"
+	"github.com/google/go-github/v69/github"
+	"github.com/spf13/cobra"
+)
+
+// maxRedirects is the number of redirects to follow when resolving download URLs.
+const maxRedirects = 3
+
+// actionsMethods lists the methods of the ActionsService, which are exposed as subcommands.
+var actionsMethods = []string{
+	"DownloadArtifact",
+	"GetArtifact",
+	"GetWorkflowByFileName",
+	"GetWorkflowByID",
+	"GetWorkflowJobByID",
+	"GetWorkflowJobLogs",
+	"GetWorkflowRunByID",
+	"GetWorkflowRunLogs",
+	"ListArtifacts",
+	"ListRepositoryWorkflowRuns",
+	"ListWorkflowJobs",
+	"ListWorkflowRunArtifacts",
+	"ListWorkflowRunsByFileName",
+	"ListWorkflows",
+}
+
+func NewActionsCmd() *cobra.Command {
+	var branch string
+	cfg := newGHCfg()
+	cfg.branch = &branch
+
+	cmd := &cobra.Command{
+		Use:   "actions",
+		Short: "Handles communication with the actions related methods.",
+		Example: heredoc.Doc(`
+			# list successful runs of the release workflow on the main branch
+			heimdall github actions workflow-runs-by-file-name --file-name release.yml --branch main --status success
+
+			# list the jobs and steps of a workflow run
+			heimdall github actions workflow-jobs --run-id 123456789
+
+			# download the logs of a workflow run
+			heimdall github actions workflow-run-logs --run-id 123456789 --dest logs.zip
+		`),
+		Args: cobra.ExactArgs(0),
+	}
+
+	ops := []string{
+		"artifact",
+		"artifacts",
+		"download-artifact",
+		"repository-workflow-runs",
+		"workflow-by-file-name",
+		"workflow-by-id",
+		"workflow-job-by-id",
+		"workflow-job-logs",
+		"workflow-jobs",
+		"workflow-run-artifacts",
+		"workflow-run-by-id",
+		"workflow-run-logs",
+		"workflow-runs-by-file-name",
+		"workflows",
+	}
+
+	svcTyp := reflect.TypeOf(&github.ActionsService{})
+	cmd.AddCommand(createCmds(cfg, svcTyp, execActions, ops)...)
+	for _, sub := range cmd.Commands() {
+		addRepoFlags(cfg, sub)
+
+		switch sub.Name() {
+		case "artifact", "workflow-by-id", "workflow-job-by-id", "workflow-run-by-id":
+			addItemFlags(cfg, sub)
+		case "artifacts":
+			sub.Flags().StringVar(&cfg.name, "name", cfg.name, "Only return artifacts with this name")
+		case "download-artifact", "workflow-job-logs":
+			addItemFlags(cfg, sub)
+			addDestFlag(cfg, sub)
+		case "repository-workflow-runs", "workflow-runs-by-file-name":
+			sub.Flags().StringVar(&cfg.actor, "actor", cfg.actor, "Only return runs triggered by this user")
+			sub.Flags().StringVar(cfg.branch, "branch", *cfg.branch, "Only return runs associated with this branch")
+			sub.Flags().StringVar(&cfg.event, "event", cfg.event, "Only return runs triggered by this event, e.g., push or release")
+			sub.Flags().StringVar(&cfg.status, "status", cfg.status, "Only return runs with this status or conclusion, e.g., completed or success")
+			sub.Flags().StringVar(&cfg.created, "created", cfg.created, "Only return runs created within this date range, e.g., >=2025-01-01")
+			sub.Flags().StringVar(&cfg.sha, "head-sha", cfg.sha, "Only return runs associated with this head commit SHA")
+			sub.Flags().BoolVar(&cfg.excludePRs, "exclude-pull-requests", cfg.excludePRs, "Omit pull requests from the response")
+		case "workflow-jobs":
+			addRunIDFlag(cfg, sub)
+			sub.Flags().StringVar(&cfg.filter, "filter", "latest", "Filter jobs by their completed_at timestamp (latest, all)")
+		case "workflow-run-artifacts":
+			addRunIDFlag(cfg, sub)
+		case "workflow-run-logs":
+			addRunIDFlag(cfg, sub)
+			addDestFlag(cfg, sub)
+		}
+
+		if sub.Name() == "workflow-by-file-name" || sub.Name() == "workflow-runs-by-file-name" {
+			sub.Flags().StringVar(&cfg.workflowFileName, "file-name", cfg.workflowFileName, "File name of the workflow, e.g., release.yml")
+			internal.MustNoErr(sub.MarkFlagRequired("file-name"))
+		}
+	}
+
+	cli.AddOutputFlags(cmd, &cfg.OutCfg)
+	return cmd
+}
+
+func addRunIDFlag(cfg *ghCfg, cmd *cobra.Command) {
+	cmd.Flags().Int64Var(&cfg.runID, "run-id", cfg.runID, "Unique ID of the workflow run")
+	internal.MustNoErr(cmd.MarkFlagRequired("run-id"))
+}
+
+func addDestFlag(cfg *ghCfg, cmd *cobra.Command) {
+	cmd.Flags().StringVar(&cfg.dest, "dest", cfg.dest, "Download the archive to this file instead of printing its URL")
+}
+
+func execActions(cfg *ghCfg, cmd *cobra.Command) (a any, err error) {
+	setHostOwnerRepo(cfg, cfg.host, cfg.owner, cfg.repo)
+	cfg.client = newClient()
+	svc := cfg.client.Actions
+
+	var u *url.URL
+	switch cmd.Name() {
+	case "artifact":
+		a, cfg.resp, err = svc.GetArtifact(getCtx(cfg), cfg.owner, cfg.repo, cfg.id)
+	case "artifacts":
+		var name *string
+		if cfg.name != "" {
+			name = &cfg.name
+		}
+		a, cfg.resp, err = svc.ListArtifacts(getCtx(cfg), cfg.owner, cfg.repo, &github.ListArtifactsOptions{
+			Name:        name,
+			ListOptions: github.ListOptions{Page: cfg.page, PerPage: cfg.perPage},
+		})
+	case "download-artifact":
+		u, cfg.resp, err = svc.DownloadArtifact(getCtx(cfg), cfg.owner, cfg.repo, cfg.id, maxRedirects)
+	case "repository-workflow-runs":
+		a, cfg.resp, err = svc.ListRepositoryWorkflowRuns(getCtx(cfg), cfg.owner, cfg.repo, workflowRunsOpts(cfg))
+	case "workflow-by-file-name":
+		a, cfg.resp, err = svc.GetWorkflowByFileName(getCtx(cfg), cfg.owner, cfg.repo, cfg.workflowFileName)
+	case "workflow-by-id":
+		a, cfg.resp, err = svc.GetWorkflowByID(getCtx(cfg), cfg.owner, cfg.repo, cfg.id)
+	case "workflow-job-by-id":
+		a, cfg.resp, err = svc.GetWorkflowJobByID(getCtx(cfg), cfg.owner, cfg.repo, cfg.id)
+	case "workflow-job-logs":
+		u, cfg.resp, err = svc.GetWorkflowJobLogs(getCtx(cfg), cfg.owner, cfg.repo, cfg.id, maxRedirects)
+	case "workflow-jobs":
+		a, cfg.resp, err = svc.ListWorkflowJobs(getCtx(cfg), cfg.owner, cfg.repo, cfg.runID, &github.ListWorkflowJobsOptions{
+			Filter:      cfg.filter,
+			ListOptions: github.ListOptions{Page: cfg.page, PerPage: cfg.perPage},
+		})
+	case "workflow-run-artifacts":
+		a, cfg.resp, err = svc.ListWorkflowRunArtifacts(getCtx(cfg), cfg.owner, cfg.repo, cfg.runID,
+			&github.ListOptions{Page: cfg.page, PerPage: cfg.perPage})
+	case "workflow-run-by-id":
+		a, cfg.resp, err = svc.GetWorkflowRunByID(getCtx(cfg), cfg.owner, cfg.repo, cfg.id)
+	case "workflow-run-logs":
+		u, cfg.resp, err = svc.GetWorkflowRunLogs(getCtx(cfg), cfg.owner, cfg.repo, cfg.runID, maxRedirects)
+	case "workflow-runs-by-file-name":
+		a, cfg.resp, err = svc.ListWorkflowRunsByFileName(getCtx(cfg), cfg.owner, cfg.repo, cfg.workflowFileName, workflowRunsOpts(cfg))
+	case "workflows":
+		a, cfg.resp, err = svc.ListWorkflows(getCtx(cfg), cfg.owner, cfg.repo,
+			&github.ListOptions{Page: cfg.page, PerPage: cfg.perPage})
+	default:
+		panic("Unsupported operation: " + cmd.Name())
+	}
+
+	if u == nil || err != nil {
+		return a, err
+	} else if cfg.dest == "" {
+		return u.String(), nil
+	}
+	return download(cfg, u)
+}
+
+func workflowRunsOpts(cfg *ghCfg) *github.ListWorkflowRunsOptions {
+	return &github.ListWorkflowRunsOptions{
+		Actor:               cfg.actor,
+		Branch:              defVal(cfg.branch),
+		Event:               cfg.event,
+		Status:              cfg.status,
+		Created:             cfg.created,
+		HeadSHA:             cfg.sha,
+		ExcludePullRequests: cfg.excludePRs,
+		ListOptions:         github.ListOptions{Page: cfg.page, PerPage: cfg.perPage},
+	}
+}
+
+// download fetches the archive from the (pre-signed) URL, stores it in cfg.dest
+// and returns the location, size and SHA-256 digest of the file.
+func download(cfg *ghCfg, u *url.URL) (any, error) {
+	req, err := http.NewRequestWithContext(getCtx(cfg), http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := (&http.Client{Transport: res.Transport(nil)}).Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return nil, errs.FromStatus(resp.StatusCode, fmt.Errorf("cannot download %s: %s", u.Redacted(), resp.Status))
+	}
+
+	f, err := os.Create(cfg.dest)
+	if err != nil {
+		return nil, errs.New(errs.Failure, err)
+	}
+	defer func() { _ = f.Close() }()
+
+	h := sha256.New()
+	n, err := io.Copy(io.MultiWriter(f, h), resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]any{"file": cfg.dest, "size": n, "sha256": hex.EncodeToString(h.Sum(nil))}, f.Close()
+}
@@ -0,0 +1,67 @@
+// Copyright 2023 The Heimdall authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !no_github
+
+package github
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/abc-inc/heimdall/internal"
+	"github.com/google/go-github/v69/github"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExecActions(t *testing.T) {
+	mux := http.NewServeMux()
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	mux.HandleFunc("/api/v3/repos/o/r/actions/workflows/release.yml/runs", func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "main", r.URL.Query().Get("branch"))
+		require.Equal(t, "success", r.URL.Query().Get("status"))
+		_, _ = w.Write([]byte(`{"total_count":1,"workflow_runs":[{"id":42,"conclusion":"success"}]}`))
+	})
+	mux.HandleFunc("/api/v3/repos/o/r/actions/runs/42/logs", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, srv.URL+"/blob/logs.zip", http.StatusFound)
+	})
+	mux.HandleFunc("/blob/logs.zip", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("logs"))
+	})
+
+	t.Setenv("GITHUB_TOKEN", "token")
+	t.Setenv("GITHUB_API_URL", srv.URL)
+
+	branch := "main"
+	cfg := newGHCfg()
+	cfg.owner, cfg.repo, cfg.branch = "o", "r", &branch
+	cfg.workflowFileName, cfg.status = "release.yml", "success"
+	runs := internal.Must(execActions(cfg, &cobra.Command{Use: "workflow-runs-by-file-name"})).(*github.WorkflowRuns)
+	require.Equal(t, int64(42), runs.WorkflowRuns[0].GetID())
+
+	cfg.runID = 42
+	u := internal.Must(execActions(cfg, &cobra.Command{Use: "workflow-run-logs"}))
+	require.Equal(t, srv.URL+"/blob/logs.zip", u)
+
+	cfg.dest = filepath.Join(t.TempDir(), "logs.zip")
+	m := internal.Must(execActions(cfg, &cobra.Command{Use: "workflow-run-logs"})).(map[string]any)
+	require.Equal(t, int64(4), m["size"])
+	require.Equal(t, "logs", string(internal.Must(os.ReadFile(cfg.dest))))
+}
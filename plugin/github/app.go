@@ -0,0 +1,119 @@
+// Copyright 2023 The Heimdall authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !no_github
+
+package github
+
+import (
+	"crypto/rsa"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/abc-inc/heimdall/cli"
+	"github.com/abc-inc/heimdall/errs"
+	"github.com/abc-inc/heimdall/res"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/google/go-github/v69/github"
+	"golang.org/x/oauth2"
+)
+
+// appTokenSource creates installation access tokens for a GitHub App.
+// It is meant to be wrapped by oauth2.ReuseTokenSource, which caches the token until it expires.
+type appTokenSource struct {
+	client         *github.Client
+	installationID int64
+}
+
+// newAppTokenSource returns a TokenSource for the GitHub App configured by the profile or environment.
+// The private key is read from GITHUB_APP_PRIVATE_KEY_FILE, if set, otherwise from the keyring.
+func newAppTokenSource(appID, url string) oauth2.TokenSource {
+	instID := cli.Setting("github", "app-installation-id", "GITHUB_APP_INSTALLATION_ID")
+	if instID == "" {
+		errs.Abortf(errs.Auth, "undefined environment variable: %s", "GITHUB_APP_INSTALLATION_ID")
+	}
+	id, err := strconv.ParseInt(instID, 10, 64)
+	if err != nil {
+		errs.Abortf(errs.Usage, "invalid GITHUB_APP_INSTALLATION_ID: %s", instID)
+	}
+
+	var pem []byte
+	if f := cli.Setting("github", "app-private-key-file", "GITHUB_APP_PRIVATE_KEY_FILE"); f != "" {
+		pem, err = os.ReadFile(f)
+	} else {
+		var s string
+		s, err = cli.Keyring().Get(cli.KeyringService, "github-app/"+appID)
+		pem = []byte(s)
+	}
+	if err != nil {
+		errs.Abort(errs.New(errs.Auth, err))
+	}
+
+	key, err := jwt.ParseRSAPrivateKeyFromPEM(pem)
+	if err != nil {
+		errs.Abort(errs.New(errs.Auth, err))
+	}
+
+	jwtClient := &http.Client{Transport: &jwtTransport{base: res.Transport(nil), appID: appID, key: key}}
+	return oauth2.ReuseTokenSource(nil, &appTokenSource{
+		client:         withBaseURL(github.NewClient(jwtClient), url),
+		installationID: id,
+	})
+}
+
+// Token exchanges a JWT signed by the App for an installation access token.
+func (s *appTokenSource) Token() (*oauth2.Token, error) {
+	t, _, err := s.client.Apps.CreateInstallationToken(cli.Context(), s.installationID, nil)
+	if err != nil {
+		return nil, errs.New(errs.Auth, err)
+	}
+	return &oauth2.Token{AccessToken: t.GetToken(), Expiry: t.GetExpiresAt().Time}, nil
+}
+
+// jwtTransport authenticates requests as the GitHub App itself.
+type jwtTransport struct {
+	base  http.RoundTripper
+	appID string
+	key   *rsa.PrivateKey
+}
+
+func (t *jwtTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// Backdate the token to allow for clock drift, see
+	// https://docs.github.com/apps/creating-github-apps/authenticating-with-a-github-app/generating-a-json-web-token-jwt-for-a-github-app
+	now := time.Now()
+	claims := jwt.RegisteredClaims{
+		Issuer:    t.appID,
+		IssuedAt:  jwt.NewNumericDate(now.Add(-time.Minute)),
+		ExpiresAt: jwt.NewNumericDate(now.Add(9 * time.Minute)),
+	}
+	s, err := jwt.NewWithClaims(jwt.SigningMethodRS256, claims).SignedString(t.key)
+	if err != nil {
+		return nil, err
+	}
+
+	req = req.Clone(req.Context())
+	req.Header.Set("Authorization", "Bearer "+s)
+	return t.base.RoundTrip(req)
+}
+
+// staticTokenSource returns a TokenSource for the personal access token resolved by cli.Token.
+func staticTokenSource() oauth2.TokenSource {
+	t := cli.Token("github")
+	if t == "" {
+		errs.Abortf(errs.Auth, "no token found in --token, GH_TOKEN, GITHUB_TOKEN or the keyring (user '%s')", cli.KeyringUser("github"))
+	}
+	return oauth2.StaticTokenSource(&oauth2.Token{AccessToken: t})
+}
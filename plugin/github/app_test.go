@@ -0,0 +1,108 @@
+// Copyright 2023 The Heimdall authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !no_github
+
+package github
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/abc-inc/heimdall/internal"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewClientApp(t *testing.T) {
+	key := internal.Must(rsa.GenerateKey(rand.Reader, 2048))
+	keyFile := filepath.Join(t.TempDir(), "app.pem")
+	internal.MustNoErr(os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{
+		Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)}), 0600))
+
+	tokens := 0
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /api/v3/app/installations/7/access_tokens", func(w http.ResponseWriter, r *http.Request) {
+		s := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		claims := &jwt.RegisteredClaims{}
+		_, err := jwt.ParseWithClaims(s, claims, func(*jwt.Token) (any, error) { return &key.PublicKey, nil })
+		require.NoError(t, err)
+		require.Equal(t, "42", claims.Issuer)
+
+		// expire immediately to force a refresh on the next request
+		tokens++
+		_, _ = fmt.Fprintf(w, `{"token":"ghs_%d","expires_at":"%s"}`, tokens, time.Now().UTC().Format(time.RFC3339))
+	})
+	mux.HandleFunc("GET /api/v3/repos/o/r", func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, fmt.Sprintf("Bearer ghs_%d", tokens), r.Header.Get("Authorization"))
+		_, _ = w.Write([]byte(`{"full_name":"o/r"}`))
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	t.Setenv("GITHUB_API_URL", srv.URL)
+	t.Setenv("GITHUB_APP_ID", "42")
+	t.Setenv("GITHUB_APP_INSTALLATION_ID", "7")
+	t.Setenv("GITHUB_APP_PRIVATE_KEY_FILE", keyFile)
+
+	c := newClient()
+	for range 2 {
+		r, _, err := c.Repositories.Get(getCtx(nil), "o", "r")
+		require.NoError(t, err)
+		require.Equal(t, "o/r", r.GetFullName())
+	}
+	require.Equal(t, 2, tokens)
+}
+
+func TestNewClientCache(t *testing.T) {
+	key := internal.Must(rsa.GenerateKey(rand.Reader, 2048))
+	keyFile := filepath.Join(t.TempDir(), "app.pem")
+	internal.MustNoErr(os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{
+		Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)}), 0600))
+
+	tokens := 0
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /api/v3/app/installations/7/access_tokens", func(w http.ResponseWriter, r *http.Request) {
+		tokens++
+		_, _ = fmt.Fprintf(w, `{"token":"ghs_%d","expires_at":"%s"}`, tokens, time.Now().Add(time.Hour).UTC().Format(time.RFC3339))
+	})
+	mux.HandleFunc("GET /api/v3/repos/o/r", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"full_name":"o/r"}`))
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	t.Setenv("GITHUB_API_URL", srv.URL)
+	t.Setenv("GITHUB_APP_ID", "42")
+	t.Setenv("GITHUB_APP_INSTALLATION_ID", "7")
+	t.Setenv("GITHUB_APP_PRIVATE_KEY_FILE", keyFile)
+
+	// every page and repository creates a client, but the installation token is reused
+	for range 3 {
+		_, _, err := newClient().Repositories.Get(getCtx(nil), "o", "r")
+		require.NoError(t, err)
+	}
+	require.Same(t, newClient(), newClient())
+	require.Equal(t, 1, tokens)
+}
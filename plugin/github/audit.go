@@ -0,0 +1,292 @@
+// Copyright 2023 The Heimdall authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !no_github
+
+package github
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"net/http"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/MakeNowJust/heredoc/v2"
+	"github.com/abc-inc/heimdall/cli"
+	"github.com/abc-inc/heimdall/errs"
+	"github.com/abc-inc/heimdall/internal"
+	"github.com/abc-inc/heimdall/res"
+	"github.com/google/go-github/v69/github"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// auditSettings maps the settings, which can be audited, to the functions retrieving them.
+var auditSettings = map[string]Inv{
+	"automated-security-fixes":    execRepos,
+	"branch-protection":           execRepos,
+	"default-setup-configuration": execCodeScan,
+	"required-status-checks":      execRepos,
+	"vulnerability-alerts":        execRepos,
+}
+
+// AuditResult describes a setting, which does not match the desired state.
+type AuditResult struct {
+	RuleID   string `json:"rule_id" yaml:"rule_id"`
+	Repo     string `json:"repo" yaml:"repo"`
+	Status   string `json:"status" yaml:"status"`
+	Level    string `json:"level" yaml:"level"`
+	Message  string `json:"message" yaml:"message"`
+	Expected any    `json:"expected,omitempty" yaml:"expected,omitempty"`
+	Actual   any    `json:"actual,omitempty" yaml:"actual,omitempty"`
+}
+
+type AuditSummary struct {
+	Repos   int `json:"repos" yaml:"repos"`
+	Checked int `json:"checked" yaml:"checked"`
+	Drifts  int `json:"drifts" yaml:"drifts"`
+	Errors  int `json:"errors" yaml:"errors"`
+}
+
+type AuditReport struct {
+	Results []AuditResult `json:"results" yaml:"results"`
+	Summary AuditSummary  `json:"summary" yaml:"summary"`
+}
+
+type auditCfg struct {
+	*ghCfg
+	policy   string
+	allRepos bool
+}
+
+func NewAuditCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "audit <subcommand>",
+		Short: "Compare settings with a desired state.",
+		Args:  cobra.ExactArgs(0),
+	}
+
+	cmd.AddCommand(NewAuditRepoCmd())
+	return cmd
+}
+
+func NewAuditRepoCmd() *cobra.Command {
+	var branch string
+	cfg := auditCfg{ghCfg: newGHCfg()}
+	cfg.branch = &branch
+
+	cmd := &cobra.Command{
+		Use:   "repo",
+		Short: "Compare the security settings of repositories with a desired state.",
+		Long: heredoc.Doc(`
+			Compare the security settings of one or more repositories with a desired state and report every drift.
+
+			The policy file is a YAML (or JSON) document, which maps settings to their desired state.
+			Supported settings are: ` + strings.Join(slices.Sorted(maps.Keys(auditSettings)), ", ") + `.
+			Every setting is retrieved like the command of the same name, e.g., 'heimdall github repositories branch-protection'.
+			Only the properties in the policy are compared, others are ignored.
+			Lists must match exactly, and a setting, which does not exist (e.g., an unprotected branch), is null.
+
+			The command exits with status 1, if any setting deviates from the desired state or cannot be retrieved.
+		`),
+		Example: heredoc.Doc(`
+			# desired.yaml
+			branch-protection:
+			  enforce_admins:
+			    enabled: true
+			  required_pull_request_reviews:
+			    required_approving_review_count: 2
+			required-status-checks:
+			  strict: true
+			vulnerability-alerts: true
+			automated-security-fixes:
+			  enabled: true
+			default-setup-configuration:
+			  state: configured
+
+			heimdall github audit repo --policy desired.yaml --owner abc-inc --repo heimdall
+			heimdall github audit repo --policy desired.yaml --owner abc-inc --all-repos --output sarif
+			heimdall github audit repo --policy desired.yaml --owner abc-inc --repos topic:production
+		`),
+		Args: cobra.ExactArgs(0),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			setHostOwnerRepo(cfg.ghCfg, cfg.host, cfg.owner, cfg.repo)
+			if cfg.owner == "" && !strings.HasPrefix(cfg.repos, "@") || cfg.repo == "" && cfg.repos == "" && !cfg.allRepos {
+				return errs.Newf(errs.Usage, "either --owner and --repo, --repos or --owner and --all-repos must be set")
+			}
+
+			desired, err := loadDesiredState(cfg.policy)
+			if err != nil {
+				return err
+			}
+
+			rep, err := audit(cfg, desired)
+			if err != nil {
+				return err
+			}
+			cli.Fmtln(rep)
+			if len(rep.Results) > 0 {
+				return errs.Newf(errs.Violation, "%d drifts and %d errors in %d repositories",
+					rep.Summary.Drifts, rep.Summary.Errors, rep.Summary.Repos)
+			}
+			return nil
+		},
+	}
+
+	addRepoFlags(cfg.ghCfg, cmd)
+	cmd.Flags().StringVar(cfg.branch, "branch", *cfg.branch, "Branch name (default: the default branch of each repository)")
+	cmd.Flags().StringVar(&cfg.policy, "policy", cfg.policy, "File containing the desired state")
+	cmd.Flags().BoolVar(&cfg.allRepos, "all-repos", cfg.allRepos, "Audit every repository returned by 'repositories list' for the owner")
+	cmd.MarkFlagsMutuallyExclusive("repo", "repos", "all-repos")
+	internal.MustNoErr(cmd.MarkFlagRequired("policy"))
+
+	cli.AddOutputFlags(cmd, &cfg.OutCfg)
+	return cmd
+}
+
+// loadDesiredState reads a policy file and validates its settings.
+func loadDesiredState(name string) (map[string]any, error) {
+	r, err := res.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = r.Close() }()
+
+	var desired map[string]any
+	if err = yaml.NewDecoder(r).Decode(&desired); err != nil {
+		return nil, errs.Newf(errs.Input, "cannot read policy '%s': %w", name, err)
+	}
+	for s := range desired {
+		if _, ok := auditSettings[s]; !ok {
+			return nil, errs.Newf(errs.Input, "unsupported setting '%s' in '%s', must be one of %s",
+				s, name, strings.Join(slices.Sorted(maps.Keys(auditSettings)), ", "))
+		}
+	}
+	return normalize(desired)
+}
+
+func audit(cfg auditCfg, desired map[string]any) (rep AuditReport, err error) {
+	repos := []string{cfg.owner + "/" + cfg.repo}
+	if cfg.allRepos {
+		cfg.repos = "*"
+	}
+	if cfg.repos != "" {
+		if repos, err = selectRepos(cfg.owner, cfg.repos); err != nil {
+			return rep, err
+		}
+	}
+
+	reps := make([]AuditReport, len(repos))
+	forEachRepo(repos, cfg.concurrency, func(i int, repo string) {
+		c := *cfg.ghCfg
+		c.owner, c.repo, _ = strings.Cut(repo, "/")
+		reps[i] = auditRepo(&c, desired)
+	})
+
+	rep.Results = []AuditResult{}
+	for _, r := range reps {
+		rep.Results = append(rep.Results, r.Results...)
+		rep.Summary.Repos++
+		rep.Summary.Checked += r.Summary.Checked
+		rep.Summary.Drifts += r.Summary.Drifts
+		rep.Summary.Errors += r.Summary.Errors
+	}
+	return rep, nil
+}
+
+// auditRepo compares the settings of a single repository with the desired state.
+func auditRepo(cfg *ghCfg, desired map[string]any) (rep AuditReport) {
+	repo, branch := cfg.owner+"/"+cfg.repo, defVal(cfg.branch)
+	for _, s := range slices.Sorted(maps.Keys(desired)) {
+		b := branch
+		cfg.branch = &b
+		log.Debug().Str("repo", repo).Str("setting", s).Msg("Auditing setting")
+
+		actual, err := getSetting(cfg, s)
+		if err != nil {
+			rep.Summary.Errors++
+			rep.Results = append(rep.Results, AuditResult{RuleID: s, Repo: repo, Status: "error", Level: cli.LevelError, Message: err.Error()})
+			continue
+		}
+
+		rep.Summary.Checked++
+		for _, d := range compare(s, desired[s], actual) {
+			d.Repo = repo
+			rep.Summary.Drifts++
+			rep.Results = append(rep.Results, d)
+		}
+	}
+	return rep
+}
+
+// getSetting retrieves a setting in the same way as the command of the same name.
+// A setting, which does not exist, is nil.
+func getSetting(cfg *ghCfg, name string) (v any, err error) {
+	defer errs.Recover(&err)
+	cmd := &cobra.Command{Use: name}
+	if name == "branch-protection" || name == "required-status-checks" {
+		cmd.Flags().StringVar(cfg.branch, "branch", *cfg.branch, "")
+	}
+
+	a, err := auditSettings[name](cfg, cmd)
+	var z *github.ErrorResponse
+	if errors.As(err, &z) && z.Response.StatusCode == http.StatusNotFound || errors.Is(err, github.ErrBranchNotProtected) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	return normalize(a)
+}
+
+// compare reports every property of the desired state, which differs from the actual state.
+func compare(path string, desired, actual any) (rs []AuditResult) {
+	if dm, ok := desired.(map[string]any); ok {
+		am, _ := actual.(map[string]any)
+		for _, k := range slices.Sorted(maps.Keys(dm)) {
+			rs = append(rs, compare(path+"."+k, dm[k], am[k])...)
+		}
+		return rs
+	}
+
+	if reflect.DeepEqual(desired, actual) {
+		return nil
+	}
+	return []AuditResult{{
+		RuleID:   path,
+		Status:   "drift",
+		Level:    cli.LevelError,
+		Message:  fmt.Sprintf("%s: expected %s, got %s", path, toJSON(desired), toJSON(actual)),
+		Expected: desired,
+		Actual:   actual,
+	}}
+}
+
+// normalize converts a value to its JSON representation, i.e., maps, slices, strings, float64, bool or nil.
+func normalize[T any](v T) (n T, err error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return n, err
+	}
+	return n, json.Unmarshal(b, &n)
+}
+
+func toJSON(a any) string {
+	b, _ := json.Marshal(a)
+	return string(b)
+}
@@ -0,0 +1,109 @@
+// Copyright 2023 The Heimdall authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !no_github
+
+package github
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/abc-inc/heimdall/cli"
+	"github.com/abc-inc/heimdall/errs"
+	"github.com/abc-inc/heimdall/internal"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAudit(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/v3/users/o/repos", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`[{"full_name":"o/a"},{"full_name":"o/b"}]`))
+	})
+	mux.HandleFunc("GET /api/v3/repos/o/{repo}", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"default_branch":"main"}`))
+	})
+	mux.HandleFunc("GET /api/v3/repos/o/a/branches/main/protection", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"enforce_admins":{"enabled":true},"required_status_checks":{"strict":true,"contexts":["build"]}}`))
+	})
+	mux.HandleFunc("GET /api/v3/repos/o/b/branches/main/protection", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"message":"Branch not protected"}`, http.StatusNotFound)
+	})
+	mux.HandleFunc("GET /api/v3/repos/o/a/vulnerability-alerts", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+	mux.HandleFunc("GET /api/v3/repos/o/b/vulnerability-alerts", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"message":"Not Found"}`, http.StatusNotFound)
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	t.Setenv("GITHUB_TOKEN", "token")
+	t.Setenv("GITHUB_API_URL", srv.URL)
+
+	policy := filepath.Join(t.TempDir(), "desired.yaml")
+	internal.MustNoErr(os.WriteFile(policy, []byte(`
+branch-protection:
+  enforce_admins:
+    enabled: true
+  required_status_checks:
+    contexts: [build]
+vulnerability-alerts: true
+`), 0600))
+	desired := internal.Must(loadDesiredState(policy))
+
+	branch := ""
+	cfg := auditCfg{ghCfg: newGHCfg(), allRepos: true}
+	cfg.owner, cfg.branch = "o", &branch
+	rep := internal.Must(audit(cfg, desired))
+
+	require.Equal(t, AuditSummary{Repos: 2, Checked: 4, Drifts: 3}, rep.Summary)
+	require.Equal(t, AuditResult{
+		RuleID:   "branch-protection.enforce_admins.enabled",
+		Repo:     "o/b",
+		Status:   "drift",
+		Level:    cli.LevelError,
+		Message:  "branch-protection.enforce_admins.enabled: expected true, got null",
+		Expected: true,
+	}, rep.Results[0])
+	require.Equal(t, "vulnerability-alerts", rep.Results[2].RuleID)
+	require.Equal(t, false, rep.Results[2].Actual)
+}
+
+func TestAuditRepoAbort(t *testing.T) {
+	defer func(inv Inv) { auditSettings["vulnerability-alerts"] = inv }(auditSettings["vulnerability-alerts"])
+	auditSettings["vulnerability-alerts"] = func(cfg *ghCfg, cmd *cobra.Command) (any, error) {
+		errs.Abortf(errs.Auth, "no credentials")
+		return nil, nil
+	}
+
+	cfg := newGHCfg()
+	cfg.owner, cfg.repo = "o", "a"
+	rep := auditRepo(cfg, map[string]any{"vulnerability-alerts": true})
+
+	require.Equal(t, AuditSummary{Errors: 1}, rep.Summary)
+	require.Equal(t, []AuditResult{{RuleID: "vulnerability-alerts", Repo: "o/a", Status: "error",
+		Level: cli.LevelError, Message: "no credentials"}}, rep.Results)
+}
+
+func TestLoadDesiredStateUnsupported(t *testing.T) {
+	policy := filepath.Join(t.TempDir(), "desired.yaml")
+	internal.MustNoErr(os.WriteFile(policy, []byte("teams: {}\n"), 0600))
+	_, err := loadDesiredState(policy)
+	require.ErrorContains(t, err, "unsupported setting 'teams'")
+}
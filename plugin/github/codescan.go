@@ -86,33 +86,33 @@ func execCodeScan(cfg *ghCfg, cmd *cobra.Command) (a any, err error) {
 
 	switch cmd.Name() {
 	case "alert":
-		a, _, err = svc.GetAlert(getCtx(cfg), cfg.owner, cfg.repo, cfg.id)
+		a, cfg.resp, err = svc.GetAlert(getCtx(cfg), cfg.owner, cfg.repo, cfg.id)
 	case "alert-instances":
-		a, _, err = svc.ListAlertInstances(getCtx(cfg), cfg.owner, cfg.repo, cfg.id, &github.AlertInstancesListOptions{
+		a, cfg.resp, err = svc.ListAlertInstances(getCtx(cfg), cfg.owner, cfg.repo, cfg.id, &github.AlertInstancesListOptions{
 			Ref:         defVal(cfg.branch),
 			ListOptions: github.ListOptions{Page: cfg.page, PerPage: cfg.perPage},
 		})
 	case "alerts-for-repo":
-		a, _, err = svc.ListAlertsForRepo(getCtx(cfg), cfg.owner, cfg.repo, &github.AlertListOptions{
+		a, cfg.resp, err = svc.ListAlertsForRepo(getCtx(cfg), cfg.owner, cfg.repo, &github.AlertListOptions{
 			State:             defVal(cfg.state),
 			Ref:               defVal(cfg.branch),
 			Severity:          defVal(cfg.severity),
 			ToolName:          defVal(cfg.toolName),
-			ListCursorOptions: github.ListCursorOptions{},
+			ListCursorOptions: github.ListCursorOptions{After: cfg.after},
 			ListOptions:       github.ListOptions{Page: cfg.page, PerPage: cfg.perPage},
 		})
 	case "analyses-for-repo":
-		a, _, err = svc.ListAnalysesForRepo(getCtx(cfg), cfg.owner, cfg.repo, &github.AnalysesListOptions{
+		a, cfg.resp, err = svc.ListAnalysesForRepo(getCtx(cfg), cfg.owner, cfg.repo, &github.AnalysesListOptions{
 			SarifID:     &cfg.sarifID,
 			Ref:         cfg.branch,
 			ListOptions: github.ListOptions{Page: cfg.page, PerPage: cfg.perPage},
 		})
 	case "analysis":
-		a, _, err = svc.GetAnalysis(getCtx(cfg), cfg.owner, cfg.repo, cfg.id)
+		a, cfg.resp, err = svc.GetAnalysis(getCtx(cfg), cfg.owner, cfg.repo, cfg.id)
 	case "default-setup-configuration":
-		a, _, err = svc.GetDefaultSetupConfiguration(getCtx(cfg), cfg.owner, cfg.repo)
+		a, cfg.resp, err = svc.GetDefaultSetupConfiguration(getCtx(cfg), cfg.owner, cfg.repo)
 	case "sarif":
-		a, _, err = svc.GetSARIF(getCtx(cfg), cfg.owner, cfg.repo, cfg.sarifID)
+		a, cfg.resp, err = svc.GetSARIF(getCtx(cfg), cfg.owner, cfg.repo, cfg.sarifID)
 	default:
 		panic("Unsupported operation: " + cmd.Name())
 	}
@@ -73,9 +73,9 @@ func execDependabot(cfg *ghCfg, cmd *cobra.Command) (a any, err error) {
 
 	switch cmd.Name() {
 	case "repo-alert":
-		a, _, err = svc.GetRepoAlert(getCtx(cfg), cfg.owner, cfg.repo, int(cfg.id))
+		a, cfg.resp, err = svc.GetRepoAlert(getCtx(cfg), cfg.owner, cfg.repo, int(cfg.id))
 	case "repo-alerts":
-		a, _, err = svc.ListRepoAlerts(getCtx(cfg), cfg.owner, cfg.repo, &github.ListAlertsOptions{
+		a, cfg.resp, err = svc.ListRepoAlerts(getCtx(cfg), cfg.owner, cfg.repo, &github.ListAlertsOptions{
 			State:             cfg.state,
 			Severity:          cfg.severity,
 			Ecosystem:         cfg.ecosystem,
@@ -84,12 +84,12 @@ func execDependabot(cfg *ghCfg, cmd *cobra.Command) (a any, err error) {
 			Sort:              cfg.sort,
 			Direction:         cfg.direction,
 			ListOptions:       github.ListOptions{Page: cfg.page, PerPage: cfg.perPage},
-			ListCursorOptions: github.ListCursorOptions{},
+			ListCursorOptions: github.ListCursorOptions{After: cfg.after},
 		})
 	case "repo-secret":
-		a, _, err = svc.GetRepoSecret(getCtx(cfg), cfg.owner, cfg.repo, cfg.name)
+		a, cfg.resp, err = svc.GetRepoSecret(getCtx(cfg), cfg.owner, cfg.repo, cfg.name)
 	case "repo-secrets":
-		a, _, err = svc.ListRepoSecrets(getCtx(cfg), cfg.owner, cfg.repo, &github.ListOptions{
+		a, cfg.resp, err = svc.ListRepoSecrets(getCtx(cfg), cfg.owner, cfg.repo, &github.ListOptions{
 			Page: cfg.page, PerPage: cfg.perPage})
 	default:
 		panic("Unsupported operation: " + cmd.Name())
@@ -55,7 +55,7 @@ func execDepGraph(cfg *ghCfg, cmd *cobra.Command) (a any, err error) {
 
 	switch cmd.Name() {
 	case "sbom":
-		a, _, err = svc.GetSBOM(getCtx(cfg), cfg.owner, cfg.repo)
+		a, cfg.resp, err = svc.GetSBOM(getCtx(cfg), cfg.owner, cfg.repo)
 	default:
 		panic("Unsupported operation: " + cmd.Name())
 	}
@@ -0,0 +1,231 @@
+// Copyright 2023 The Heimdall authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !no_github
+
+package github
+
+import (
+	"bufio"
+	"cmp"
+	"errors"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/abc-inc/heimdall/cli"
+	"github.com/abc-inc/heimdall/errs"
+	"github.com/abc-inc/heimdall/res"
+	"github.com/gobwas/glob"
+	"github.com/google/go-github/v69/github"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+)
+
+// RepoResult is the outcome of a subcommand for a single repository.
+type RepoResult struct {
+	Repo   string `json:"repo" yaml:"repo"`
+	Result any    `json:"result,omitempty" yaml:"result,omitempty"`
+	Error  string `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+// fanOut runs a subcommand for every repository selected by --repos and prints
+// the results tagged with the repository.
+// Errors are collected per repository instead of aborting the whole run.
+func fanOut(cfg *ghCfg, cmd *cobra.Command, inv Inv) error {
+	setHostOwnerRepo(cfg, cfg.host, cfg.owner, cfg.repo)
+	repos, err := selectRepos(cfg.owner, cfg.repos)
+	if err != nil {
+		return err
+	}
+
+	// cobra initializes flag sets lazily, hence every worker gets its own command
+	cmds := make([]*cobra.Command, len(repos))
+	for i := range cmds {
+		cmds[i] = &cobra.Command{Use: cmd.Name()}
+		cmds[i].Flags().AddFlagSet(cmd.Flags())
+	}
+
+	rs := make([]RepoResult, len(repos))
+	failed := make([]bool, len(repos))
+	gate := &rateGate{}
+	forEachRepo(repos, cfg.concurrency, func(i int, repo string) {
+		c := *cfg
+		c.owner, c.repo, _ = strings.Cut(repo, "/")
+
+		rs[i].Repo = repo
+		a, err := gate.call(&c, cmds[i], inv)
+		if err != nil {
+			rs[i].Error = err.Error()
+			failed[i] = apiError(err) != nil
+			return
+		}
+		rs[i].Result = a
+	})
+
+	cli.Fmtln(rs)
+	if n := len(slices.DeleteFunc(failed, func(f bool) bool { return !f })); n > 0 {
+		return errs.Newf(errs.Remote, "%d of %d repositories failed", n, len(repos))
+	}
+	return nil
+}
+
+// forEachRepo calls fn for every repository using at most n goroutines.
+func forEachRepo(repos []string, n int, fn func(i int, repo string)) {
+	sem := make(chan struct{}, max(n, 1))
+	wg := sync.WaitGroup{}
+	for i, r := range repos {
+		sem <- struct{}{}
+		wg.Add(1)
+		go func() {
+			defer func() { <-sem; wg.Done() }()
+			fn(i, r)
+		}()
+	}
+	wg.Wait()
+}
+
+// selectRepos returns the full names of the repositories matching the selector, i.e.,
+// "@<file>" containing one repository per line, "topic:<topic>" or a glob pattern.
+// Repositories without owner and globs without "/" refer to repositories of the given owner.
+func selectRepos(owner, sel string) (names []string, err error) {
+	if f, ok := strings.CutPrefix(sel, "@"); ok {
+		return readRepos(owner, f)
+	} else if owner == "" {
+		return nil, errs.Newf(errs.Usage, "--owner is required for selecting repositories by '%s'", sel)
+	}
+
+	var match func(r *github.Repository) bool
+	if t, ok := strings.CutPrefix(sel, "topic:"); ok {
+		match = func(r *github.Repository) bool { return slices.Contains(r.Topics, t) }
+	} else if g, err := glob.Compile(sel, '/'); err != nil {
+		return nil, errs.Newf(errs.Usage, "invalid repository pattern '%s': %w", sel, err)
+	} else if strings.Contains(sel, "/") {
+		match = func(r *github.Repository) bool { return g.Match(r.GetFullName()) }
+	} else {
+		match = func(r *github.Repository) bool { return g.Match(r.GetName()) }
+	}
+
+	rs, err := listRepos(owner)
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range rs {
+		if match(r) {
+			names = append(names, r.GetFullName())
+		}
+	}
+	log.Debug().Str("selector", sel).Int("total", len(rs)).Int("selected", len(names)).Msg("Selected repositories")
+	return names, nil
+}
+
+// readRepos reads repository names from a file, ignoring blank lines and comments.
+func readRepos(owner, name string) (names []string, err error) {
+	r, err := res.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = r.Close() }()
+
+	s := bufio.NewScanner(r)
+	for s.Scan() {
+		l, _, _ := strings.Cut(s.Text(), "#")
+		if l = strings.TrimSpace(l); l == "" {
+			continue
+		} else if !strings.Contains(l, "/") {
+			if owner == "" {
+				return nil, errs.Newf(errs.Usage, "--owner is required for repository '%s' in '%s'", l, name)
+			}
+			l = owner + "/" + l
+		}
+		names = append(names, l)
+	}
+	return names, s.Err()
+}
+
+// listRepos returns all repositories of the owner like 'repositories list'.
+func listRepos(owner string) ([]*github.Repository, error) {
+	cfg := newGHCfg()
+	cfg.owner, cfg.all = owner, true
+	a, err := paginate(cfg, &cobra.Command{Use: "list"}, execRepos)
+	if err != nil {
+		return nil, apiError(err)
+	}
+	return a.([]*github.Repository), nil
+}
+
+// rateGate suspends all workers, once the primary or secondary rate limit is exceeded.
+type rateGate struct {
+	mu    sync.Mutex
+	until time.Time
+}
+
+// call invokes the operation and retries it after the rate limit has been reset.
+func (g *rateGate) call(cfg *ghCfg, cmd *cobra.Command, inv Inv) (any, error) {
+	for {
+		if err := g.wait(); err != nil {
+			return nil, err
+		}
+
+		a, err := invoke(cfg, cmd, inv)
+		var rle *github.RateLimitError
+		var are *github.AbuseRateLimitError
+		switch {
+		case errors.As(err, &rle):
+			g.pause(rle.Rate.Reset.Time)
+		case errors.As(err, &are):
+			g.pause(time.Now().Add(cmp.Or(are.GetRetryAfter(), time.Minute)))
+		default:
+			if cfg.resp != nil && cfg.resp.Rate.Limit > 0 && cfg.resp.Rate.Remaining == 0 {
+				g.pause(cfg.resp.Rate.Reset.Time)
+			}
+			return a, err
+		}
+		log.Warn().Err(err).Str("repo", cfg.owner+"/"+cfg.repo).Msg("Rate limit exceeded, waiting")
+	}
+}
+
+// invoke calls the operation and turns an abort, e.g., due to missing credentials,
+// into an error, so that it fails only the current repository.
+func invoke(cfg *ghCfg, cmd *cobra.Command, inv Inv) (a any, err error) {
+	defer errs.Recover(&err)
+	return inv(cfg, cmd)
+}
+
+func (g *rateGate) pause(t time.Time) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if t.After(g.until) {
+		g.until = t
+	}
+}
+
+func (g *rateGate) wait() error {
+	g.mu.Lock()
+	d := time.Until(g.until)
+	g.mu.Unlock()
+	if d <= 0 {
+		return nil
+	}
+
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-cli.Context().Done():
+		return cli.Context().Err()
+	}
+}
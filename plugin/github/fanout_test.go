@@ -0,0 +1,113 @@
+// Copyright 2023 The Heimdall authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !no_github
+
+package github
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/abc-inc/heimdall/cli"
+	"github.com/abc-inc/heimdall/errs"
+	"github.com/abc-inc/heimdall/internal"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSelectRepos(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/v3/users/o/repos", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`[{"name":"api","full_name":"o/api","topics":["prod"]},
+			{"name":"api-docs","full_name":"o/api-docs"},{"name":"web","full_name":"o/web","topics":["prod"]}]`))
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+	t.Setenv("GITHUB_TOKEN", "token")
+	t.Setenv("GITHUB_API_URL", srv.URL)
+
+	require.Equal(t, []string{"o/api", "o/api-docs"}, internal.Must(selectRepos("o", "api*")))
+	require.Equal(t, []string{"o/api", "o/web"}, internal.Must(selectRepos("o", "topic:prod")))
+	require.Equal(t, []string{"o/web"}, internal.Must(selectRepos("o", "o/{web,www}")))
+
+	f := filepath.Join(t.TempDir(), "repos.txt")
+	internal.MustNoErr(os.WriteFile(f, []byte("# repos\nx/y\n\nz  # no owner\n"), 0600))
+	require.Equal(t, []string{"x/y", "o/z"}, internal.Must(selectRepos("o", "@"+f)))
+
+	_, err := selectRepos("", "api*")
+	require.ErrorIs(t, err, errs.ErrUsage)
+}
+
+func TestFanOut(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/v3/repos/o/a/languages", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"Go":42}`))
+	})
+	mux.HandleFunc("GET /api/v3/repos/o/b/languages", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"message":"Bad credentials"}`, http.StatusUnauthorized)
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+	t.Setenv("GITHUB_TOKEN", "token")
+	t.Setenv("GITHUB_API_URL", srv.URL)
+
+	f := filepath.Join(t.TempDir(), "repos.txt")
+	internal.MustNoErr(os.WriteFile(f, []byte("o/a\no/b\n"), 0600))
+
+	io, _, out, _ := cli.Test()
+	cli.IO = io
+	defer func() { cli.IO = cli.System() }()
+	cli.SetFormat(map[string]any{"output": "json"})
+
+	cfg := newGHCfg()
+	cfg.repos, cfg.concurrency = "@"+f, 2
+	err := fanOut(cfg, &cobra.Command{Use: "languages"}, execRepos)
+	require.ErrorIs(t, err, errs.ErrRemote)
+
+	var rs []RepoResult
+	internal.MustNoErr(json.Unmarshal(out.Bytes(), &rs))
+	require.Len(t, rs, 2)
+	require.Equal(t, RepoResult{Repo: "o/a", Result: map[string]any{"Go": 42.0}}, rs[0])
+	require.Equal(t, "o/b", rs[1].Repo)
+	require.Contains(t, rs[1].Error, "401 Bad credentials")
+}
+
+func TestFanOutAbort(t *testing.T) {
+	f := filepath.Join(t.TempDir(), "repos.txt")
+	internal.MustNoErr(os.WriteFile(f, []byte("o/a\no/b\n"), 0600))
+
+	io, _, out, _ := cli.Test()
+	cli.IO = io
+	defer func() { cli.IO = cli.System() }()
+	cli.SetFormat(map[string]any{"output": "json"})
+
+	cfg := newGHCfg()
+	cfg.repos, cfg.concurrency = "@"+f, 2
+	err := fanOut(cfg, &cobra.Command{Use: "get"}, func(cfg *ghCfg, cmd *cobra.Command) (any, error) {
+		if cfg.repo == "b" {
+			errs.Abortf(errs.Auth, "no credentials for %s", cfg.repo)
+		}
+		return cfg.repo, nil
+	})
+	require.ErrorIs(t, err, errs.ErrRemote)
+
+	var rs []RepoResult
+	internal.MustNoErr(json.Unmarshal(out.Bytes(), &rs))
+	require.Equal(t, []RepoResult{{Repo: "o/a", Result: "a"}, {Repo: "o/b", Error: "no credentials for b"}}, rs)
+}
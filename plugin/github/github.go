@@ -25,14 +25,17 @@ import (
 	"reflect"
 	"slices"
 	"strings"
+	"sync"
 	"time"
 	"unicode"
 
 	"github.com/abc-inc/goava/base/casefmt"
 	"github.com/abc-inc/heimdall"
 	"github.com/abc-inc/heimdall/cli"
+	"github.com/abc-inc/heimdall/errs"
 	"github.com/abc-inc/heimdall/internal"
 	"github.com/abc-inc/heimdall/plugin/root"
+	"github.com/abc-inc/heimdall/res"
 	"github.com/google/go-github/v69/github"
 	"github.com/rs/zerolog/log"
 	"github.com/spf13/cobra"
@@ -48,14 +51,21 @@ type ghCfg struct {
 	host   string
 	owner  string
 	repo   string
+	// Fan-out
+	repos       string
+	concurrency int
 	// Misc
 	branch  *string
 	name    string
 	id      int64
 	sarifID string
 	// Page
-	page    int
-	perPage int
+	page     int
+	perPage  int
+	after    string
+	all      bool
+	maxItems int
+	resp     *github.Response
 	// CodeScan
 	state    *string
 	severity *string
@@ -100,6 +110,14 @@ type ghCfg struct {
 	// Actions
 	workflowFileName string
 	inputs           map[string]interface{}
+	actor            string
+	event            string
+	status           string
+	created          string
+	excludePRs       bool
+	filter           string
+	runID            int64
+	dest             string
 	// Enterprise
 	enterprise string
 	// Output
@@ -121,12 +139,15 @@ func newGHCfg() *ghCfg {
 }
 
 const envHelp = `
-GH_TOKEN        <PERSONAL_ACCESS_TOKEN>
-GH_HOST         https://api.github.com
-GH_OWNER        <OWNER>
-GH_REPO         <REPO>
-GITHUB_TOKEN    <PERSONAL_ACCESS_TOKEN>
-GITHUB_API_URL  https://api.github.com
+GH_TOKEN                     <PERSONAL_ACCESS_TOKEN>
+GH_HOST                      https://api.github.com
+GH_OWNER                     <OWNER>
+GH_REPO                      <REPO>
+GITHUB_TOKEN                 <PERSONAL_ACCESS_TOKEN>
+GITHUB_API_URL               https://api.github.com
+GITHUB_APP_ID                <APP_ID>
+GITHUB_APP_INSTALLATION_ID   <INSTALLATION_ID>
+GITHUB_APP_PRIVATE_KEY_FILE  <PEM_FILE>
 `
 
 func NewGitHubCmd() *cobra.Command {
@@ -139,6 +160,8 @@ func NewGitHubCmd() *cobra.Command {
 	}
 
 	cmd.AddCommand(
+		NewActionsCmd(),
+		NewAuditCmd(),
 		NewCodeScanCmd(),
 		NewDependabotCmd(),
 		NewDepGraphCmd(),
@@ -149,6 +172,7 @@ func NewGitHubCmd() *cobra.Command {
 		NewTeamsCmd(),
 		NewUsersCmd(),
 	)
+	cli.AddTokenFlag(cmd, "github", "GH_TOKEN", "GITHUB_TOKEN")
 
 	return cmd
 }
@@ -168,6 +192,9 @@ func addRepoFlags(cfg *ghCfg, cmd *cobra.Command) {
 
 	cmd.Flags().StringVar(&cfg.owner, "owner", cfg.owner, "Owner/org of the repository")
 	cmd.Flags().StringVar(&cfg.repo, "repo", cfg.repo, "Repository name")
+	cmd.Flags().StringVar(&cfg.repos, "repos", cfg.repos, "Run for multiple repositories: @<file>, topic:<topic> or a glob matching the repositories of the owner")
+	cmd.Flags().IntVar(&cfg.concurrency, "concurrency", 4, "Number of repositories to query concurrently with --repos")
+	cmd.MarkFlagsMutuallyExclusive("repo", "repos")
 }
 
 func setHostOwnerRepo(cfg *ghCfg, host, owner, repo string) {
@@ -194,6 +221,9 @@ func setHostOwnerRepo(cfg *ghCfg, host, owner, repo string) {
 func addListFlags(cfg *ghCfg, cmd *cobra.Command) {
 	cmd.Flags().IntVar(&cfg.page, "page", cfg.page, "Page number")
 	cmd.Flags().IntVar(&cfg.perPage, "per-page", cfg.perPage, "Items per page")
+	cmd.Flags().BoolVar(&cfg.all, "all", cfg.all, "Fetch all pages and merge the items")
+	cmd.Flags().IntVar(&cfg.maxItems, "max-items", cfg.maxItems, "Maximum number of items to fetch with --all (0 means no limit)")
+	cmd.MarkFlagsMutuallyExclusive("all", "page")
 }
 
 func addItemFlags(cfg *ghCfg, cmd *cobra.Command) {
@@ -205,11 +235,15 @@ func createCmds(cfg *ghCfg, svcTyp reflect.Type, inv Inv, list []string) (cmds [
 	ctxTyp := reflect.TypeOf(context.Background())
 	respType := reflect.TypeOf(&github.Response{})
 
+	actions := svcTyp == reflect.TypeOf(&github.ActionsService{})
+
 	for i := 0; i < svcTyp.NumMethod(); i++ {
 		m := svcTyp.Method(i)
 		mTyp := m.Type
 
-		if strings.HasSuffix(m.Name, "ForEnterprise") || strings.HasSuffix(m.Name, "ForOrg") ||
+		if actions && !slices.Contains(actionsMethods, m.Name) {
+			continue
+		} else if !actions && (strings.HasSuffix(m.Name, "ForEnterprise") || strings.HasSuffix(m.Name, "ForOrg") ||
 			strings.Contains(m.Name, "CodeQL") ||
 			strings.Contains(m.Name, "OrgAlerts") || strings.Contains(m.Name, "OrgPublicKey") || strings.Contains(m.Name, "OrgSecret") ||
 			strings.Contains(m.Name, "RepoPublicKey") || strings.Contains(m.Name, "Ruleset") || strings.Contains(m.Name, "CodeOfConduct") ||
@@ -222,12 +256,12 @@ func createCmds(cfg *ghCfg, svcTyp reflect.Type, inv Inv, list []string) (cmds [
 			strings.Contains(m.Name, "Page") || strings.Contains(m.Name, "Signatures") ||
 			strings.Contains(m.Name, "App") || strings.Contains(m.Name, "Autolink") || strings.Contains(m.Name, "Project") ||
 			strings.Contains(m.Name, "Context") || strings.Contains(m.Name, "Traffic") ||
-			(svcTyp == reflect.TypeOf(&github.UsersService{}) && m.Name != "ListAll" && m.Name != "Get") {
+			(svcTyp == reflect.TypeOf(&github.UsersService{}) && m.Name != "ListAll" && m.Name != "Get")) {
 
 			continue
 		}
 
-		if (strings.HasPrefix(m.Name, "Get") || strings.HasPrefix(m.Name, "List")) &&
+		if (strings.HasPrefix(m.Name, "Get") || strings.HasPrefix(m.Name, "List") || actions && strings.HasPrefix(m.Name, "Download")) &&
 			(mTyp.NumIn() > 2 && ctxTyp.Implements(mTyp.In(1))) &&
 			(mTyp.NumOut() > 2 && mTyp.Out(mTyp.NumOut()-2).AssignableTo(respType) && mTyp.Out(mTyp.NumOut()-1).Name() == "error") {
 
@@ -257,17 +291,21 @@ func createCmds(cfg *ghCfg, svcTyp reflect.Type, inv Inv, list []string) (cmds [
 				Long:        desc,
 				Args:        cobra.ExactArgs(0),
 				Annotations: map[string]string{"method": m.Name, "kind": kind},
-				Run: func(cmd *cobra.Command, args []string) {
-					a, err := inv(cfg, cmd)
-					var z *github.ErrorResponse
-					if errors.As(err, &z) && z.Response.StatusCode == http.StatusNotFound {
-						log.Warn().Err(err).Send()
-						return
-					} else if err != nil {
-						log.Err(err).Send()
-					} else {
-						cli.Fmtln(a)
+				RunE: func(cmd *cobra.Command, args []string) error {
+					call := inv
+					if kind == "list" && cfg.all {
+						call = func(cfg *ghCfg, cmd *cobra.Command) (any, error) { return paginate(cfg, cmd, inv) }
+					}
+					if cfg.repos != "" {
+						return fanOut(cfg, cmd, call)
+					}
+
+					a, err := call(cfg, cmd)
+					if err != nil {
+						return apiError(err)
 					}
+					cli.Fmtln(a)
+					return nil
 				},
 			})
 
@@ -279,6 +317,23 @@ func createCmds(cfg *ghCfg, svcTyp reflect.Type, inv Inv, list []string) (cmds [
 	return
 }
 
+// apiError classifies errors returned by the GitHub API.
+// Not found errors are logged, but not considered as failure.
+func apiError(err error) error {
+	var z *github.ErrorResponse
+	if errors.As(err, &z) && z.Response.StatusCode == http.StatusNotFound {
+		log.Warn().Err(err).Send()
+		return nil
+	} else if z != nil {
+		return errs.FromStatus(z.Response.StatusCode, err)
+	} else if err != nil && errors.As(err, new(*errs.Error)) {
+		return err
+	} else if err != nil {
+		return errs.New(errs.Remote, err)
+	}
+	return nil
+}
+
 func cmdName(m reflect.Method) string {
 	n := casefmt.LowerCamel{}.To(casefmt.LowerHyphen{}, m.Name)
 	if n == "get" {
@@ -295,28 +350,50 @@ func cmdName(m reflect.Method) string {
 	return n
 }
 
+// clients caches the clients by profile, URL and --token flag, so that the
+// token is resolved once and installation tokens of GitHub Apps are reused.
+var clients = struct {
+	sync.Mutex
+	m map[string]*github.Client
+}{m: map[string]*github.Client{}}
+
 func newClient() *github.Client {
-	t := os.Getenv("GITHUB_TOKEN")
-	if t == "" {
-		log.Fatal().Str("name", "GITHUB_TOKEN").Msg("undefined environment variable")
-		return nil
-	}
-	url := strings.TrimSuffix(os.Getenv("GITHUB_API_URL"), "/")
+	url := strings.TrimSuffix(cli.Setting("github", "url", "GITHUB_API_URL"), "/")
 	if url == "" {
-		log.Fatal().Str("name", "GITHUB_API_URL").Msg("undefined environment variable")
+		errs.Abortf(errs.Usage, "undefined environment variable: %s", "GITHUB_API_URL")
 		return nil
 	}
 
-	src := oauth2.StaticTokenSource(&oauth2.Token{AccessToken: t})
-	httpClient := oauth2.NewClient(context.Background(), src)
+	key := strings.Join([]string{cli.Profile(), url, cli.TokenFlag("github")}, "\x00")
+	clients.Lock()
+	defer clients.Unlock()
+	if c, ok := clients.m[key]; ok {
+		return c
+	}
+
+	var src oauth2.TokenSource
+	if appID := cli.Setting("github", "app-id", "GITHUB_APP_ID"); appID != "" {
+		src = newAppTokenSource(appID, url)
+	} else {
+		src = staticTokenSource()
+	}
+
+	ctx := context.WithValue(context.Background(), oauth2.HTTPClient, &http.Client{Transport: res.Transport(nil)})
+	httpClient := oauth2.NewClient(ctx, src)
+	clients.m[key] = withBaseURL(github.NewClient(httpClient), url)
+	return clients.m[key]
+}
+
+// withBaseURL configures the client to use GitHub Enterprise Server, unless url refers to github.com.
+func withBaseURL(c *github.Client, url string) *github.Client {
 	if strings.HasPrefix(url, "https://api.github.com") {
-		return github.NewClient(httpClient)
+		return c
 	}
-	return internal.Must(github.NewClient(httpClient).WithEnterpriseURLs(url, strings.TrimSuffix(strings.TrimSuffix(url, "/v3"), "/api")))
+	return internal.Must(c.WithEnterpriseURLs(url, strings.TrimSuffix(strings.TrimSuffix(url, "/v3"), "/api")))
 }
 
 func getCtx(_ *ghCfg) context.Context {
-	return context.Background()
+	return cli.Context()
 }
 
 func branchOrDefault(cfg *ghCfg, svc *github.RepositoriesService) {
@@ -0,0 +1,102 @@
+// Copyright 2025 The Heimdall authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !no_github
+
+package github
+
+import (
+	"reflect"
+
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+)
+
+// defaultPerPage is the page size used for fetching all pages, unless --per-page
+// is set. It is the maximum supported by most GitHub APIs.
+const defaultPerPage = 100
+
+// paginate invokes a list operation repeatedly, until there are no more pages
+// or maxItems is reached, and returns the merged items.
+// It follows both, page numbers and cursors (e.g., for code scanning alerts).
+func paginate(cfg *ghCfg, cmd *cobra.Command, inv Inv) (any, error) {
+	if cfg.perPage == 0 {
+		cfg.perPage = defaultPerPage
+	}
+
+	var items reflect.Value
+	for {
+		a, err := inv(cfg, cmd)
+		if err != nil {
+			return nil, err
+		}
+
+		is := listItems(a)
+		if !is.IsValid() {
+			// not a list, so there is nothing to merge
+			return a, nil
+		} else if !items.IsValid() {
+			items = reflect.MakeSlice(is.Type(), 0, is.Len())
+		}
+		items = reflect.AppendSlice(items, is)
+		log.Debug().Int("page", cfg.page).Str("after", cfg.after).Int("items", items.Len()).Msg("Fetched page")
+
+		if cfg.maxItems > 0 && items.Len() >= cfg.maxItems {
+			items = items.Slice(0, cfg.maxItems)
+			break
+		}
+		if !nextPage(cfg) {
+			break
+		}
+	}
+	return items.Interface(), nil
+}
+
+// nextPage updates the page number or cursor, if there is another page.
+// It stops, if the page number or cursor does not advance, e.g., because the
+// operation ignores it, to avoid fetching the same page forever.
+func nextPage(cfg *ghCfg) bool {
+	switch {
+	case cfg.resp == nil:
+		return false
+	case cfg.resp.NextPage > 0 && cfg.resp.NextPage != cfg.page:
+		cfg.page = cfg.resp.NextPage
+		return true
+	case cfg.resp.After != "" && cfg.resp.After != cfg.after:
+		cfg.after = cfg.resp.After
+		return true
+	}
+	return false
+}
+
+// listItems returns the items of a list result, which is either a slice or a
+// struct containing a slice (e.g., *github.Secrets).
+func listItems(a any) reflect.Value {
+	v := reflect.ValueOf(a)
+	if v.Kind() == reflect.Ptr {
+		v = v.Elem()
+	}
+
+	switch v.Kind() {
+	case reflect.Slice:
+		return v
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if f := v.Field(i); f.Kind() == reflect.Slice && v.Type().Field(i).IsExported() {
+				return f
+			}
+		}
+	}
+	return reflect.Value{}
+}
@@ -0,0 +1,70 @@
+// Copyright 2025 The Heimdall authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !no_github
+
+package github
+
+import (
+	"testing"
+
+	"github.com/abc-inc/heimdall/internal"
+	"github.com/google/go-github/v69/github"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPaginate(t *testing.T) {
+	pages := map[int][]string{1: {"a", "b"}, 2: {"c", "d"}, 3: {"e"}}
+	inv := func(cfg *ghCfg, cmd *cobra.Command) (any, error) {
+		p := max(cfg.page, 1)
+		cfg.resp = &github.Response{}
+		if p < len(pages) {
+			cfg.resp.NextPage = p + 1
+		}
+		return pages[p], nil
+	}
+
+	cfg := newGHCfg()
+	require.Equal(t, []string{"a", "b", "c", "d", "e"}, internal.Must(paginate(cfg, nil, inv)))
+	require.Equal(t, defaultPerPage, cfg.perPage)
+
+	cfg = newGHCfg()
+	cfg.maxItems = 3
+	require.Equal(t, []string{"a", "b", "c"}, internal.Must(paginate(cfg, nil, inv)))
+}
+
+func TestPaginateCursor(t *testing.T) {
+	cursors := map[string]string{"": "x", "x": "y", "y": ""}
+	inv := func(cfg *ghCfg, cmd *cobra.Command) (any, error) {
+		cfg.resp = &github.Response{After: cursors[cfg.after]}
+		return &github.Secrets{Secrets: []*github.Secret{{Name: "s" + cfg.after}}}, nil
+	}
+
+	ss := internal.Must(paginate(newGHCfg(), nil, inv)).([]*github.Secret)
+	require.Len(t, ss, 3)
+	require.Equal(t, "sy", ss[2].Name)
+}
+
+func TestPaginateNoProgress(t *testing.T) {
+	calls := 0
+	inv := func(cfg *ghCfg, cmd *cobra.Command) (any, error) {
+		calls++
+		cfg.resp = &github.Response{NextPage: 2}
+		return []string{"a"}, nil
+	}
+
+	require.Equal(t, []string{"a", "a"}, internal.Must(paginate(newGHCfg(), nil, inv)))
+	require.Equal(t, 2, calls)
+}
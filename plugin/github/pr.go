@@ -117,24 +117,24 @@ func execPR(cfg *ghCfg, cmd *cobra.Command) (x any, err error) {
 
 	switch cmd.Name() {
 	case "comment":
-		x, _, err = svc.GetComment(getCtx(cfg), cfg.owner, cfg.repo, cfg.id)
+		x, cfg.resp, err = svc.GetComment(getCtx(cfg), cfg.owner, cfg.repo, cfg.id)
 	case "comments":
-		x, _, err = svc.ListComments(getCtx(cfg), cfg.owner, cfg.repo, int(cfg.id), &github.PullRequestListCommentsOptions{
+		x, cfg.resp, err = svc.ListComments(getCtx(cfg), cfg.owner, cfg.repo, int(cfg.id), &github.PullRequestListCommentsOptions{
 			Sort:        *cfg.sort,
 			Direction:   *cfg.direction,
 			Since:       cfg.sinceTime,
 			ListOptions: github.ListOptions{Page: cfg.page, PerPage: cfg.perPage},
 		})
 	case "commits":
-		x, _, err = svc.ListCommits(getCtx(cfg), cfg.owner, cfg.repo, int(cfg.id),
+		x, cfg.resp, err = svc.ListCommits(getCtx(cfg), cfg.owner, cfg.repo, int(cfg.id),
 			&github.ListOptions{Page: cfg.page, PerPage: cfg.perPage})
 	case "files":
-		x, _, err = svc.ListFiles(getCtx(cfg), cfg.owner, cfg.repo, int(cfg.id),
+		x, cfg.resp, err = svc.ListFiles(getCtx(cfg), cfg.owner, cfg.repo, int(cfg.id),
 			&github.ListOptions{Page: cfg.page, PerPage: cfg.perPage})
 	case "get":
-		x, _, err = svc.Get(getCtx(cfg), cfg.owner, cfg.repo, int(cfg.id))
+		x, cfg.resp, err = svc.Get(getCtx(cfg), cfg.owner, cfg.repo, int(cfg.id))
 	case "list":
-		x, _, err = svc.List(getCtx(cfg), cfg.owner, cfg.repo, &github.PullRequestListOptions{
+		x, cfg.resp, err = svc.List(getCtx(cfg), cfg.owner, cfg.repo, &github.PullRequestListOptions{
 			State:       *cfg.state,
 			Head:        cfg.head,
 			Base:        cfg.base,
@@ -143,27 +143,27 @@ func execPR(cfg *ghCfg, cmd *cobra.Command) (x any, err error) {
 			ListOptions: github.ListOptions{Page: cfg.page, PerPage: cfg.perPage},
 		})
 	case "list-comments":
-		x, _, err = svc.ListComments(getCtx(cfg), cfg.owner, cfg.repo, int(cfg.id), &github.PullRequestListCommentsOptions{
+		x, cfg.resp, err = svc.ListComments(getCtx(cfg), cfg.owner, cfg.repo, int(cfg.id), &github.PullRequestListCommentsOptions{
 			Sort:        *cfg.sort,
 			Direction:   *cfg.direction,
 			Since:       cfg.sinceTime,
 			ListOptions: github.ListOptions{Page: cfg.page, PerPage: cfg.perPage},
 		})
 	case "pull-requests-with-commit":
-		x, _, err = svc.ListPullRequestsWithCommit(getCtx(cfg), cfg.owner, cfg.repo, cfg.sha,
+		x, cfg.resp, err = svc.ListPullRequestsWithCommit(getCtx(cfg), cfg.owner, cfg.repo, cfg.sha,
 			&github.ListOptions{Page: cfg.page, PerPage: cfg.perPage})
 	case "raw":
-		x, _, err = svc.GetRaw(getCtx(cfg), cfg.owner, cfg.repo, int(cfg.id), github.RawOptions{Type: github.Diff})
+		x, cfg.resp, err = svc.GetRaw(getCtx(cfg), cfg.owner, cfg.repo, int(cfg.id), github.RawOptions{Type: github.Diff})
 	case "review":
-		x, _, err = svc.GetReview(getCtx(cfg), cfg.owner, cfg.repo, int(cfg.id), cfg.reviewID)
+		x, cfg.resp, err = svc.GetReview(getCtx(cfg), cfg.owner, cfg.repo, int(cfg.id), cfg.reviewID)
 	case "reviews":
-		x, _, err = svc.ListReviews(getCtx(cfg), cfg.owner, cfg.repo, int(cfg.id),
+		x, cfg.resp, err = svc.ListReviews(getCtx(cfg), cfg.owner, cfg.repo, int(cfg.id),
 			&github.ListOptions{Page: cfg.page, PerPage: cfg.perPage})
 	case "review-comments":
-		x, _, err = svc.ListReviewComments(getCtx(cfg), cfg.owner, cfg.repo, int(cfg.id), cfg.reviewID,
+		x, cfg.resp, err = svc.ListReviewComments(getCtx(cfg), cfg.owner, cfg.repo, int(cfg.id), cfg.reviewID,
 			&github.ListOptions{Page: cfg.page, PerPage: cfg.perPage})
 	case "reviewers":
-		x, _, err = svc.ListReviewers(getCtx(cfg), cfg.owner, cfg.repo, int(cfg.id),
+		x, cfg.resp, err = svc.ListReviewers(getCtx(cfg), cfg.owner, cfg.repo, int(cfg.id),
 			&github.ListOptions{Page: cfg.page, PerPage: cfg.perPage})
 	}
 	return
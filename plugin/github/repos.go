@@ -227,39 +227,39 @@ func execRepos(cfg *ghCfg, cmd *cobra.Command) (x any, err error) {
 
 	switch cmd.Name() {
 	case "actions-access-level":
-		x, _, err = svc.GetActionsAccessLevel(getCtx(cfg), cfg.owner, cfg.repo)
+		x, cfg.resp, err = svc.GetActionsAccessLevel(getCtx(cfg), cfg.owner, cfg.repo)
 	case "actions-allowed":
-		x, _, err = svc.GetActionsAllowed(getCtx(cfg), cfg.owner, cfg.repo)
+		x, cfg.resp, err = svc.GetActionsAllowed(getCtx(cfg), cfg.owner, cfg.repo)
 	case "actions-permissions":
-		x, _, err = svc.GetActionsPermissions(getCtx(cfg), cfg.owner, cfg.repo)
+		x, cfg.resp, err = svc.GetActionsPermissions(getCtx(cfg), cfg.owner, cfg.repo)
 	case "admin-enforcement":
-		x, _, err = svc.GetAdminEnforcement(getCtx(cfg), cfg.owner, cfg.repo, *cfg.branch)
+		x, cfg.resp, err = svc.GetAdminEnforcement(getCtx(cfg), cfg.owner, cfg.repo, *cfg.branch)
 	case "all":
-		x, _, err = svc.ListAll(getCtx(cfg), &github.RepositoryListAllOptions{Since: cfg.sinceID})
+		x, cfg.resp, err = svc.ListAll(getCtx(cfg), &github.RepositoryListAllOptions{Since: cfg.sinceID})
 	case "all-custom-property-values":
-		x, _, err = svc.GetAllCustomPropertyValues(getCtx(cfg), cfg.owner, cfg.repo)
+		x, cfg.resp, err = svc.GetAllCustomPropertyValues(getCtx(cfg), cfg.owner, cfg.repo)
 	case "all-topics":
-		x, _, err = svc.ListAllTopics(getCtx(cfg), cfg.owner, cfg.repo)
+		x, cfg.resp, err = svc.ListAllTopics(getCtx(cfg), cfg.owner, cfg.repo)
 	case "archive-link":
-		x, _, err = svc.GetArchiveLink(getCtx(cfg), cfg.owner, cfg.repo, github.Tarball,
+		x, cfg.resp, err = svc.GetArchiveLink(getCtx(cfg), cfg.owner, cfg.repo, github.Tarball,
 			&github.RepositoryContentGetOptions{Ref: *cfg.branch}, int(cfg.id))
 	case "attestations":
-		x, _, err = svc.ListAttestations(getCtx(cfg), cfg.owner, cfg.repo, *cfg.subjectDigest,
+		x, cfg.resp, err = svc.ListAttestations(getCtx(cfg), cfg.owner, cfg.repo, *cfg.subjectDigest,
 			&github.ListOptions{Page: cfg.page, PerPage: cfg.perPage})
 	case "automated-security-fixes":
-		x, _, err = svc.GetAutomatedSecurityFixes(getCtx(cfg), cfg.owner, cfg.repo)
+		x, cfg.resp, err = svc.GetAutomatedSecurityFixes(getCtx(cfg), cfg.owner, cfg.repo)
 	case "branch":
-		x, _, err = svc.GetBranch(getCtx(cfg), cfg.owner, cfg.repo, *cfg.branch, 3)
+		x, cfg.resp, err = svc.GetBranch(getCtx(cfg), cfg.owner, cfg.repo, *cfg.branch, 3)
 	case "branch-protection":
-		x, _, err = svc.GetBranchProtection(getCtx(cfg), cfg.owner, cfg.repo, *cfg.branch)
+		x, cfg.resp, err = svc.GetBranchProtection(getCtx(cfg), cfg.owner, cfg.repo, *cfg.branch)
 	case "branches-head-commit":
-		x, _, err = svc.ListBranchesHeadCommit(getCtx(cfg), cfg.owner, cfg.repo, *cfg.branch)
+		x, cfg.resp, err = svc.ListBranchesHeadCommit(getCtx(cfg), cfg.owner, cfg.repo, *cfg.branch)
 	case "branches":
-		x, _, err = svc.ListBranches(getCtx(cfg), cfg.owner, cfg.repo, &github.BranchListOptions{
+		x, cfg.resp, err = svc.ListBranches(getCtx(cfg), cfg.owner, cfg.repo, &github.BranchListOptions{
 			Protected: cfg.protected, ListOptions: github.ListOptions{Page: cfg.page, PerPage: cfg.perPage},
 		})
 	case "by-authenticated-user":
-		x, _, err = svc.ListByAuthenticatedUser(getCtx(cfg), &github.RepositoryListByAuthenticatedUserOptions{
+		x, cfg.resp, err = svc.ListByAuthenticatedUser(getCtx(cfg), &github.RepositoryListByAuthenticatedUserOptions{
 			Visibility:  cfg.visibility,
 			Affiliation: cfg.affiliation,
 			Type:        cfg.typ,
@@ -268,35 +268,35 @@ func execRepos(cfg *ghCfg, cmd *cobra.Command) (x any, err error) {
 			ListOptions: github.ListOptions{Page: cfg.page, PerPage: cfg.perPage},
 		})
 	case "by-user":
-		x, _, err = svc.ListByUser(getCtx(cfg), cfg.user, &github.RepositoryListByUserOptions{
+		x, cfg.resp, err = svc.ListByUser(getCtx(cfg), cfg.user, &github.RepositoryListByUserOptions{
 			Type:        cfg.typ,
 			Sort:        *cfg.sort,
 			Direction:   *cfg.direction,
 			ListOptions: github.ListOptions{Page: cfg.page, PerPage: cfg.perPage},
 		})
 	case "collaborators":
-		x, _, err = svc.ListCollaborators(getCtx(cfg), cfg.owner, cfg.repo, &github.ListCollaboratorsOptions{
+		x, cfg.resp, err = svc.ListCollaborators(getCtx(cfg), cfg.owner, cfg.repo, &github.ListCollaboratorsOptions{
 			Affiliation: "",
 			Permission:  "",
 			ListOptions: github.ListOptions{Page: cfg.page, PerPage: cfg.perPage},
 		})
 	case "combined-status":
-		x, _, err = svc.GetCombinedStatus(getCtx(cfg), cfg.owner, cfg.repo, cfg.ref,
+		x, cfg.resp, err = svc.GetCombinedStatus(getCtx(cfg), cfg.owner, cfg.repo, cfg.ref,
 			&github.ListOptions{Page: cfg.page, PerPage: cfg.perPage})
 	case "comment":
-		x, _, err = svc.GetComment(getCtx(cfg), cfg.owner, cfg.repo, cfg.id)
+		x, cfg.resp, err = svc.GetComment(getCtx(cfg), cfg.owner, cfg.repo, cfg.id)
 	case "comments":
-		x, _, err = svc.ListComments(getCtx(cfg), cfg.owner, cfg.repo,
+		x, cfg.resp, err = svc.ListComments(getCtx(cfg), cfg.owner, cfg.repo,
 			&github.ListOptions{Page: cfg.page, PerPage: cfg.perPage})
 	case "commit-comments":
-		x, _, err = svc.ListCommitComments(getCtx(cfg), cfg.owner, cfg.repo, cfg.sha,
+		x, cfg.resp, err = svc.ListCommitComments(getCtx(cfg), cfg.owner, cfg.repo, cfg.sha,
 			&github.ListOptions{Page: cfg.page, PerPage: cfg.perPage})
 	case "commit-raw":
-		x, _, err = svc.GetCommitRaw(getCtx(cfg), cfg.owner, cfg.repo, cfg.sha, github.RawOptions{Type: github.Diff})
+		x, cfg.resp, err = svc.GetCommitRaw(getCtx(cfg), cfg.owner, cfg.repo, cfg.sha, github.RawOptions{Type: github.Diff})
 	case "commit-sha1":
-		x, _, err = svc.GetCommitSHA1(getCtx(cfg), cfg.owner, cfg.repo, cfg.ref, cfg.sha)
+		x, cfg.resp, err = svc.GetCommitSHA1(getCtx(cfg), cfg.owner, cfg.repo, cfg.ref, cfg.sha)
 	case "commits":
-		x, _, err = svc.ListCommits(getCtx(cfg), cfg.owner, cfg.repo, &github.CommitsListOptions{
+		x, cfg.resp, err = svc.ListCommits(getCtx(cfg), cfg.owner, cfg.repo, &github.CommitsListOptions{
 			SHA:         cfg.sha,
 			Path:        cfg.path,
 			Author:      cfg.author,
@@ -305,47 +305,47 @@ func execRepos(cfg *ghCfg, cmd *cobra.Command) (x any, err error) {
 			ListOptions: github.ListOptions{Page: cfg.page, PerPage: cfg.perPage},
 		})
 	case "compare-commits":
-		x, _, err = svc.CompareCommits(getCtx(cfg), cfg.owner, cfg.repo, cfg.base, cfg.head,
+		x, cfg.resp, err = svc.CompareCommits(getCtx(cfg), cfg.owner, cfg.repo, cfg.base, cfg.head,
 			&github.ListOptions{Page: cfg.page, PerPage: cfg.perPage})
 	case "compare-commits-raw":
-		x, _, err = svc.CompareCommitsRaw(getCtx(cfg), cfg.owner, cfg.repo, cfg.base, cfg.head, github.RawOptions{Type: github.Diff})
+		x, cfg.resp, err = svc.CompareCommitsRaw(getCtx(cfg), cfg.owner, cfg.repo, cfg.base, cfg.head, github.RawOptions{Type: github.Diff})
 	case "contents":
 		var dcs []*github.RepositoryContent
-		x, dcs, _, err = svc.GetContents(getCtx(cfg), cfg.owner, cfg.repo, cfg.path, &github.RepositoryContentGetOptions{Ref: cfg.ref})
+		x, dcs, cfg.resp, err = svc.GetContents(getCtx(cfg), cfg.owner, cfg.repo, cfg.path, &github.RepositoryContentGetOptions{Ref: cfg.ref})
 		if dcs != nil {
 			x = dcs
 		}
 	case "contributors":
-		x, _, err = svc.ListContributors(getCtx(cfg), cfg.owner, cfg.repo, &github.ListContributorsOptions{
+		x, cfg.resp, err = svc.ListContributors(getCtx(cfg), cfg.owner, cfg.repo, &github.ListContributorsOptions{
 			Anon:        "",
 			ListOptions: github.ListOptions{Page: cfg.page, PerPage: cfg.perPage},
 		})
 	case "contributors-stats":
-		x, _, err = svc.ListContributorsStats(getCtx(cfg), cfg.owner, cfg.repo)
+		x, cfg.resp, err = svc.ListContributorsStats(getCtx(cfg), cfg.owner, cfg.repo)
 	case "default-workflow-permissions":
-		x, _, err = svc.GetDefaultWorkflowPermissions(getCtx(cfg), cfg.owner, cfg.repo)
+		x, cfg.resp, err = svc.GetDefaultWorkflowPermissions(getCtx(cfg), cfg.owner, cfg.repo)
 	case "download-contents":
-		x, _, err = svc.DownloadContents(getCtx(cfg), cfg.owner, cfg.repo, cfg.path, &github.RepositoryContentGetOptions{Ref: cfg.ref})
+		x, cfg.resp, err = svc.DownloadContents(getCtx(cfg), cfg.owner, cfg.repo, cfg.path, &github.RepositoryContentGetOptions{Ref: cfg.ref})
 	case "download-contents-with-meta":
-		_, x, _, err = svc.DownloadContentsWithMeta(getCtx(cfg), cfg.owner, cfg.repo, cfg.path, &github.RepositoryContentGetOptions{Ref: cfg.ref})
+		_, x, cfg.resp, err = svc.DownloadContentsWithMeta(getCtx(cfg), cfg.owner, cfg.repo, cfg.path, &github.RepositoryContentGetOptions{Ref: cfg.ref})
 	case "environment":
-		x, _, err = svc.GetEnvironment(getCtx(cfg), cfg.owner, cfg.repo, cfg.name)
+		x, cfg.resp, err = svc.GetEnvironment(getCtx(cfg), cfg.owner, cfg.repo, cfg.name)
 	case "environments":
-		x, _, err = svc.ListEnvironments(getCtx(cfg), cfg.owner, cfg.repo, &github.EnvironmentListOptions{
+		x, cfg.resp, err = svc.ListEnvironments(getCtx(cfg), cfg.owner, cfg.repo, &github.EnvironmentListOptions{
 			ListOptions: github.ListOptions{Page: cfg.page, PerPage: cfg.perPage},
 		})
 	case "generate-release-notes":
-		x, _, err = svc.GenerateReleaseNotes(getCtx(cfg), cfg.owner, cfg.repo, &github.GenerateNotesOptions{TagName: cfg.tag})
+		x, cfg.resp, err = svc.GenerateReleaseNotes(getCtx(cfg), cfg.owner, cfg.repo, &github.GenerateNotesOptions{TagName: cfg.tag})
 	case "get":
-		x, _, err = svc.Get(getCtx(cfg), cfg.owner, cfg.repo)
+		x, cfg.resp, err = svc.Get(getCtx(cfg), cfg.owner, cfg.repo)
 	case "languages":
-		x, _, err = svc.ListLanguages(getCtx(cfg), cfg.owner, cfg.repo)
+		x, cfg.resp, err = svc.ListLanguages(getCtx(cfg), cfg.owner, cfg.repo)
 	case "latest-release":
-		x, _, err = svc.GetLatestRelease(getCtx(cfg), cfg.owner, cfg.repo)
+		x, cfg.resp, err = svc.GetLatestRelease(getCtx(cfg), cfg.owner, cfg.repo)
 	case "license":
-		x, _, err = svc.License(getCtx(cfg), cfg.owner, cfg.repo)
+		x, cfg.resp, err = svc.License(getCtx(cfg), cfg.owner, cfg.repo)
 	case "list":
-		x, _, err = svc.List(getCtx(cfg), cfg.owner, &github.RepositoryListOptions{
+		x, cfg.resp, err = svc.List(getCtx(cfg), cfg.owner, &github.RepositoryListOptions{
 			Visibility:  cfg.visibility,
 			Affiliation: cfg.affiliation,
 			Type:        cfg.typ,
@@ -354,54 +354,54 @@ func execRepos(cfg *ghCfg, cmd *cobra.Command) (x any, err error) {
 			ListOptions: github.ListOptions{Page: cfg.page, PerPage: cfg.perPage},
 		})
 	case "participation":
-		x, _, err = svc.ListParticipation(getCtx(cfg), cfg.owner, cfg.repo)
+		x, cfg.resp, err = svc.ListParticipation(getCtx(cfg), cfg.owner, cfg.repo)
 	case "permission-level":
-		x, _, err = svc.GetPermissionLevel(getCtx(cfg), cfg.owner, cfg.repo, cfg.user)
+		x, cfg.resp, err = svc.GetPermissionLevel(getCtx(cfg), cfg.owner, cfg.repo, cfg.user)
 	case "pre-receive-hook":
-		x, _, err = svc.GetPreReceiveHook(getCtx(cfg), cfg.owner, cfg.repo, cfg.id)
+		x, cfg.resp, err = svc.GetPreReceiveHook(getCtx(cfg), cfg.owner, cfg.repo, cfg.id)
 	case "pre-receive-hooks":
-		x, _, err = svc.ListPreReceiveHooks(getCtx(cfg), cfg.owner, cfg.repo,
+		x, cfg.resp, err = svc.ListPreReceiveHooks(getCtx(cfg), cfg.owner, cfg.repo,
 			&github.ListOptions{Page: cfg.page, PerPage: cfg.perPage})
 	case "pull-request-review-enforcement":
-		x, _, err = svc.GetPullRequestReviewEnforcement(getCtx(cfg), cfg.owner, cfg.repo, *cfg.branch)
+		x, cfg.resp, err = svc.GetPullRequestReviewEnforcement(getCtx(cfg), cfg.owner, cfg.repo, *cfg.branch)
 	case "readme":
-		x, _, err = svc.GetReadme(getCtx(cfg), cfg.owner, cfg.repo, &github.RepositoryContentGetOptions{Ref: cfg.ref})
+		x, cfg.resp, err = svc.GetReadme(getCtx(cfg), cfg.owner, cfg.repo, &github.RepositoryContentGetOptions{Ref: cfg.ref})
 	case "release":
-		x, _, err = svc.GetRelease(getCtx(cfg), cfg.owner, cfg.repo, cfg.id)
+		x, cfg.resp, err = svc.GetRelease(getCtx(cfg), cfg.owner, cfg.repo, cfg.id)
 	case "release-by-tag":
-		x, _, err = svc.GetReleaseByTag(getCtx(cfg), cfg.owner, cfg.repo, cfg.tag)
+		x, cfg.resp, err = svc.GetReleaseByTag(getCtx(cfg), cfg.owner, cfg.repo, cfg.tag)
 	case "release-asset":
-		x, _, err = svc.GetReleaseAsset(getCtx(cfg), cfg.owner, cfg.repo, cfg.id)
+		x, cfg.resp, err = svc.GetReleaseAsset(getCtx(cfg), cfg.owner, cfg.repo, cfg.id)
 	case "release-assets":
-		x, _, err = svc.ListReleaseAssets(getCtx(cfg), cfg.owner, cfg.repo, cfg.id,
+		x, cfg.resp, err = svc.ListReleaseAssets(getCtx(cfg), cfg.owner, cfg.repo, cfg.id,
 			&github.ListOptions{Page: cfg.page, PerPage: cfg.perPage},
 		)
 	case "releases":
-		x, _, err = svc.ListReleases(getCtx(cfg), cfg.owner, cfg.repo,
+		x, cfg.resp, err = svc.ListReleases(getCtx(cfg), cfg.owner, cfg.repo,
 			&github.ListOptions{Page: cfg.page, PerPage: cfg.perPage})
 	case "required-status-checks":
-		x, _, err = svc.GetRequiredStatusChecks(getCtx(cfg), cfg.owner, cfg.repo, *cfg.branch)
+		x, cfg.resp, err = svc.GetRequiredStatusChecks(getCtx(cfg), cfg.owner, cfg.repo, *cfg.branch)
 	case "rules-for-branch":
-		x, _, err = svc.GetRulesForBranch(getCtx(cfg), cfg.owner, cfg.repo, *cfg.branch)
+		x, cfg.resp, err = svc.GetRulesForBranch(getCtx(cfg), cfg.owner, cfg.repo, *cfg.branch)
 	case "statuses":
-		x, _, err = svc.ListStatuses(getCtx(cfg), cfg.owner, cfg.repo, cfg.ref,
+		x, cfg.resp, err = svc.ListStatuses(getCtx(cfg), cfg.owner, cfg.repo, cfg.ref,
 			&github.ListOptions{Page: cfg.page, PerPage: cfg.perPage})
 	case "tag-protection":
-		x, _, err = svc.ListTagProtection(getCtx(cfg), cfg.owner, cfg.repo)
+		x, cfg.resp, err = svc.ListTagProtection(getCtx(cfg), cfg.owner, cfg.repo)
 	case "tag-protections":
-		x, _, err = svc.ListTagProtection(getCtx(cfg), cfg.owner, cfg.repo)
+		x, cfg.resp, err = svc.ListTagProtection(getCtx(cfg), cfg.owner, cfg.repo)
 	case "tags":
-		x, _, err = svc.ListTags(getCtx(cfg), cfg.owner, cfg.repo, &github.ListOptions{Page: cfg.page, PerPage: cfg.perPage})
+		x, cfg.resp, err = svc.ListTags(getCtx(cfg), cfg.owner, cfg.repo, &github.ListOptions{Page: cfg.page, PerPage: cfg.perPage})
 	case "team-restrictions":
-		x, _, err = svc.ListTeamRestrictions(getCtx(cfg), cfg.owner, cfg.repo, *cfg.branch)
+		x, cfg.resp, err = svc.ListTeamRestrictions(getCtx(cfg), cfg.owner, cfg.repo, *cfg.branch)
 	case "teams":
-		x, _, err = svc.ListTeams(getCtx(cfg), cfg.owner, cfg.repo, &github.ListOptions{Page: cfg.page, PerPage: cfg.perPage})
+		x, cfg.resp, err = svc.ListTeams(getCtx(cfg), cfg.owner, cfg.repo, &github.ListOptions{Page: cfg.page, PerPage: cfg.perPage})
 	case "topics":
-		x, _, err = svc.ListAllTopics(getCtx(cfg), cfg.owner, cfg.repo)
+		x, cfg.resp, err = svc.ListAllTopics(getCtx(cfg), cfg.owner, cfg.repo)
 	case "user-restrictions":
-		x, _, err = svc.ListUserRestrictions(getCtx(cfg), cfg.owner, cfg.repo, *cfg.branch)
+		x, cfg.resp, err = svc.ListUserRestrictions(getCtx(cfg), cfg.owner, cfg.repo, *cfg.branch)
 	case "vulnerability-alerts":
-		x, _, err = svc.GetVulnerabilityAlerts(getCtx(cfg), cfg.owner, cfg.repo)
+		x, cfg.resp, err = svc.GetVulnerabilityAlerts(getCtx(cfg), cfg.owner, cfg.repo)
 	default:
 		panic("Unsupported operation: " + cmd.Name())
 	}
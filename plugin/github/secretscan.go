@@ -66,17 +66,17 @@ func execSecretScan(cfg *ghCfg, cmd *cobra.Command) (a any, err error) {
 
 	switch cmd.Name() {
 	case "alert":
-		a, _, err = svc.GetAlert(getCtx(cfg), cfg.owner, cfg.repo, cfg.id)
+		a, cfg.resp, err = svc.GetAlert(getCtx(cfg), cfg.owner, cfg.repo, cfg.id)
 	case "alerts-for-repo":
-		a, _, err = svc.ListAlertsForRepo(getCtx(cfg), cfg.owner, cfg.repo, &github.SecretScanningAlertListOptions{
+		a, cfg.resp, err = svc.ListAlertsForRepo(getCtx(cfg), cfg.owner, cfg.repo, &github.SecretScanningAlertListOptions{
 			State:             *cfg.state,
 			SecretType:        strings.Join(cfg.secretType, ","),
 			Resolution:        strings.Join(cfg.resolution, ","),
-			ListCursorOptions: github.ListCursorOptions{},
+			ListCursorOptions: github.ListCursorOptions{After: cfg.after},
 			ListOptions:       github.ListOptions{Page: cfg.page, PerPage: cfg.perPage},
 		})
 	case "locations-for-alert":
-		a, _, err = svc.ListLocationsForAlert(getCtx(cfg), cfg.owner, cfg.repo, cfg.id,
+		a, cfg.resp, err = svc.ListLocationsForAlert(getCtx(cfg), cfg.owner, cfg.repo, cfg.id,
 			&github.ListOptions{Page: cfg.page, PerPage: cfg.perPage})
 	default:
 		panic("Unsupported operation: " + cmd.Name())
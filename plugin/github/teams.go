@@ -139,45 +139,45 @@ func execTeams(cfg *ghCfg, cmd *cobra.Command) (x any, err error) {
 
 	switch cmd.Name() {
 	case "child-teams-by-parent-id":
-		x, _, err = svc.ListChildTeamsByParentID(context.Background(), cfg.orgID, cfg.teamID,
+		x, cfg.resp, err = svc.ListChildTeamsByParentID(context.Background(), cfg.orgID, cfg.teamID,
 			&github.ListOptions{Page: cfg.page, PerPage: cfg.perPage})
 	case "child-teams-by-parent-slug":
-		x, _, err = svc.ListChildTeamsByParentSlug(context.Background(), cfg.owner, cfg.slug,
+		x, cfg.resp, err = svc.ListChildTeamsByParentSlug(context.Background(), cfg.owner, cfg.slug,
 			&github.ListOptions{Page: cfg.page, PerPage: cfg.perPage})
 	case "comment-by-slug":
-		x, _, err = svc.GetCommentBySlug(context.Background(), cfg.owner, cfg.slug, cfg.discussionNumber, cfg.commentID)
+		x, cfg.resp, err = svc.GetCommentBySlug(context.Background(), cfg.owner, cfg.slug, cfg.discussionNumber, cfg.commentID)
 	case "comments-by-slug":
-		x, _, err = svc.ListCommentsBySlug(context.Background(), cfg.owner, cfg.slug, cfg.discussionNumber,
+		x, cfg.resp, err = svc.ListCommentsBySlug(context.Background(), cfg.owner, cfg.slug, cfg.discussionNumber,
 			&github.DiscussionCommentListOptions{Direction: defVal(cfg.direction), ListOptions: github.ListOptions{Page: cfg.page, PerPage: cfg.perPage}})
 	case "discussion-by-slug":
-		x, _, err = svc.GetDiscussionBySlug(context.Background(), cfg.owner, cfg.slug, cfg.discussionNumber)
+		x, cfg.resp, err = svc.GetDiscussionBySlug(context.Background(), cfg.owner, cfg.slug, cfg.discussionNumber)
 	case "discussions-by-slug":
-		x, _, err = svc.ListDiscussionsBySlug(context.Background(), cfg.owner, cfg.slug, &github.DiscussionListOptions{
+		x, cfg.resp, err = svc.ListDiscussionsBySlug(context.Background(), cfg.owner, cfg.slug, &github.DiscussionListOptions{
 			Direction: defVal(cfg.direction), ListOptions: github.ListOptions{Page: cfg.page, PerPage: cfg.perPage},
 		})
 	case "external-group":
-		x, _, err = svc.GetExternalGroup(context.Background(), cfg.owner, cfg.groupID)
+		x, cfg.resp, err = svc.GetExternalGroup(context.Background(), cfg.owner, cfg.groupID)
 	case "external-groups":
-		x, _, err = svc.ListExternalGroups(context.Background(), cfg.owner, &github.ListExternalGroupsOptions{
+		x, cfg.resp, err = svc.ListExternalGroups(context.Background(), cfg.owner, &github.ListExternalGroupsOptions{
 			DisplayName: cfg.displayName, ListOptions: github.ListOptions{Page: cfg.page, PerPage: cfg.perPage}})
 	case "external-groups-for-team-by-slug":
-		x, _, err = svc.ListExternalGroupsForTeamBySlug(context.Background(), cfg.owner, cfg.slug)
+		x, cfg.resp, err = svc.ListExternalGroupsForTeamBySlug(context.Background(), cfg.owner, cfg.slug)
 	case "idp-groups-for-team-by-slug":
-		x, _, err = svc.ListIDPGroupsForTeamBySlug(context.Background(), cfg.owner, cfg.slug)
+		x, cfg.resp, err = svc.ListIDPGroupsForTeamBySlug(context.Background(), cfg.owner, cfg.slug)
 	case "idp-groups-in-organization":
-		x, _, err = svc.ListIDPGroupsInOrganization(context.Background(), cfg.owner,
+		x, cfg.resp, err = svc.ListIDPGroupsInOrganization(context.Background(), cfg.owner,
 			&github.ListIDPGroupsOptions{ListCursorOptions: github.ListCursorOptions{Page: defVal(cfg.displayName), PerPage: cfg.perPage}})
 	case "team-members-by-slug":
-		x, _, err = svc.ListTeamMembersBySlug(context.Background(), cfg.owner, cfg.slug, &github.TeamListTeamMembersOptions{
+		x, cfg.resp, err = svc.ListTeamMembersBySlug(context.Background(), cfg.owner, cfg.slug, &github.TeamListTeamMembersOptions{
 			Role: cfg.role, ListOptions: github.ListOptions{Page: cfg.page, PerPage: cfg.perPage}})
 	case "team-membership-by-slug":
-		x, _, err = svc.GetTeamMembershipBySlug(context.Background(), cfg.owner, cfg.slug, cfg.user)
+		x, cfg.resp, err = svc.GetTeamMembershipBySlug(context.Background(), cfg.owner, cfg.slug, cfg.user)
 	case "team-repos-by-slug":
-		x, _, err = svc.ListTeamReposBySlug(context.Background(), cfg.owner, cfg.slug, &github.ListOptions{Page: cfg.page, PerPage: cfg.perPage})
+		x, cfg.resp, err = svc.ListTeamReposBySlug(context.Background(), cfg.owner, cfg.slug, &github.ListOptions{Page: cfg.page, PerPage: cfg.perPage})
 	case "teams":
-		x, _, err = svc.ListTeams(context.Background(), cfg.owner, &github.ListOptions{Page: cfg.page, PerPage: cfg.perPage})
+		x, cfg.resp, err = svc.ListTeams(context.Background(), cfg.owner, &github.ListOptions{Page: cfg.page, PerPage: cfg.perPage})
 	case "user-teams":
-		x, _, err = svc.ListUserTeams(context.Background(), &github.ListOptions{Page: cfg.page, PerPage: cfg.perPage})
+		x, cfg.resp, err = svc.ListUserTeams(context.Background(), &github.ListOptions{Page: cfg.page, PerPage: cfg.perPage})
 	}
 	return
 }
@@ -57,9 +57,12 @@ func execUsers(cfg *ghCfg, cmd *cobra.Command) (x any, err error) {
 
 	switch cmd.Name() {
 	case "all":
-		x, _, err = svc.ListAll(getCtx(cfg), nil)
+		x, cfg.resp, err = svc.ListAll(getCtx(cfg), &github.UserListOptions{
+			Since:       int64(cfg.page),
+			ListOptions: github.ListOptions{PerPage: cfg.perPage},
+		})
 	case "get":
-		x, _, err = svc.Get(getCtx(cfg), cfg.user)
+		x, cfg.resp, err = svc.Get(getCtx(cfg), cfg.user)
 	}
 	return
 }
@@ -23,6 +23,7 @@ import (
 	"github.com/MakeNowJust/heredoc/v2"
 	"github.com/PuerkitoBio/goquery"
 	"github.com/abc-inc/heimdall/cli"
+	"github.com/abc-inc/heimdall/errs"
 	"github.com/abc-inc/heimdall/internal"
 	"github.com/abc-inc/heimdall/res"
 	"github.com/rs/zerolog/log"
@@ -289,7 +290,9 @@ func handle(sel *goquery.Selection, f, v string) any {
 		return sel.ReplaceWithHtml(v)
 	case "set-attr":
 		key, val, ok := strings.Cut(v, "=")
-		internal.MustOkMsgf("", ok, "invalid key-value pair: %s", v)
+		if !ok {
+			errs.Abortf(errs.Usage, "invalid key-value pair: %s", v)
+		}
 		return sel.SetAttr(key, val)
 	case "set-html":
 		return sel.SetHtml(v)
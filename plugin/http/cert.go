@@ -17,6 +17,7 @@
 package http
 
 import (
+	"crypto/x509"
 	"encoding/json"
 	"encoding/pem"
 	"fmt"
@@ -25,8 +26,8 @@ import (
 	"time"
 
 	"github.com/abc-inc/heimdall/cli"
+	"github.com/abc-inc/heimdall/errs"
 	"github.com/abc-inc/heimdall/internal"
-	"github.com/rs/zerolog/log"
 	"github.com/spf13/cobra"
 	"github.com/square/certigo/lib"
 	"github.com/square/certigo/starttls"
@@ -53,15 +54,14 @@ func NewCertificateCmd() *cobra.Command {
 		Use:   "certificate [flags] <host>[:<port>]",
 		Short: "Verify SSL certificates",
 		Args:  cobra.ExactArgs(1),
-		Run: func(cmd *cobra.Command, args []string) {
+		RunE: func(cmd *cobra.Command, args []string) error {
 			cfg.connectTo = args[0]
 			res := verifyIt(cfg)
 			if res != nil {
 				cli.Fmtln(res)
-				if time.Now().AddDate(0, 0, int(cfg.expiresIn)).After(res.Certificates[0].NotAfter) {
-					os.Exit(2)
-				}
+				return checkExpiry(cfg, res.Certificates[0])
 			}
+			return nil
 		},
 	}
 
@@ -82,13 +82,13 @@ func NewCertificateCmd() *cobra.Command {
 func verifyIt(cfg certCfg) *Result {
 	result := Result{}
 	if cfg.startTLS == "" && cfg.identity != "" {
-		log.Fatal().Msg("--identity can only be used with --start-tls")
+		errs.Abortf(errs.Usage, "--identity can only be used with --start-tls")
 	}
 	connState, cri, err := starttls.GetConnectionState(
 		cfg.startTLS, cfg.sni, cfg.connectTo, cfg.identity,
 		"", "", nil, cfg.timeout)
 	if err != nil {
-		log.Fatal().Str("server", cfg.connectTo).Dur("timeout", cfg.timeout).Err(err).Msg("error connecting to server")
+		errs.Abort(errs.Newf(errs.Remote, "error connecting to server '%s' (timeout %s): %w", cfg.connectTo, cfg.timeout, err))
 	}
 
 	result.TLSConnectionState = connState
@@ -142,9 +142,18 @@ func verifyIt(cfg certCfg) *Result {
 			fmt.Printf("%s\n\n", lib.EncodeX509ToText(cert, termWidth, cfg.verbose))
 		}
 		lib.PrintVerifyResult(os.Stdout, *result.VerifyResult)
-		if time.Now().AddDate(0, 0, int(cfg.expiresIn)).After(result.Certificates[0].NotAfter) {
-			os.Exit(2)
-		}
+		internal.MustNoErr(checkExpiry(cfg, result.Certificates[0]))
+	}
+	return nil
+}
+
+// checkExpiry returns a policy violation, if the certificate expires within
+// the configured number of days. It keeps exit status 2, which --expires-in
+// has always used.
+func checkExpiry(cfg certCfg, cert *x509.Certificate) error {
+	if time.Now().AddDate(0, 0, int(cfg.expiresIn)).After(cert.NotAfter) {
+		err := fmt.Errorf("certificate expires on %s", cert.NotAfter.Format(time.DateOnly))
+		return &errs.Error{Kind: errs.Violation, Err: err, Code: 2}
 	}
 	return nil
 }
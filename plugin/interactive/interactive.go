@@ -60,7 +60,7 @@ func NewInteractiveCmd() *cobra.Command {
 
 const argsLabel = "Arguments"
 
-var outputs = []string{"csv", "json", "table", "text", "tsv", "yaml"}
+var outputs = []string{"csv", "json", "junit", "sarif", "table", "text", "tsv", "yaml"}
 var subCmd *cobra.Command
 var form *tview.Form
 var docs *tview.TextView
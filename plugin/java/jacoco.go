@@ -25,9 +25,9 @@ import (
 
 	"github.com/MakeNowJust/heredoc/v2"
 	"github.com/abc-inc/heimdall/cli"
+	"github.com/abc-inc/heimdall/errs"
 	"github.com/abc-inc/heimdall/internal"
 	"github.com/abc-inc/heimdall/res"
-	"github.com/rs/zerolog/log"
 	"github.com/spf13/cobra"
 )
 
@@ -97,7 +97,7 @@ func processJaCoCo(cfg jaCoCoCfg) (crs []CovRec) {
 		crs = append(crs, loadJaCoCoCSV(p, test)...)
 	}
 	if len(crs) == 0 {
-		log.Fatal().Strs("files", cfg.files).Msg("Cannot load JaCoCo report or files do not contain any matching lines")
+		errs.Abortf(errs.Input, "cannot load JaCoCo report or files do not contain any matching lines: %s", strings.Join(cfg.files, ", "))
 	}
 
 	if cfg.summary {
@@ -23,10 +23,10 @@ import (
 
 	"github.com/MakeNowJust/heredoc/v2"
 	"github.com/abc-inc/heimdall/cli"
+	"github.com/abc-inc/heimdall/errs"
 	"github.com/abc-inc/heimdall/internal"
 	"github.com/abc-inc/heimdall/res"
 	"github.com/pavlo-v-chernykh/keystore-go/v4"
-	"github.com/rs/zerolog/log"
 	"github.com/spf13/cobra"
 )
 
@@ -92,7 +92,7 @@ func readKeyStore(cfg keystoreCfg) keystore.KeyStore {
 
 	ks := keystore.New()
 	if err := ks.Load(f, cfg.password); err != nil {
-		log.Fatal().Err(err).Send()
+		errs.Abort(errs.Newf(errs.Input, "cannot load keystore '%s': %w", cfg.file, err))
 	}
 	return ks
 }
@@ -20,11 +20,11 @@ import (
 	"slices"
 
 	"github.com/abc-inc/heimdall/cli"
+	"github.com/abc-inc/heimdall/errs"
 	"github.com/abc-inc/heimdall/internal"
 	"github.com/abc-inc/heimdall/plugin/parse"
 	"github.com/clbanning/mxj/v2"
 	"github.com/gobwas/glob"
-	"github.com/rs/zerolog/log"
 	"github.com/spf13/cobra"
 )
 
@@ -61,7 +61,7 @@ func processWebXML(cfg webXMLCfg, uri string) any {
 	case "servlet-mappings":
 		return listServlets(m)
 	default:
-		log.Fatal().Str("mode", cfg.mode).Msg("unsupported operation")
+		errs.Abortf(errs.Usage, "unsupported operation: %s", cfg.mode)
 		return nil
 	}
 }
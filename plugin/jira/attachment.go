@@ -71,7 +71,7 @@ func NewAttachmentDownloadCmd() *cobra.Command {
 		`),
 		Args: cobra.ExactArgs(0),
 		Run: func(cmd *cobra.Command, args []string) {
-			client := internal.Must(newClient(cfg.apiURL, cfg.token))
+			client := internal.Must(newClient())
 			download(client, cfg)
 		},
 	}
@@ -101,7 +101,7 @@ func NewAttachmentsDownloadCmd() *cobra.Command {
 		`),
 		Args: cobra.ExactArgs(0),
 		Run: func(cmd *cobra.Command, args []string) {
-			client := internal.Must(newClient(cfg.apiURL, cfg.token))
+			client := internal.Must(newClient())
 			downloadMatching(client, cfg)
 		},
 	}
@@ -120,7 +120,7 @@ func NewAttachmentsListCmd() *cobra.Command {
 		Short: "List attachments from a Jira issue",
 		Args:  cobra.ExactArgs(0),
 		Run: func(cmd *cobra.Command, args []string) {
-			client := internal.Must(newClient(cfg.apiURL, cfg.token))
+			client := internal.Must(newClient())
 			cli.Fmtln(list(client, cfg))
 		},
 	}
@@ -139,7 +139,7 @@ func NewAttachmentUploadCmd() *cobra.Command {
 		Short: "Upload an attachment to a Jira issue",
 		Args:  cobra.ExactArgs(0),
 		Run: func(cmd *cobra.Command, args []string) {
-			client := internal.Must(newClient(cfg.apiURL, cfg.token))
+			client := internal.Must(newClient())
 			cli.Fmtln(upload(client, cfg))
 		},
 	}
@@ -165,7 +165,7 @@ func download(client *jira.Client, cfg jiraAttCfg) {
 		cfg.attId, cfg.attName = atts[0].ID, atts[0].Filename
 	}
 
-	resp := internal.Must(client.Issue.DownloadAttachment(cfg.attId))
+	resp := internal.Must(client.Issue.DownloadAttachmentWithContext(cli.Context(), cfg.attId))
 	defer func() { _ = resp.Body.Close() }()
 
 	if cfg.file == "-" {
@@ -188,7 +188,7 @@ func downloadMatching(client *jira.Client, cfg jiraAttCfg) {
 	}
 
 	atts := list(client, cfg)
-	internal.MustOkMsgf(atts, len(atts) > 0, "")
+	internal.MustOkMsgf(atts, len(atts) > 0, "no attachments found")
 	for _, att := range atts {
 		fileCfg := cfg
 		fileCfg.attId, fileCfg.file = att.ID, att.Filename
@@ -199,7 +199,7 @@ func downloadMatching(client *jira.Client, cfg jiraAttCfg) {
 // list returns a list of files attached to a Jira issue.
 func list(client *jira.Client, cfg jiraAttCfg) []*jira.Attachment {
 	filename := func(e *jira.Attachment) string { return e.Filename }
-	atts := handle(client.Issue.Get(cfg.keyOrID, &jira.GetQueryOptions{Fields: "attachment"})).Fields.Attachments
+	atts := handle(client.Issue.GetWithContext(cli.Context(), cfg.keyOrID, &jira.GetQueryOptions{Fields: "attachment"})).Fields.Attachments
 	matches := slices.Collect(res.Seq(res.MatchAny[*jira.Attachment](filename, cfg.attName), atts...))
 	if len(matches) > 1 {
 		slices.SortFunc(matches, func(a, b *jira.Attachment) int {
@@ -216,5 +216,5 @@ func upload(client *jira.Client, cfg jiraAttCfg) *[]jira.Attachment {
 	r := internal.Must(res.Open(cfg.file))
 	defer func() { _ = r.Close() }()
 
-	return handle(client.Issue.PostAttachment(cfg.keyOrID, r, filepath.Base(cfg.file)))
+	return handle(client.Issue.PostAttachmentWithContext(cli.Context(), cfg.keyOrID, r, filepath.Base(cfg.file)))
 }
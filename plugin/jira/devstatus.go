@@ -37,7 +37,7 @@ func NewDevStatusCmd() *cobra.Command {
 		Short: "Get details about the development status.",
 		Args:  cobra.ExactArgs(0),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			client, err := newClient(cfg.apiURL, cfg.token)
+			client, err := newClient()
 			if err != nil {
 				return err
 			}
@@ -58,7 +58,7 @@ func NewDevStatusCmd() *cobra.Command {
 }
 
 func getDetails(client *jira.Client, cfg jiraDevStatusCfg) (body map[string]any, resp *jira.Response, err error) {
-	req := internal.Must(client.NewRequest(http.MethodGet,
+	req := internal.Must(client.NewRequestWithContext(cli.Context(), http.MethodGet,
 		"/rest/dev-status/1.0/issue/detail?issueId="+cfg.issueID+"&applicationType=githube&dataType=repository", nil))
 	resp, err = client.Do(req, &body)
 	return
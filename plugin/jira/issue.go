@@ -56,8 +56,8 @@ func NewViewIssueCmd() *cobra.Command {
 		`),
 		Args: cobra.ExactArgs(1),
 		Run: func(cmd *cobra.Command, args []string) {
-			client := internal.Must(newClient(cfg.apiURL, cfg.token))
-			cli.Fmtln(handle(client.Issue.Get(args[0], &jira.GetQueryOptions{
+			client := internal.Must(newClient())
+			cli.Fmtln(handle(client.Issue.GetWithContext(cli.Context(), args[0], &jira.GetQueryOptions{
 				Fields: strings.Join(cfg.jiraCfg.opts.Fields, ","),
 				Expand: cfg.jiraCfg.opts.Expand,
 			})))
@@ -86,8 +86,8 @@ func NewListIssuesCmd() *cobra.Command {
 		`),
 		Args: cobra.ExactArgs(0),
 		Run: func(cmd *cobra.Command, args []string) {
-			client := internal.Must(newClient(cfg.apiURL, cfg.token))
-			cli.Fmtln(handle(client.Issue.Search(cfg.jql, cfg.opts)))
+			client := internal.Must(newClient())
+			cli.Fmtln(handle(client.Issue.SearchWithContext(cli.Context(), cfg.jql, cfg.opts)))
 		},
 	}
 
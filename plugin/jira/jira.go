@@ -18,28 +18,23 @@ package jira
 
 import (
 	"fmt"
-	"os"
 	"strings"
 	"time"
 
 	"github.com/abc-inc/heimdall/cli"
 	"github.com/abc-inc/heimdall/internal"
+	"github.com/abc-inc/heimdall/res"
 	"github.com/andygrunwald/go-jira"
 	"github.com/spf13/cobra"
 )
 
 type jiraCfg struct {
 	cli.OutCfg
-	apiURL string
-	token  string
-	opts   *jira.SearchOptions
+	opts *jira.SearchOptions
 }
 
 func newJiraCfg() *jiraCfg {
-	return &jiraCfg{
-		apiURL: os.Getenv("JIRA_API_URL"),
-		token:  os.Getenv("JIRA_TOKEN"),
-	}
+	return &jiraCfg{}
 }
 
 const envHelp = `
@@ -61,17 +56,21 @@ func NewJiraCmd() *cobra.Command {
 		NewIssueCmd(),
 		NewVersionCmd(),
 	)
+	cli.AddTokenFlag(cmd, "jira", "JIRA_TOKEN")
 
 	return cmd
 }
 
-// newClient creates a new Jira client.
-func newClient(apiURL, token string) (*jira.Client, error) {
+// newClient creates a new Jira client for the active profile or environment,
+// which retries failed requests and records or replays them, if enabled.
+// Each request times out after 30 seconds, unless the command is canceled earlier.
+func newClient() (*jira.Client, error) {
+	apiURL, token := cli.Setting("jira", "url", "JIRA_API_URL"), cli.Token("jira")
 	if apiURL == "" || token == "" {
 		return nil, fmt.Errorf("JIRA_API_URL and JIRA_TOKEN must be defined")
 	}
 
-	tp := jira.PATAuthTransport{Token: token}
+	tp := jira.PATAuthTransport{Token: token, Transport: res.Transport(nil)}
 	httpClient := tp.Client()
 	httpClient.Timeout = 30 * time.Second
 	return jira.NewClient(httpClient, baseURL(apiURL))
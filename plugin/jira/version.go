@@ -57,7 +57,7 @@ func NewVersionListCmd() *cobra.Command {
 		`),
 		Args: cobra.ExactArgs(0),
 		Run: func(cmd *cobra.Command, args []string) {
-			client := internal.Must(newClient(cfg.apiURL, cfg.token))
+			client := internal.Must(newClient())
 			cli.Fmtln(listVersions(client, cfg))
 		},
 	}
@@ -71,5 +71,5 @@ func NewVersionListCmd() *cobra.Command {
 
 // listVersions returns all versions of a given project.
 func listVersions(client *jira.Client, cfg jiraVersionCfg) []jira.Version {
-	return handle(client.Project.Get(cfg.project)).Versions
+	return handle(client.Project.GetWithContext(cli.Context(), cfg.project)).Versions
 }
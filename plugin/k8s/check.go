@@ -0,0 +1,232 @@
+// Copyright 2025 The Heimdall authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !no_k8s
+
+package k8s
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/MakeNowJust/heredoc/v2"
+	"github.com/abc-inc/heimdall/cli"
+	"github.com/abc-inc/heimdall/errs"
+	"github.com/spf13/cobra"
+)
+
+// Issue is a violation of a rule by a resource.
+type Issue struct {
+	RuleID    string `json:"rule_id" yaml:"rule_id"`
+	Level     string `json:"level" yaml:"level"`
+	Message   string `json:"message" yaml:"message"`
+	Kind      string `json:"kind" yaml:"kind"`
+	Namespace string `json:"namespace" yaml:"namespace"`
+	Name      string `json:"name" yaml:"name"`
+	Container string `json:"container,omitempty" yaml:"container,omitempty"`
+	File      string `json:"file" yaml:"file"`
+	StartLine int    `json:"start_line" yaml:"start_line"`
+}
+
+// container is a container of a pod template, and the pod-level settings.
+type container struct {
+	name    string
+	spec    map[string]any
+	pod     map[string]any
+	init    bool
+	service bool // whether the workload runs continuously, i.e., it is not a Job
+}
+
+type rule struct {
+	level string
+	desc  string
+	check func(c container) string
+}
+
+// rules maps rule IDs to checks, which return a message if a container violates
+// the rule.
+var rules = map[string]rule{
+	"latest-tag": {cli.LevelWarning, "Images must be pinned to a tag other than 'latest' or a digest", func(c container) string {
+		img := str(c.spec["image"])
+		if strings.Contains(img, "@") {
+			return ""
+		}
+		if i := strings.LastIndexByte(img, ':'); i < 0 || strings.Contains(img[i:], "/") {
+			return fmt.Sprintf("image '%s' has no tag", img)
+		} else if img[i+1:] == "latest" {
+			return fmt.Sprintf("image '%s' uses the 'latest' tag", img)
+		}
+		return ""
+	}},
+	"privileged": {cli.LevelError, "Containers must not run in privileged mode", func(c container) string {
+		if get(c.spec, "securityContext", "privileged") == true {
+			return "container runs in privileged mode"
+		}
+		return ""
+	}},
+	"probes": {cli.LevelWarning, "Long-running containers must define liveness and readiness probes", func(c container) string {
+		if c.init || !c.service {
+			return ""
+		}
+		var ps []string
+		for _, p := range []string{"livenessProbe", "readinessProbe"} {
+			if c.spec[p] == nil {
+				ps = append(ps, p)
+			}
+		}
+		if len(ps) > 0 {
+			return "container has no " + strings.Join(ps, " and ")
+		}
+		return ""
+	}},
+	"resource-limits": {cli.LevelWarning, "Containers must define CPU and memory limits", func(c container) string {
+		var rs []string
+		for _, r := range []string{"cpu", "memory"} {
+			if get(c.spec, "resources", "limits", r) == nil {
+				rs = append(rs, r)
+			}
+		}
+		if len(rs) > 0 {
+			return "container has no " + strings.Join(rs, " and ") + " limit"
+		}
+		return ""
+	}},
+	"run-as-root": {cli.LevelError, "Containers must not run as user 0", func(c container) string {
+		u := get(c.spec, "securityContext", "runAsUser")
+		if u == nil {
+			u = get(c.pod, "securityContext", "runAsUser")
+		}
+		if u == 0 {
+			return "container runs as user 0 (root)"
+		}
+		return ""
+	}},
+}
+
+type checkCfg struct {
+	k8sCfg
+	skip []string
+}
+
+func NewCheckCmd() *cobra.Command {
+	cfg := checkCfg{}
+
+	cmd := &cobra.Command{
+		Use:   "check [flags] [<path>...]",
+		Short: "Check workloads in manifests for common compliance issues",
+		Long: heredoc.Doc(`
+			Check the pod templates of workloads in Kubernetes manifests for common compliance issues.
+			Paths can be files containing multiple documents, directories, which are searched recursively for JSON and YAML files, or "-" for standard input (default).
+			Files in directories that are not valid manifests, e.g., Helm templates, are skipped.
+
+			The following rules are checked:
+		`) + ruleHelp() + heredoc.Doc(`
+
+			The command exits with status 1, if any issue is found.
+		`),
+		Example: heredoc.Doc(`
+			heimdall k8s check deploy/
+			heimdall k8s check --skip probes,latest-tag --output sarif deploy/ > k8s.sarif
+			kustomize build overlays/prod | heimdall k8s check --namespace shop
+		`),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			for _, s := range cfg.skip {
+				if _, ok := rules[s]; !ok {
+					return errs.Newf(errs.Usage, "unknown rule '%s', must be one of %s",
+						s, strings.Join(slices.Sorted(maps.Keys(rules)), ", "))
+				}
+			}
+
+			rs, err := LoadResources(args)
+			if err != nil {
+				return err
+			}
+			rs = cfg.filter(rs)
+			is := check(rs, cfg.skip)
+			cli.Fmtln(is)
+			if len(is) > 0 {
+				return errs.Newf(errs.Violation, "%d issues in %d resources", len(is), len(rs))
+			}
+			return nil
+		},
+	}
+
+	addFilterFlags(cmd, &cfg.k8sCfg)
+	cmd.Flags().StringSliceVar(&cfg.skip, "skip", nil, "Do not check the rule. May be provided multiple times.")
+	cli.AddOutputFlags(cmd, &cfg.OutCfg)
+	cmd.DisableFlagsInUseLine = true
+	return cmd
+}
+
+func ruleHelp() string {
+	var sb strings.Builder
+	for _, id := range slices.Sorted(maps.Keys(rules)) {
+		_, _ = fmt.Fprintf(&sb, "  %-16s %s (%s)\n", id, rules[id].desc, rules[id].level)
+	}
+	return sb.String()
+}
+
+// check applies all rules, which are not skipped, to the containers of all
+// workloads. Resources without pod template are ignored.
+func check(rs []Resource, skip []string) []Issue {
+	ids := slices.DeleteFunc(slices.Sorted(maps.Keys(rules)), func(id string) bool {
+		return slices.Contains(skip, id)
+	})
+
+	is := make([]Issue, 0)
+	for _, r := range rs {
+		for _, c := range containers(r) {
+			for _, id := range ids {
+				if msg := rules[id].check(c); msg != "" {
+					is = append(is, Issue{
+						RuleID: id, Level: rules[id].level, Message: msg,
+						Kind: r.Kind, Namespace: r.Namespace, Name: r.Name, Container: c.name,
+						File: r.File, StartLine: r.StartLine,
+					})
+				}
+			}
+		}
+	}
+	return is
+}
+
+// containers returns the containers and init containers of the pod template
+// of a workload.
+func containers(r Resource) (cs []container) {
+	var pod map[string]any
+	switch r.Kind {
+	case "Pod":
+		pod, _ = get(r.Object, "spec").(map[string]any)
+	case "CronJob":
+		pod, _ = get(r.Object, "spec", "jobTemplate", "spec", "template", "spec").(map[string]any)
+	default:
+		pod, _ = get(r.Object, "spec", "template", "spec").(map[string]any)
+	}
+	if pod == nil {
+		return nil
+	}
+
+	service := r.Kind != "Job" && r.Kind != "CronJob"
+	for _, k := range []string{"initContainers", "containers"} {
+		l, _ := pod[k].([]any)
+		for _, e := range l {
+			if m, ok := e.(map[string]any); ok {
+				cs = append(cs, container{name: str(m["name"]), spec: m, pod: pod, init: k == "initContainers", service: service})
+			}
+		}
+	}
+	return cs
+}
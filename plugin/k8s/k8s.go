@@ -0,0 +1,221 @@
+// Copyright 2025 The Heimdall authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !no_k8s
+
+package k8s
+
+import (
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/abc-inc/heimdall/cli"
+	"github.com/abc-inc/heimdall/errs"
+	"github.com/abc-inc/heimdall/res"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// Resource is a Kubernetes object read from a manifest.
+type Resource struct {
+	APIVersion string         `json:"api_version" yaml:"api_version"`
+	Kind       string         `json:"kind" yaml:"kind"`
+	Namespace  string         `json:"namespace" yaml:"namespace"`
+	Name       string         `json:"name" yaml:"name"`
+	File       string         `json:"file" yaml:"file"`
+	StartLine  int            `json:"start_line" yaml:"start_line"`
+	Object     map[string]any `json:"-" yaml:"-"`
+}
+
+type k8sCfg struct {
+	cli.OutCfg
+	kinds     []string
+	namespace string
+}
+
+func NewK8sCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "k8s <subcommand>",
+		Short:   "Inspect Kubernetes manifests",
+		GroupID: cli.SoftwareGroup,
+		Args:    cobra.ExactArgs(0),
+	}
+
+	cmd.AddCommand(
+		NewCheckCmd(),
+		NewResourcesCmd(),
+	)
+
+	return cmd
+}
+
+// addFilterFlags adds the flags to select resources by kind and namespace.
+func addFilterFlags(cmd *cobra.Command, cfg *k8sCfg) {
+	cmd.Flags().StringSliceVarP(&cfg.kinds, "kind", "k", nil, "Only include resources of the kind (case-insensitive). May be provided multiple times.")
+	cmd.Flags().StringVarP(&cfg.namespace, "namespace", "n", "", "Only include resources in the namespace")
+}
+
+// filter returns the resources matching the kind and namespace filters.
+func (cfg k8sCfg) filter(rs []Resource) []Resource {
+	return slices.DeleteFunc(rs, func(r Resource) bool {
+		if cfg.namespace != "" && r.Namespace != cfg.namespace {
+			return true
+		}
+		return len(cfg.kinds) > 0 && !slices.ContainsFunc(cfg.kinds, func(k string) bool {
+			return strings.EqualFold(k, r.Kind)
+		})
+	})
+}
+
+// LoadResources reads all resources from the given manifest files. Directories
+// are searched recursively for JSON and YAML files, and "-" denotes standard
+// input. Objects of kind "List" are expanded to their items. Files found in
+// directories that cannot be decoded are skipped with a warning.
+func LoadResources(paths []string) (rs []Resource, err error) {
+	if len(paths) == 0 {
+		paths = []string{"-"}
+	}
+
+	var files []string
+	walked := map[string]bool{}
+	for _, p := range paths {
+		if fi, err := os.Stat(p); p == "-" || err != nil || !fi.IsDir() {
+			files = append(files, p)
+			continue
+		}
+		err = filepath.WalkDir(p, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			switch strings.ToLower(filepath.Ext(path)) {
+			case ".json", ".yaml", ".yml":
+				if !d.IsDir() {
+					files, walked[path] = append(files, path), true
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, errs.New(errs.Input, err)
+		}
+	}
+
+	for _, f := range files {
+		frs, err := loadFile(f, walked[f])
+		if err != nil {
+			return nil, err
+		}
+		rs = append(rs, frs...)
+	}
+	return rs, nil
+}
+
+// loadFile returns the resources in a file. Files found in directories may
+// contain other data, e.g., Helm templates, hence invalid files and documents,
+// which are not objects, are skipped (lenient), unless the file is named
+// explicitly.
+func loadFile(name string, lenient bool) (rs []Resource, err error) {
+	log.Debug().Str("file", name).Msg("Loading manifest")
+	r, err := res.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = r.Close() }()
+
+	skip := func(err error) error {
+		if !lenient {
+			return err
+		}
+		log.Warn().Err(err).Msg("Skipping invalid manifest")
+		return nil
+	}
+
+	d := yaml.NewDecoder(r)
+	for {
+		var doc yaml.Node
+		if err = d.Decode(&doc); errors.Is(err, io.EOF) {
+			return rs, nil
+		} else if err != nil {
+			// the decoder cannot resume after a syntax error, hence the whole file is skipped
+			return nil, skip(errs.Newf(errs.Input, "cannot decode '%s': %w", name, err))
+		} else if len(doc.Content) == 0 {
+			continue
+		}
+
+		n := doc.Content[0]
+		r, err := toResource(n, name)
+		if err != nil {
+			if err = skip(err); err != nil {
+				return nil, err
+			}
+			continue
+		} else if r.Kind == "" {
+			log.Debug().Str("file", name).Int("line", n.Line).Msg("Skipping document without kind")
+			continue
+		} else if _, ok := r.Object["items"].([]any); !ok || !strings.HasSuffix(r.Kind, "List") {
+			rs = append(rs, r)
+			continue
+		}
+
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			if n.Content[i].Value != "items" {
+				continue
+			}
+			for _, in := range n.Content[i+1].Content {
+				if r, err = toResource(in, name); err != nil {
+					if err = skip(err); err != nil {
+						return nil, err
+					}
+					continue
+				}
+				rs = append(rs, r)
+			}
+		}
+	}
+}
+
+func toResource(n *yaml.Node, file string) (Resource, error) {
+	r := Resource{File: file, StartLine: n.Line}
+	if err := n.Decode(&r.Object); err != nil {
+		return r, errs.Newf(errs.Input, "cannot decode object in '%s' at line %d: %w", file, n.Line, err)
+	}
+	r.APIVersion = str(r.Object["apiVersion"])
+	r.Kind = str(r.Object["kind"])
+	r.Namespace = str(get(r.Object, "metadata", "namespace"))
+	r.Name = str(get(r.Object, "metadata", "name"))
+	return r, nil
+}
+
+// get returns the value at the path of keys, or nil if it does not exist.
+func get(v any, path ...string) any {
+	for _, p := range path {
+		m, ok := v.(map[string]any)
+		if !ok {
+			return nil
+		}
+		v = m[p]
+	}
+	return v
+}
+
+func str(v any) string {
+	s, _ := v.(string)
+	return s
+}
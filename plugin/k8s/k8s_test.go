@@ -0,0 +1,86 @@
+// Copyright 2025 The Heimdall authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !no_k8s
+
+package k8s_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/MakeNowJust/heredoc/v2"
+	"github.com/abc-inc/heimdall/errs"
+	"github.com/abc-inc/heimdall/internal"
+	"github.com/abc-inc/heimdall/plugin/k8s"
+	"github.com/abc-inc/heimdall/test"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadResources(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "list.yml"), []byte(heredoc.Doc(`
+		apiVersion: v1
+		kind: List
+		items:
+		  - apiVersion: v1
+		    kind: ConfigMap
+		    metadata:
+		      name: cfg
+		      namespace: shop
+		---
+	`)), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "README.md"), []byte("# not a manifest"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "values.json"), []byte(`[1, 2]`), 0o600))
+	tmpl := filepath.Join(dir, "template.yaml")
+	require.NoError(t, os.WriteFile(tmpl, []byte("{{- if .Values.enabled }}\nkind: ConfigMap\n{{- end }}\n"), 0o600))
+
+	rs, err := k8s.LoadResources([]string{filepath.Join(test.GetRootDir(), "testdata", "k8s"), dir})
+	require.NoError(t, err)
+
+	var names []string
+	for _, r := range rs {
+		names = append(names, r.Kind+"/"+r.Name)
+	}
+	require.Equal(t, []string{"Namespace/shop", "Deployment/web", "Service/web", "CronJob/cleanup", "ConfigMap/cfg"}, names)
+	require.Equal(t, 6, rs[1].StartLine)
+	require.Equal(t, 4, rs[4].StartLine)
+
+	// files named explicitly must be valid
+	for _, f := range []string{tmpl, filepath.Join(dir, "values.json")} {
+		_, err = k8s.LoadResources([]string{f})
+		require.Equal(t, errs.Input, errs.KindOf(err), f)
+	}
+}
+
+func TestNewResourcesCmd(t *testing.T) {
+	cmd := k8s.NewResourcesCmd()
+	internal.MustNoErr(cmd.Flags().Set("kind", "service,deployment"))
+	got := test.Run(`[.[].kind] | join(",")`, cmd, []string{filepath.Join(test.GetRootDir(), "testdata", "k8s", "app.yaml")})
+	require.Equal(t, "Deployment,Service", got)
+}
+
+func TestNewCheckCmd(t *testing.T) {
+	f := filepath.Join(test.GetRootDir(), "testdata", "k8s", "app.yaml")
+
+	cmd := k8s.NewCheckCmd()
+	require.ErrorIs(t, cmd.RunE(cmd, []string{f}), errs.ErrViolation)
+
+	internal.MustNoErr(cmd.Flags().Set("kind", "Deployment"))
+	require.NoError(t, cmd.RunE(cmd, []string{f}))
+
+	internal.MustNoErr(cmd.Flags().Set("skip", "unknown"))
+	require.ErrorIs(t, cmd.RunE(cmd, []string{f}), errs.ErrUsage)
+}
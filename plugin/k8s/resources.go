@@ -0,0 +1,52 @@
+// Copyright 2025 The Heimdall authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !no_k8s
+
+package k8s
+
+import (
+	"github.com/MakeNowJust/heredoc/v2"
+	"github.com/abc-inc/heimdall/cli"
+	"github.com/abc-inc/heimdall/internal"
+	"github.com/spf13/cobra"
+)
+
+func NewResourcesCmd() *cobra.Command {
+	cfg := k8sCfg{}
+
+	cmd := &cobra.Command{
+		Use:     "resources [flags] [<path>...]",
+		Aliases: []string{"res"},
+		Short:   "List the resources defined in manifests",
+		Long: heredoc.Doc(`
+			List the resources defined in Kubernetes manifests.
+			Paths can be files containing multiple documents, directories, which are searched recursively for JSON and YAML files, or "-" for standard input (default).
+			Files in directories that are not valid manifests, e.g., Helm templates, are skipped.
+		`),
+		Example: heredoc.Doc(`
+			heimdall k8s resources deploy/
+			heimdall k8s resources --kind Deployment,StatefulSet --namespace shop deploy/
+			helm template my-chart | heimdall k8s resources
+		`),
+		Run: func(cmd *cobra.Command, args []string) {
+			cli.Fmtln(cfg.filter(internal.Must(LoadResources(args))))
+		},
+	}
+
+	addFilterFlags(cmd, &cfg)
+	cli.AddOutputFlags(cmd, &cfg.OutCfg)
+	cmd.DisableFlagsInUseLine = true
+	return cmd
+}
@@ -0,0 +1,53 @@
+// Copyright 2023 The Heimdall authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !no_keyring
+
+package keyring
+
+import (
+	"errors"
+
+	"github.com/abc-inc/heimdall/cli"
+	"github.com/abc-inc/heimdall/errs"
+	"github.com/abc-inc/heimdall/internal"
+	"github.com/spf13/cobra"
+	"github.com/zalando/go-keyring"
+)
+
+func NewDeleteCmd() *cobra.Command {
+	cfg := keyringCfg{service: cli.KeyringService}
+	cmd := &cobra.Command{
+		Use:   "delete",
+		Short: "Delete a secret from the system keyring",
+		Args:  cobra.ExactArgs(0),
+		Run: func(cmd *cobra.Command, args []string) {
+			del(cfg)
+		},
+	}
+
+	cmd.Flags().StringVarP(&cfg.service, "service", "s", cfg.service, "Service / application name")
+	cmd.Flags().StringVarP(&cfg.user, "username", "u", cfg.user, "Username")
+
+	internal.MustNoErr(cmd.MarkFlagRequired("username"))
+	return cmd
+}
+
+func del(cfg keyringCfg) {
+	if err := cli.Keyring().Delete(cfg.service, cfg.user); errors.Is(err, keyring.ErrNotFound) {
+		errs.Abortf(errs.Input, "secret of user '%s' not found in service '%s'", cfg.user, cfg.service)
+	} else if err != nil {
+		internal.MustNoErr(err)
+	}
+}
@@ -23,7 +23,6 @@ import (
 	"github.com/abc-inc/heimdall/internal"
 	"github.com/mattn/go-isatty"
 	"github.com/spf13/cobra"
-	"github.com/zalando/go-keyring"
 )
 
 func NewGetCmd() *cobra.Command {
@@ -49,5 +48,5 @@ func NewGetCmd() *cobra.Command {
 }
 
 func get(cfg keyringCfg) string {
-	return internal.Must(keyring.Get(cfg.service, cfg.user))
+	return internal.Must(cli.Keyring().Get(cfg.service, cfg.user))
 }
@@ -31,7 +31,10 @@ func NewKeyringCmd() *cobra.Command {
 	}
 
 	cmd.AddCommand(
+		NewDeleteCmd(),
 		NewGetCmd(),
+		NewListCmd(),
+		NewSetCmd(),
 	)
 
 	return cmd
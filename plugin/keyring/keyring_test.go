@@ -0,0 +1,42 @@
+// Copyright 2025 The Heimdall authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !no_keyring
+
+package keyring
+
+import (
+	"testing"
+
+	"github.com/abc-inc/heimdall/cli"
+	"github.com/stretchr/testify/require"
+	"github.com/zalando/go-keyring"
+)
+
+func TestSetListDelete(t *testing.T) {
+	keyring.MockInit()
+	set(keyringCfg{service: cli.KeyringService, user: "default/github"}, "secret")
+	set(keyringCfg{service: cli.KeyringService, user: "ghes/jira"}, "secret")
+	require.Equal(t, "secret", get(keyringCfg{service: cli.KeyringService, user: "default/github"}))
+
+	es := list([]string{"default", "ghes"}, []string{"github", "jira"})
+	require.Equal(t, []Entry{
+		{Profile: "default", Service: "github", User: "default/github"},
+		{Profile: "ghes", Service: "jira", User: "ghes/jira"},
+	}, es)
+
+	del(keyringCfg{service: cli.KeyringService, user: "default/github"})
+	require.Len(t, list([]string{"default", "ghes"}, []string{"github", "jira"}), 1)
+	require.Panics(t, func() { del(keyringCfg{service: cli.KeyringService, user: "default/github"}) })
+}
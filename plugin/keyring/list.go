@@ -0,0 +1,79 @@
+// Copyright 2023 The Heimdall authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !no_keyring
+
+package keyring
+
+import (
+	"errors"
+	"maps"
+	"slices"
+
+	"github.com/abc-inc/heimdall/cli"
+	"github.com/abc-inc/heimdall/internal"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/zalando/go-keyring"
+)
+
+// Entry is a keyring entry holding the token of a service in a profile.
+type Entry struct {
+	Profile string `json:"profile" yaml:"profile"`
+	Service string `json:"service" yaml:"service"`
+	User    string `json:"user" yaml:"user"`
+}
+
+type keyringListCfg struct {
+	cli.OutCfg
+}
+
+func NewListCmd() *cobra.Command {
+	cfg := keyringListCfg{}
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List the service tokens stored in the system keyring",
+		Long: "List the service tokens stored in the system keyring for all profiles.\n" +
+			"Secrets are not included in the output.",
+		Args: cobra.ExactArgs(0),
+		Run: func(cmd *cobra.Command, args []string) {
+			cli.Fmtln(list(profiles(), cli.Services()))
+		},
+	}
+
+	cli.AddOutputFlags(cmd, &cfg.OutCfg)
+	return cmd
+}
+
+// list returns the entries of all combinations of profiles and services,
+// because keyrings cannot be searched in a portable way.
+func list(profiles, services []string) (es []Entry) {
+	for _, p := range profiles {
+		for _, s := range services {
+			u := p + "/" + s
+			if _, err := cli.Keyring().Get(cli.KeyringService, u); err == nil {
+				es = append(es, Entry{Profile: p, Service: s, User: u})
+			} else if !errors.Is(err, keyring.ErrNotFound) {
+				internal.MustNoErr(err)
+			}
+		}
+	}
+	return es
+}
+
+// profiles returns the default profile and the profiles in the config file.
+func profiles() []string {
+	ps := slices.Sorted(maps.Keys(viper.GetStringMap("profiles")))
+	return append([]string{cli.DefaultProfile}, slices.DeleteFunc(ps, func(p string) bool { return p == cli.DefaultProfile })...)
+}
@@ -0,0 +1,87 @@
+// Copyright 2023 The Heimdall authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !no_keyring
+
+package keyring
+
+import (
+	"errors"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/MakeNowJust/heredoc/v2"
+	"github.com/abc-inc/heimdall/cli"
+	"github.com/abc-inc/heimdall/errs"
+	"github.com/abc-inc/heimdall/internal"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+func NewSetCmd() *cobra.Command {
+	cfg := keyringCfg{service: cli.KeyringService}
+	cmd := &cobra.Command{
+		Use:   "set",
+		Short: "Store a secret in the system keyring",
+		Long: heredoc.Doc(`
+			Store a secret in the system keyring.
+
+			The secret is read from standard input, so that it does not appear in the shell history.
+			Tokens of services are looked up with the user '<profile>/<service>', e.g., 'default/github'.
+		`),
+		Example: heredoc.Doc(`
+			heimdall keyring set --username default/github
+			heimdall keyring set --username ghes/github < token.txt
+		`),
+		Args: cobra.ExactArgs(0),
+		Run: func(cmd *cobra.Command, args []string) {
+			set(cfg, readSecret())
+		},
+	}
+
+	cmd.Flags().StringVarP(&cfg.service, "service", "s", cfg.service, "Service / application name")
+	cmd.Flags().StringVarP(&cfg.user, "username", "u", cfg.user, "Username")
+
+	internal.MustNoErr(cmd.MarkFlagRequired("username"))
+	return cmd
+}
+
+func set(cfg keyringCfg, secret string) {
+	if secret == "" {
+		errs.Abortf(errs.Input, "secret must not be empty")
+	}
+	if err := cli.Keyring().Set(cfg.service, cfg.user, secret); err != nil {
+		internal.MustNoErr(err)
+	}
+}
+
+// readSecret prompts for the secret without echoing it, or reads it from
+// standard input, if it is not a terminal.
+func readSecret() string {
+	if cli.IO.IsStdinTTY() {
+		_ = internal.Must(cli.IO.ErrOut.Write([]byte("Secret: ")))
+		b, err := term.ReadPassword(int(os.Stdin.Fd()))
+		_ = internal.Must(cli.IO.ErrOut.Write([]byte("\n")))
+		if err != nil {
+			errs.Abort(errs.New(errs.Input, err))
+		}
+		return string(b)
+	}
+	b, err := io.ReadAll(cli.IO.In)
+	if err != nil && !errors.Is(err, io.EOF) {
+		errs.Abort(errs.New(errs.Input, err))
+	}
+	return strings.TrimRight(string(b), "\r\n")
+}
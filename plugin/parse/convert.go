@@ -0,0 +1,258 @@
+// Copyright 2025 The Heimdall authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !no_parse
+
+package parse
+
+import (
+	"fmt"
+	"maps"
+	"math"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/MakeNowJust/heredoc/v2"
+	"github.com/abc-inc/heimdall/cli"
+	"github.com/abc-inc/heimdall/errs"
+	"github.com/abc-inc/heimdall/internal"
+	"github.com/spf13/cobra"
+)
+
+type convertCfg struct {
+	from   string
+	to     string
+	expand bool
+	infer  bool
+}
+
+func NewConvertCmd() *cobra.Command {
+	cfg := convertCfg{}
+
+	cmd := &cobra.Command{
+		Use:   "convert [flags] --to <type> <file>...",
+		Short: "Convert files from one format to another",
+		Long: heredoc.Doc(`
+			Convert files from one format to another.
+			The following file formats are supported: ` + strings.Join(slices.Sorted(maps.Keys(Encoders)), ", ") + `
+
+			Multiple files are merged before they are converted.
+			Types are kept, if the target format supports them, e.g., numbers and booleans in JSON, TOML and YAML.
+			Nested values are flattened for formats that only support key-value pairs, i.e., properties and env.
+			Keys are joined with dots in properties files, and with underscores in env files.
+
+			Values read from formats without types, e.g., CSV, env, properties and XML, stay strings.
+			With --infer-types, strings that are booleans ("true", "false") or JSON numbers are converted.
+		`),
+		Example: heredoc.Doc(`
+			# turn Gradle properties into Helm values
+			heimdall parse convert --expand --to yaml gradle.properties > values.yaml
+
+			# turn Gradle properties into JSON with numbers and booleans
+			heimdall parse convert --infer-types --to json gradle.properties
+
+			# turn a dotenv file into a properties file
+			heimdall parse convert --to properties .env > application.properties
+
+			# read a JSON file without extension
+			heimdall parse convert --from json --to toml config
+		`),
+		Args: cobra.MinimumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			enc, ok := Encoders[cfg.to]
+			if !ok {
+				errs.Abortf(errs.Usage, "unsupported target type '%s', must be one of: %s",
+					cfg.to, strings.Join(slices.Sorted(maps.Keys(Encoders)), ", "))
+			}
+			v := internal.Must(processFiles(args, cfg.from))
+			if cfg.infer {
+				v = inferTypes(v)
+			}
+			if cfg.expand {
+				v = expandKeys(v)
+			}
+			internal.MustNoErr(enc(cli.IO.Out, normalize(v)))
+		},
+	}
+
+	cmd.Flags().StringVarP(&cfg.from, "from", "f", "", "Type of files without (known) extension")
+	cmd.Flags().StringVarP(&cfg.to, "to", "t", "", "Type of the output (required)")
+	cmd.Flags().BoolVarP(&cfg.expand, "expand", "e", false, `Split keys at dots into nested objects, e.g., "a.b=c" becomes {"a": {"b": "c"}}`)
+	cmd.Flags().BoolVar(&cfg.infer, "infer-types", false, `Convert strings into booleans and numbers, e.g., "8080" becomes 8080`)
+	internal.MustNoErr(cmd.MarkFlagRequired("to"))
+	cmd.DisableFlagsInUseLine = true
+	return cmd
+}
+
+// normalize converts integral floating point numbers into integers, because
+// JSON decoders cannot distinguish them. Moreover, arrays of tables decoded
+// from TOML are converted into arrays of values.
+func normalize(v any) any {
+	switch v := v.(type) {
+	case float64:
+		if v == math.Trunc(v) && math.Abs(v) < 1<<53 {
+			return int64(v)
+		}
+	case []map[string]any:
+		a := make([]any, len(v))
+		for i, e := range v {
+			a[i] = normalize(e)
+		}
+		return a
+	case []any:
+		for i, e := range v {
+			v[i] = normalize(e)
+		}
+	case map[string]any:
+		for k, e := range v {
+			v[k] = normalize(e)
+		}
+	}
+	return v
+}
+
+var numberRe = regexp.MustCompile(`^-?(0|[1-9][0-9]*)(\.[0-9]+)?([eE][+-]?[0-9]+)?$`)
+
+// inferTypes converts strings, which are booleans or JSON numbers, into bool,
+// int64 and float64 values. Numbers with leading zeros, e.g., "007", and
+// integers out of range remain strings.
+func inferTypes(v any) any {
+	switch v := v.(type) {
+	case string:
+		switch {
+		case v == "true" || v == "false":
+			return v == "true"
+		case !numberRe.MatchString(v):
+			return v
+		case !strings.ContainsAny(v, ".eE"):
+			if i, err := strconv.ParseInt(v, 10, 64); err == nil {
+				return i
+			}
+		default:
+			if f, err := strconv.ParseFloat(v, 64); err == nil {
+				return f
+			}
+		}
+		return v
+	case []string:
+		a := make([]any, len(v))
+		for i, e := range v {
+			a[i] = inferTypes(e)
+		}
+		return a
+	case [][]string:
+		a := make([]any, len(v))
+		for i, e := range v {
+			a[i] = inferTypes(e)
+		}
+		return a
+	case []any:
+		for i, e := range v {
+			v[i] = inferTypes(e)
+		}
+	case map[string]any:
+		for k, e := range v {
+			v[k] = inferTypes(e)
+		}
+	case map[string][]string:
+		m := make(map[string]any, len(v))
+		for k, e := range v {
+			m[k] = inferTypes(e)
+		}
+		return m
+	}
+	return v
+}
+
+// expandKeys splits the keys of the top-level object at dots, and merges the
+// values into nested objects. If a key is both a value and a parent, e.g.,
+// "a=1" and "a.b=2", the value is kept under an empty key.
+func expandKeys(v any) any {
+	m, ok := v.(map[string]any)
+	if !ok {
+		return v
+	}
+
+	res := make(map[string]any)
+	for _, k := range slices.Sorted(maps.Keys(m)) {
+		cur := res
+		ps := strings.Split(k, ".")
+		for _, p := range ps[:len(ps)-1] {
+			switch c := cur[p].(type) {
+			case map[string]any:
+				cur = c
+			case nil:
+				n := make(map[string]any)
+				cur[p], cur = n, n
+			default:
+				n := map[string]any{"": c}
+				cur[p], cur = n, n
+			}
+		}
+		if c, ok := cur[ps[len(ps)-1]].(map[string]any); ok {
+			c[""] = m[k]
+		} else {
+			cur[ps[len(ps)-1]] = m[k]
+		}
+	}
+	return res
+}
+
+// flatten converts nested values into key-value pairs. Keys are joined with
+// sep, and array indices are appended in brackets, e.g., "a.b[0]".
+func flatten(v any, sep string) map[string]string {
+	res := make(map[string]string)
+	var walk func(p string, v any)
+	walk = func(p string, v any) {
+		switch v := v.(type) {
+		case map[string]any:
+			for k, e := range v {
+				if p != "" && k != "" {
+					k = p + sep + k
+				} else if p != "" {
+					k = p
+				}
+				walk(k, e)
+			}
+		case []any:
+			for i, e := range v {
+				walk(p+"["+strconv.Itoa(i)+"]", e)
+			}
+		case [][]string:
+			for i, r := range v {
+				for j, e := range r {
+					walk(p+"["+strconv.Itoa(i)+"]["+strconv.Itoa(j)+"]", e)
+				}
+			}
+		case nil:
+			res[p] = ""
+		case time.Time:
+			res[p] = v.Format(time.RFC3339Nano)
+		default:
+			res[p] = fmt.Sprint(v)
+		}
+	}
+	walk("", v)
+	return res
+}
+
+var envKeyRe = regexp.MustCompile(`[^A-Za-z0-9_]+_?`)
+
+// envKey replaces characters, which are not allowed in variable names.
+func envKey(k string) string {
+	return strings.TrimRight(envKeyRe.ReplaceAllString(k, "_"), "_")
+}
@@ -0,0 +1,107 @@
+// Copyright 2025 The Heimdall authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !no_parse
+
+package parse_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/MakeNowJust/heredoc/v2"
+	"github.com/abc-inc/heimdall/plugin/parse"
+	"github.com/abc-inc/heimdall/test"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewConvertCmd(t *testing.T) {
+	f := filepath.Join(t.TempDir(), "gradle.properties")
+	require.NoError(t, os.WriteFile(f, []byte("app.name=demo\napp.port=8080\norg.gradle.jvmargs=-Xmx2g\n"), 0o600))
+
+	cmd := parse.NewConvertCmd()
+	require.NoError(t, cmd.Flags().Set("to", "yaml"))
+	require.NoError(t, cmd.Flags().Set("expand", "true"))
+	require.Equal(t, heredoc.Doc(`
+		app:
+		  name: demo
+		  port: "8080"
+		org:
+		  gradle:
+		    jvmargs: -Xmx2g`), test.Run("", cmd, []string{f}))
+
+	cmd = parse.NewConvertCmd()
+	require.NoError(t, cmd.Flags().Set("to", "env"))
+	require.Equal(t, heredoc.Doc(`
+		app_name=demo
+		app_port=8080
+		org_gradle_jvmargs=-Xmx2g`), test.Run("", cmd, []string{f}))
+}
+
+func TestEncoders(t *testing.T) {
+	v := map[string]any{
+		"name": "a b",
+		"port": int64(8080),
+		"libs": []any{map[string]any{"id": "x"}},
+	}
+	tests := map[string]string{
+		"json":       "{\n  \"libs\": [\n    {\n      \"id\": \"x\"\n    }\n  ],\n  \"name\": \"a b\",\n  \"port\": 8080\n}\n",
+		"yaml":       "libs:\n  - id: x\nname: a b\nport: 8080\n",
+		"toml":       "name = \"a b\"\nport = 8080\n\n[[libs]]\nid = \"x\"\n",
+		"xml":        "<?xml version=\"1.0\" encoding=\"UTF-8\"?>\n<doc>\n  <libs>\n    <id>x</id>\n  </libs>\n  <name>a b</name>\n  <port>8080</port>\n</doc>\n",
+		"properties": "libs[0].id=x\nname=a b\nport=8080\n",
+		"env":        "libs_0_id=x\nname='a b'\nport=8080\n",
+	}
+	for typ, want := range tests {
+		t.Run(typ, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			require.NoError(t, parse.Encoders[typ](buf, v))
+			require.Equal(t, want, buf.String())
+
+			got, err := parse.Decoders[typ](buf)
+			require.NoError(t, err)
+			require.NotEmpty(t, got)
+		})
+	}
+
+	buf := &bytes.Buffer{}
+	require.NoError(t, parse.Encoders["csv"](buf, []any{map[string]any{"a": 1, "b": "x,y"}, map[string]any{"a": 2}}))
+	require.Equal(t, "a,b\n1,\"x,y\"\n2,\n", buf.String())
+}
+
+func TestNewConvertCmd_InferTypes(t *testing.T) {
+	f := filepath.Join(t.TempDir(), ".env")
+	require.NoError(t, os.WriteFile(f, []byte("DEBUG=true\nPORT=8080\nRATIO=0.5\nZIP=01234\nVERSION=1.2.3\n"), 0o600))
+
+	cmd := parse.NewConvertCmd()
+	require.NoError(t, cmd.Flags().Set("to", "yaml"))
+	require.Equal(t, heredoc.Doc(`
+		DEBUG: "true"
+		PORT: "8080"
+		RATIO: "0.5"
+		VERSION: 1.2.3
+		ZIP: "01234"`), test.Run("", cmd, []string{f}))
+
+	cmd = parse.NewConvertCmd()
+	require.NoError(t, cmd.Flags().Set("to", "yaml"))
+	require.NoError(t, cmd.Flags().Set("infer-types", "true"))
+	require.Equal(t, heredoc.Doc(`
+		DEBUG: true
+		PORT: 8080
+		RATIO: 0.5
+		VERSION: 1.2.3
+		ZIP: "01234"`), test.Run("", cmd, []string{f}))
+}
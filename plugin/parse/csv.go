@@ -19,9 +19,13 @@ package parse
 import (
 	"encoding/csv"
 	"errors"
+	"fmt"
 	"io"
+	"maps"
+	"slices"
 	"unicode/utf8"
 
+	"github.com/abc-inc/heimdall/errs"
 	"github.com/abc-inc/heimdall/internal"
 	"github.com/spf13/viper"
 )
@@ -79,6 +83,63 @@ func decodeCSVRecords(r io.Reader) (any, error) {
 	return m, nil
 }
 
+// encodeCSV writes records, i.e., arrays of arrays, or an array of objects.
+// In the latter case, the header consists of the keys of all objects.
+func encodeCSV(w io.Writer, v any) error {
+	var recs [][]string
+	switch v := v.(type) {
+	case [][]string:
+		recs = v
+	case []any:
+		var keys []string
+		for _, e := range v {
+			if m, ok := e.(map[string]any); ok {
+				for _, k := range slices.Sorted(maps.Keys(m)) {
+					if !slices.Contains(keys, k) {
+						keys = append(keys, k)
+					}
+				}
+			}
+		}
+		if len(keys) > 0 {
+			recs = append(recs, keys)
+		}
+		for _, e := range v {
+			var rec []string
+			switch e := e.(type) {
+			case map[string]any:
+				for _, k := range keys {
+					rec = append(rec, csvField(e[k]))
+				}
+			case []any:
+				for _, f := range e {
+					rec = append(rec, csvField(f))
+				}
+			default:
+				return errs.Newf(errs.Input, "CSV records must be arrays or objects, not %T", e)
+			}
+			recs = append(recs, rec)
+		}
+	default:
+		return errs.Newf(errs.Input, "CSV documents must be arrays, not %T", v)
+	}
+
+	c := csv.NewWriter(w)
+	if d := viper.GetString("csv-delimiter"); len(d) >= 1 {
+		ru, _ := utf8.DecodeRuneInString(d)
+		c.Comma = ru
+	}
+	return c.WriteAll(recs)
+}
+
+func csvField(v any) string {
+	if v == nil {
+		return ""
+	}
+	return fmt.Sprint(v)
+}
+
 func init() {
 	Decoders["csv"] = decodeCSVRecords
+	Encoders["csv"] = encodeCSV
 }
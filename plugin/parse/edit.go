@@ -0,0 +1,369 @@
+// Copyright 2025 The Heimdall authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !no_parse
+
+package parse
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/MakeNowJust/heredoc/v2"
+	"github.com/abc-inc/heimdall/cli"
+	"github.com/abc-inc/heimdall/errs"
+	"github.com/abc-inc/heimdall/internal"
+	"github.com/abc-inc/heimdall/res"
+	"github.com/spf13/cobra"
+)
+
+// Edit is a modification of a document. If Delete is false, the value at the
+// path is set to Value, otherwise it is removed.
+type Edit struct {
+	Path   []PathElem
+	Value  any
+	Delete bool
+}
+
+// PathElem is an element of a path, i.e., either a key or an array index.
+// Keys, which look like numbers, e.g., "0" in 'a.0' or 'a."0"', are no indices.
+type PathElem struct {
+	Key     string
+	Index   int
+	IsIndex bool
+}
+
+// Key returns a path element denoting the key of an object.
+func Key(k string) PathElem {
+	return PathElem{Key: k}
+}
+
+// Index returns a path element denoting the index of an array.
+func Index(i int) PathElem {
+	return PathElem{Index: i, IsIndex: true}
+}
+
+// Keys returns a path consisting of the given keys.
+func Keys(ks ...string) []PathElem {
+	ps := make([]PathElem, len(ks))
+	for i, k := range ks {
+		ps[i] = Key(k)
+	}
+	return ps
+}
+
+func (p PathElem) String() string {
+	if p.IsIndex {
+		return strconv.Itoa(p.Index)
+	}
+	return p.Key
+}
+
+// Editor applies an edit to a document and returns the modified document.
+// Editors modify the document in place as far as possible, i.e., comments, key
+// order and formatting are preserved.
+type Editor func(b []byte, e Edit) ([]byte, error)
+
+var Editors = make(map[string]Editor)
+
+type editCfg struct {
+	set     []string
+	del     []string
+	inPlace bool
+}
+
+func NewEditCmd() *cobra.Command {
+	cfg := editCfg{}
+
+	cmd := &cobra.Command{
+		Use:   "edit [flags] <file>...",
+		Short: "Set or delete values in files, keeping comments and formatting",
+		Long: heredoc.Doc(`
+			Set or delete values in files, keeping comments and formatting.
+			The following file formats are supported: json, properties, toml, xml, yaml
+
+			A path consists of keys separated by dots, and array indices, e.g., "jobs.build.steps[0].uses".
+			Keys containing dots can be quoted, e.g., 'plugins."com.example".version'.
+			Missing arrays are created for indices, e.g., "hosts[0]", whereas "hosts.0" denotes the key "0".
+			In XML files, the path starts with the root element, and attributes are prefixed with "-", e.g., "web-app.-version".
+			YAML files must contain a single document.
+
+			Values are parsed as JSON, if possible, e.g., 'true', '42' or '["a", "b"]'. Otherwise, they are strings.
+			Hence, a string looking like a number must be quoted, e.g., 'version="2"'.
+
+			Deletions are applied before values are set.
+			Without --in-place, the modified file is written to standard output.
+		`),
+		Example: heredoc.Doc(`
+			# bump the version of a library in a Gradle version catalog
+			heimdall parse edit --in-place --set versions.junit=5.11.0 gradle/libs.versions.toml
+
+			# enable a feature and remove an obsolete setting in a YAML config
+			heimdall parse edit --set features.audit=true --delete legacy.mode config.yaml
+		`),
+		Args: cobra.MinimumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			if len(args) > 1 && !cfg.inPlace {
+				errs.Abortf(errs.Usage, "multiple files can only be edited with --in-place")
+			}
+			es := internal.Must(parseEdits(cfg))
+			for _, f := range args {
+				internal.MustNoErr(editFile(f, es, cfg.inPlace))
+			}
+		},
+	}
+
+	cmd.Flags().StringArrayVarP(&cfg.set, "set", "s", nil, "Set the value at the path (<path>=<value>). May be provided multiple times.")
+	cmd.Flags().StringArrayVarP(&cfg.del, "delete", "d", nil, "Delete the value at the path. May be provided multiple times.")
+	cmd.Flags().BoolVarP(&cfg.inPlace, "in-place", "i", cfg.inPlace, "Modify the files instead of writing to standard output")
+	cmd.DisableFlagsInUseLine = true
+	return cmd
+}
+
+// parseEdits converts the flags into edits. Deletions come first.
+func parseEdits(cfg editCfg) (es []Edit, err error) {
+	for _, p := range cfg.del {
+		ps, err := ParsePath(p)
+		if err != nil {
+			return nil, err
+		}
+		es = append(es, Edit{Path: ps, Delete: true})
+	}
+	for _, kv := range cfg.set {
+		p, v, ok := cutPathValue(kv)
+		if !ok {
+			return nil, errs.Newf(errs.Usage, "invalid assignment '%s', must be <path>=<value>", kv)
+		}
+		ps, err := ParsePath(p)
+		if err != nil {
+			return nil, err
+		}
+		es = append(es, Edit{Path: ps, Value: ParseValue(v)})
+	}
+	if len(es) == 0 {
+		return nil, errs.Newf(errs.Usage, "at least one of --set or --delete is required")
+	}
+	return es, nil
+}
+
+func editFile(name string, es []Edit, inPlace bool) error {
+	i := SplitNamePrefixType(name)
+	ed, ok := Editors[i.Type]
+	if !ok {
+		return errs.Newf(errs.Usage, "editing files of type '%s' is not supported", i.Type)
+	} else if inPlace && i.File == "-" {
+		return errs.Newf(errs.Usage, "standard input cannot be edited in place")
+	}
+
+	r, err := res.Open(i.File)
+	if err != nil {
+		return err
+	}
+	b, err := io.ReadAll(r)
+	_ = r.Close()
+	if err != nil {
+		return errs.New(errs.Input, err)
+	}
+
+	for _, e := range es {
+		if b, err = ed(b, e); err != nil {
+			return errs.Newf(errs.KindOf(err), "cannot edit '%s' in '%s': %w", FormatPath(e.Path), i.File, err)
+		}
+	}
+	// An editor must never write a file, which cannot be read afterward.
+	if dec, ok := Decoders[i.Type]; ok {
+		if _, err = dec(bytes.NewReader(b)); err != nil {
+			return errs.Newf(errs.Failure, "editing '%s' results in an invalid file: %w", i.File, err)
+		}
+	}
+
+	if !inPlace {
+		_, err = cli.IO.Out.Write(b)
+		return err
+	}
+	fi, err := os.Stat(i.File)
+	if err != nil {
+		return errs.New(errs.Input, err)
+	}
+	return errs.New(errs.Input, os.WriteFile(i.File, b, fi.Mode().Perm()))
+}
+
+// cutPathValue splits an assignment at the first "=", which is not quoted.
+func cutPathValue(s string) (path, value string, ok bool) {
+	quoted := false
+	for i, c := range s {
+		if c == '"' {
+			quoted = !quoted
+		} else if c == '=' && !quoted {
+			return s[:i], s[i+1:], true
+		}
+	}
+	return "", "", false
+}
+
+// ParsePath splits a path like 'a."b.c"[0].d' into its keys and indices.
+func ParsePath(p string) ([]PathElem, error) {
+	var ps []PathElem
+	var cur strings.Builder
+	key := false // whether cur holds a key, which is empty, if it is quoted
+	flush := func() {
+		if key {
+			ps = append(ps, Key(cur.String()))
+			cur.Reset()
+			key = false
+		}
+	}
+
+	for i := 0; i < len(p); i++ {
+		switch c := p[i]; c {
+		case '"':
+			j := strings.IndexByte(p[i+1:], '"')
+			if j < 0 {
+				return nil, errs.Newf(errs.Usage, "invalid path '%s': unterminated quote", p)
+			}
+			cur.WriteString(p[i+1 : i+1+j])
+			key = true
+			i += j + 1
+		case '.':
+			if !key && (i == 0 || p[i-1] != ']') {
+				return nil, errs.Newf(errs.Usage, "invalid path '%s': empty key", p)
+			}
+			flush()
+		case '[':
+			flush()
+			j := strings.IndexByte(p[i:], ']')
+			if j < 0 {
+				return nil, errs.Newf(errs.Usage, "invalid path '%s': unterminated index", p)
+			}
+			n, err := strconv.Atoi(p[i+1 : i+j])
+			if err != nil || n < 0 {
+				return nil, errs.Newf(errs.Usage, "invalid path '%s': invalid index '%s'", p, p[i+1:i+j])
+			}
+			ps = append(ps, Index(n))
+			i += j
+		default:
+			cur.WriteByte(c)
+			key = true
+		}
+	}
+	if !key && (p == "" || p[len(p)-1] != ']') {
+		return nil, errs.Newf(errs.Usage, "invalid path '%s': empty key", p)
+	}
+	flush()
+	return ps, nil
+}
+
+// FormatPath is the inverse of ParsePath.
+func FormatPath(ps []PathElem) string {
+	var b strings.Builder
+	for i, p := range ps {
+		if p.IsIndex {
+			b.WriteString("[" + strconv.Itoa(p.Index) + "]")
+			continue
+		}
+		if i > 0 {
+			b.WriteByte('.')
+		}
+		if p.Key == "" || strings.ContainsAny(p.Key, `.[]"`) {
+			b.WriteString(`"` + p.Key + `"`)
+		} else {
+			b.WriteString(p.Key)
+		}
+	}
+	return b.String()
+}
+
+// nest returns the value to create for the missing path elements, e.g.,
+// {"b": [value]} for 'b[0]'. Arrays are created for indices, which must be 0.
+func nest(ps []PathElem, v any) (any, error) {
+	for i := len(ps) - 1; i >= 0; i-- {
+		if !ps[i].IsIndex {
+			v = map[string]any{ps[i].Key: v}
+		} else if ps[i].Index != 0 {
+			return nil, errs.Newf(errs.Usage, "index %d out of range (length 0)", ps[i].Index)
+		} else {
+			v = []any{v}
+		}
+	}
+	return v, nil
+}
+
+// ParseValue parses a JSON value, or returns the string as it is, if it is not
+// valid JSON. Numbers are returned as json.Number.
+func ParseValue(s string) any {
+	d := json.NewDecoder(strings.NewReader(s))
+	d.UseNumber()
+	var v any
+	if err := d.Decode(&v); err != nil || d.More() {
+		return s
+	}
+	return v
+}
+
+// index returns the array index denoted by a path element.
+func index(p PathElem, n int) (int, error) {
+	if !p.IsIndex {
+		return 0, errs.Newf(errs.Usage, "'%s' is not an array index", p.Key)
+	} else if p.Index < 0 || p.Index >= n {
+		return 0, errs.Newf(errs.Usage, "index %d out of range (length %d)", p.Index, n)
+	}
+	return p.Index, nil
+}
+
+// splice replaces the bytes from start to end with r.
+func splice(b []byte, start, end int, r []byte) []byte {
+	return slices.Concat(b[:start], r, b[end:])
+}
+
+// lineStart returns the offset of the first byte of the line containing the
+// given offset.
+func lineStart(b []byte, off int) int {
+	return bytes.LastIndexByte(b[:off], '\n') + 1
+}
+
+// lineEnd returns the offset after the newline ending the line containing the
+// given offset.
+func lineEnd(b []byte, off int) int {
+	if i := bytes.IndexByte(b[off:], '\n'); i >= 0 {
+		return off + i + 1
+	}
+	return len(b)
+}
+
+// indentOf returns the leading whitespace of the line containing the offset.
+func indentOf(b []byte, off int) string {
+	s := lineStart(b, off)
+	e := s
+	for e < len(b) && (b[e] == ' ' || b[e] == '\t') {
+		e++
+	}
+	return string(b[s:e])
+}
+
+func notFound(ps []PathElem) error {
+	return errs.Newf(errs.Usage, "'%s' does not exist", FormatPath(ps))
+}
+
+func valueString(v any) string {
+	if s, ok := v.(string); ok {
+		return s
+	}
+	return fmt.Sprint(v)
+}
@@ -0,0 +1,260 @@
+// Copyright 2025 The Heimdall authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !no_parse
+
+package parse_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/MakeNowJust/heredoc/v2"
+	"github.com/abc-inc/heimdall/errs"
+	"github.com/abc-inc/heimdall/internal"
+	"github.com/abc-inc/heimdall/plugin/parse"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePath(t *testing.T) {
+	ps, err := parse.ParsePath(`jobs.build.steps[0].uses`)
+	require.NoError(t, err)
+	require.Equal(t, []parse.PathElem{parse.Key("jobs"), parse.Key("build"), parse.Key("steps"), parse.Index(0), parse.Key("uses")}, ps)
+
+	ps, err = parse.ParsePath(`plugins."com.example".version`)
+	require.NoError(t, err)
+	require.Equal(t, parse.Keys("plugins", "com.example", "version"), ps)
+	require.Equal(t, `plugins."com.example".version`, parse.FormatPath(ps))
+
+	for _, p := range []string{`a.0`, `a."0"`} {
+		ps, err = parse.ParsePath(p)
+		require.NoError(t, err)
+		require.Equal(t, parse.Keys("a", "0"), ps)
+	}
+	require.NotEqual(t, ps, path(`a[0]`))
+
+	for _, p := range []string{"", "a..b", `a."b`, "a[x]", "a[0", "a[-1]"} {
+		_, err = parse.ParsePath(p)
+		require.Equal(t, errs.Usage, errs.KindOf(err), p)
+	}
+}
+
+func TestEditors(t *testing.T) {
+	tests := []struct {
+		typ, in, want string
+		edits         []parse.Edit
+	}{
+		{"json", heredoc.Doc(`
+			{
+			  "name": "a",
+			  "tags": ["x"],
+			  "old": null
+			}
+		`), heredoc.Doc(`
+			{
+			  "name": "b",
+			  "tags": ["x", "y"],
+			  "new": {"n":1}
+			}
+		`), []parse.Edit{
+			{Path: path(`old`), Delete: true},
+			{Path: path(`name`), Value: "b"},
+			{Path: path(`tags[1]`), Value: "y"},
+			{Path: path(`new.n`), Value: json1()},
+		}},
+		{"yaml", heredoc.Doc(`
+			# config
+			name: a # the name
+			old: true
+			list:
+			  - x
+		`), heredoc.Doc(`
+			# config
+			name: b # the name
+			list:
+			  - x
+			  - 1
+		`), []parse.Edit{
+			{Path: path(`old`), Delete: true},
+			{Path: path(`name`), Value: "b"},
+			{Path: path(`list[1]`), Value: json1()},
+		}},
+		{"toml", heredoc.Doc(`
+			# versions
+			[versions]
+			junit = "5.10.0" # test
+			old = "1"
+
+			[[libs]]
+			name = "a"
+		`), heredoc.Doc(`
+			# versions
+			[versions]
+			junit = "5.11.0" # test
+			kotlin = "2.0"
+
+			[[libs]]
+			name = "b"
+		`), []parse.Edit{
+			{Path: path(`versions.old`), Delete: true},
+			{Path: path(`versions.junit`), Value: "5.11.0"},
+			{Path: path(`versions.kotlin`), Value: "2.0"},
+			{Path: path(`libs[0].name`), Value: "b"},
+		}},
+		{"xml", heredoc.Doc(`
+			<!-- app -->
+			<app version="1">
+			  <name>a</name>
+			  <old/>
+			</app>
+		`), heredoc.Doc(`
+			<!-- app -->
+			<app version="2">
+			  <name>b</name>
+			  <new id="x"/>
+			</app>
+		`), []parse.Edit{
+			{Path: path(`app.old`), Delete: true},
+			{Path: path(`app.-version`), Value: json1()},
+			{Path: path(`app.-version`), Value: "2"},
+			{Path: path(`app.name`), Value: "b"},
+			{Path: path(`app.new.-id`), Value: "x"},
+		}},
+		{"properties", heredoc.Doc(`
+			# server
+			server.port = 8080
+			old = \
+			  1
+		`), heredoc.Doc(`
+			# server
+			server.port = 9090
+			server.host = localhost
+		`), []parse.Edit{
+			{Path: path(`old`), Delete: true},
+			{Path: path(`server.port`), Value: json1()},
+			{Path: path(`"server.port"`), Value: "9090"},
+			{Path: path(`server.host`), Value: "localhost"},
+		}},
+		{"env", heredoc.Doc(`
+			export A=1 # a
+			B=2
+		`), heredoc.Doc(`
+			export A='x y' # a
+			C=3
+		`), []parse.Edit{
+			{Path: path(`B`), Delete: true},
+			{Path: path(`A`), Value: "x y"},
+			{Path: path(`C`), Value: "3"},
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.typ, func(t *testing.T) {
+			b := []byte(tt.in)
+			for _, e := range tt.edits {
+				var err error
+				b, err = parse.Editors[tt.typ](b, e)
+				require.NoError(t, err)
+			}
+			require.Equal(t, tt.want, string(b))
+
+			_, err := parse.Editors[tt.typ]([]byte(tt.want), parse.Edit{Path: path(`missing`), Delete: true})
+			require.Equal(t, errs.Usage, errs.KindOf(err))
+		})
+	}
+}
+
+func TestEditors_Index(t *testing.T) {
+	tests := []struct {
+		typ, in, want, wantKey string
+	}{
+		{"json", `{}`, `{"x": [{"m":1}]}`, `{"x": {"0":1}}`},
+		{"yaml", "", "x:\n  - m: 1\n", "x:\n  \"0\": 1\n"},
+		{"toml", "", "x = [{ m = 1 }]\n", "x.0 = 1\n"},
+		{"properties", "", "x[0].m=1\n", "x.0=1\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.typ, func(t *testing.T) {
+			b, err := parse.Editors[tt.typ]([]byte(tt.in), parse.Edit{Path: path(`x[0].m`), Value: json1()})
+			require.NoError(t, err)
+			require.Equal(t, tt.want, string(b))
+
+			b, err = parse.Editors[tt.typ]([]byte(tt.in), parse.Edit{Path: path(`x."0"`), Value: json1()})
+			require.NoError(t, err)
+			require.Equal(t, tt.wantKey, string(b))
+
+			_, err = parse.Editors[tt.typ]([]byte(tt.in), parse.Edit{Path: path(`x[1]`), Value: json1()})
+			if tt.typ != "properties" {
+				require.ErrorContains(t, err, "index 1 out of range (length 0)")
+			}
+		})
+	}
+}
+
+func TestEditYAML_Documents(t *testing.T) {
+	set := parse.Edit{Path: path(`a.b`), Value: "x"}
+	tests := map[string]string{
+		"":                     "a:\n  b: x\n",
+		"---\n":                "a:\n  b: x\n",
+		"# config\n":           "# config\n\na:\n  b: x\n",
+		"# config\n---\n# a\n": "# config\n\n# a\n\na:\n  b: x\n",
+	}
+	for in, want := range tests {
+		b, err := parse.Editors["yaml"]([]byte(in), set)
+		require.NoError(t, err, in)
+		require.Equal(t, want, string(b), in)
+	}
+
+	_, err := parse.Editors["yaml"]([]byte("a: 1\n---\na: 2\n"), set)
+	require.ErrorContains(t, err, "2 documents")
+	require.Equal(t, errs.Usage, errs.KindOf(err))
+}
+
+func TestEditTOML_OutOfRange(t *testing.T) {
+	in := []byte("[[x]]\nm = 1\n")
+	_, err := parse.Editors["toml"](in, parse.Edit{Path: path(`x[1].m`), Value: json1()})
+	require.ErrorContains(t, err, "index 1 out of range (length 1)")
+	require.Equal(t, errs.Usage, errs.KindOf(err))
+
+	b, err := parse.Editors["toml"](in, parse.Edit{Path: path(`x[0].n`), Value: json1()})
+	require.NoError(t, err)
+	require.Equal(t, "[[x]]\nm = 1\nn = 1\n", string(b))
+}
+
+func TestNewEditCmd_Invalid(t *testing.T) {
+	f := filepath.Join(t.TempDir(), "a.json")
+	require.NoError(t, os.WriteFile(f, []byte(`{"a": 1}`), 0o600))
+	defer func(ed parse.Editor) { parse.Editors["json"] = ed }(parse.Editors["json"])
+	parse.Editors["json"] = func(b []byte, e parse.Edit) ([]byte, error) { return []byte("{"), nil }
+
+	cmd := parse.NewEditCmd()
+	require.NoError(t, cmd.Flags().Set("set", "a=2"))
+	require.NoError(t, cmd.Flags().Set("in-place", "true"))
+	err := func() (err error) {
+		defer errs.Recover(&err)
+		cmd.Run(cmd, []string{f})
+		return nil
+	}()
+	require.Equal(t, errs.Failure, errs.KindOf(err))
+	require.Equal(t, `{"a": 1}`, string(internal.Must(os.ReadFile(f))))
+}
+
+func path(p string) []parse.PathElem {
+	return internal.Must(parse.ParsePath(p))
+}
+
+func json1() any {
+	return parse.ParseValue("1")
+}
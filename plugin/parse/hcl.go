@@ -0,0 +1,137 @@
+// Copyright 2025 The Heimdall authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !no_parse && !no_hcl
+
+package parse
+
+import (
+	"encoding/json"
+	"io"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+	ctyjson "github.com/zclconf/go-cty/cty/json"
+)
+
+// DecodeHCL decodes HCL files, e.g., Terraform configurations, into maps.
+//
+// Blocks are nested by their type and labels, e.g., 'resource "aws_s3_bucket"
+// "logs" {...}' becomes resource.aws_s3_bucket.logs. If a block occurs
+// multiple times with the same type and labels, e.g., nested "ingress" blocks,
+// the bodies are collected in an array.
+//
+// Expressions, which can be evaluated without context, are converted to
+// values. Other expressions, e.g., references to variables, are kept as
+// strings in interpolation syntax, e.g., "${var.region}".
+func DecodeHCL(r io.Reader) (any, error) {
+	src, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	f, diags := hclsyntax.ParseConfig(src, "", hcl.InitialPos)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+	return hclBody(f.Body.(*hclsyntax.Body), src), nil
+}
+
+func hclBody(b *hclsyntax.Body, src []byte) map[string]any {
+	m := make(map[string]any, len(b.Attributes)+len(b.Blocks))
+	for n, a := range b.Attributes {
+		m[n] = hclExpr(a.Expr, src)
+	}
+
+	for _, blk := range b.Blocks {
+		parent, key := m, blk.Type
+		for _, l := range blk.Labels {
+			c, ok := parent[key].(map[string]any)
+			if !ok {
+				c = make(map[string]any)
+				parent[key] = c
+			}
+			parent, key = c, l
+		}
+
+		v := hclBody(blk.Body, src)
+		switch e := parent[key].(type) {
+		case nil:
+			parent[key] = v
+		case []any:
+			parent[key] = append(e, v)
+		default:
+			parent[key] = []any{e, v}
+		}
+	}
+	return m
+}
+
+func hclExpr(e hclsyntax.Expression, src []byte) any {
+	switch e := e.(type) {
+	case *hclsyntax.ObjectConsExpr:
+		m := make(map[string]any, len(e.Items))
+		for _, i := range e.Items {
+			k, ok := hclExpr(i.KeyExpr, src).(string)
+			if !ok {
+				k = hclSource(i.KeyExpr, src)
+			}
+			m[k] = hclExpr(i.ValueExpr, src)
+		}
+		return m
+	case *hclsyntax.TupleConsExpr:
+		l := make([]any, len(e.Exprs))
+		for i, x := range e.Exprs {
+			l[i] = hclExpr(x, src)
+		}
+		return l
+	}
+
+	v, diags := e.Value(nil)
+	if diags.HasErrors() || !v.IsWhollyKnown() {
+		return hclSource(e, src)
+	}
+	return ctyValue(v)
+}
+
+// hclSource returns the source of an expression in interpolation syntax.
+func hclSource(e hclsyntax.Expression, src []byte) string {
+	rng := e.Range()
+	s := string(rng.SliceBytes(src))
+	if _, ok := e.(*hclsyntax.TemplateExpr); ok && strings.HasPrefix(s, `"`) {
+		return strings.TrimSuffix(strings.TrimPrefix(s, `"`), `"`)
+	}
+	return "${" + s + "}"
+}
+
+// ctyValue converts a value to the types used by encoding/json.
+func ctyValue(v cty.Value) any {
+	if v.IsNull() {
+		return nil
+	}
+	b, err := ctyjson.Marshal(v, v.Type())
+	if err != nil {
+		return v.GoString()
+	}
+	var a any
+	_ = json.Unmarshal(b, &a)
+	return a
+}
+
+func init() {
+	Decoders["hcl"] = DecodeHCL
+	Decoders["tf"] = DecodeHCL
+	Decoders["tfvars"] = DecodeHCL
+}
@@ -0,0 +1,49 @@
+// Copyright 2025 The Heimdall authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !no_parse && !no_hcl
+
+package parse_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/abc-inc/heimdall/plugin/parse"
+	"github.com/abc-inc/heimdall/test"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodeHCL(t *testing.T) {
+	f, err := os.Open(filepath.Join(test.GetRootDir(), "testdata", "terraform", "main.tf"))
+	require.NoError(t, err)
+	defer func() { _ = f.Close() }()
+
+	v, err := parse.DecodeHCL(f)
+	require.NoError(t, err)
+	m := v.(map[string]any)
+	require.Equal(t, map[string]any{"required_version": ">= 1.5"}, m["terraform"])
+	require.Equal(t, "logs-${var.env}", m["locals"].(map[string]any)["name"])
+
+	rs := m["resource"].(map[string]any)
+	require.Equal(t, map[string]any{
+		"bucket": "${local.name}",
+		"tags":   map[string]any{"Env": "${var.env}", "Team": "platform"},
+	}, rs["aws_s3_bucket"].(map[string]any)["logs"])
+	require.Equal(t, []any{
+		map[string]any{"from_port": 443.0, "cidr_blocks": []any{"0.0.0.0/0"}},
+		map[string]any{"from_port": 80.0},
+	}, rs["aws_security_group"].(map[string]any)["web"].(map[string]any)["ingress"])
+}
@@ -88,4 +88,10 @@ func toAnyMap(a any, defKey string) any {
 
 func init() {
 	Decoders["json"] = ReadJSON
+	Encoders["json"] = func(w io.Writer, v any) error {
+		enc := json.NewEncoder(w)
+		enc.SetEscapeHTML(false)
+		enc.SetIndent("", "  ")
+		return enc.Encode(v)
+	}
 }
@@ -0,0 +1,261 @@
+// Copyright 2025 The Heimdall authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !no_parse && !no_json
+
+package parse
+
+import (
+	"bytes"
+	"encoding/json"
+	"slices"
+
+	"github.com/abc-inc/heimdall/errs"
+)
+
+// jsonValue is the location of a value in a JSON document.
+type jsonValue struct {
+	start, end int
+	kind       byte // '{', '[' or 0 for scalars
+	members    []jsonMember
+	elems      []*jsonValue
+}
+
+// jsonMember is the location of a member of a JSON object. keyEnd is the offset
+// after the closing quote of the key.
+type jsonMember struct {
+	key              string
+	keyStart, keyEnd int
+	value            *jsonValue
+}
+
+func init() {
+	Editors["json"] = editJSON
+}
+
+// editJSON modifies the text of the document, so that the formatting of all
+// other values is preserved. New members are formatted like their siblings.
+func editJSON(b []byte, e Edit) ([]byte, error) {
+	if !json.Valid(b) {
+		return nil, errs.Newf(errs.Input, "invalid JSON")
+	}
+	s := &jsonScanner{b: b}
+	root := s.value()
+
+	// find the deepest existing value along the path
+	v, i := root, 0
+	for ; i < len(e.Path); i++ {
+		c := v.child(e.Path[i])
+		if c == nil {
+			break
+		}
+		v = c
+	}
+
+	if e.Delete {
+		if i < len(e.Path) {
+			return nil, notFound(e.Path)
+		}
+		parent := root.at(e.Path[:len(e.Path)-1])
+		return parent.remove(b, e.Path[len(e.Path)-1]), nil
+	}
+
+	if i == len(e.Path) {
+		val, err := marshalJSON(e.Value)
+		if err != nil {
+			return nil, err
+		}
+		return splice(b, v.start, v.end, val), nil
+	}
+
+	// create the missing values, e.g., {"b": {"c": value}} for "a.b.c"
+	nested, err := nest(e.Path[i+1:], e.Value)
+	if err != nil {
+		return nil, err
+	}
+	val, err := marshalJSON(nested)
+	if err != nil {
+		return nil, err
+	}
+	return v.add(b, e.Path[i], val)
+}
+
+func marshalJSON(v any) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	enc := json.NewEncoder(buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return nil, errs.New(errs.Usage, err)
+	}
+	return bytes.TrimRight(buf.Bytes(), "\n"), nil
+}
+
+// child returns the member or element denoted by the path element, or nil.
+func (v *jsonValue) child(p PathElem) *jsonValue {
+	switch {
+	case v.kind == '{' && !p.IsIndex:
+		for j := len(v.members) - 1; j >= 0; j-- {
+			if v.members[j].key == p.Key {
+				return v.members[j].value
+			}
+		}
+	case v.kind == '[' && p.IsIndex:
+		if p.Index < len(v.elems) {
+			return v.elems[p.Index]
+		}
+	}
+	return nil
+}
+
+func (v *jsonValue) at(ps []PathElem) *jsonValue {
+	for _, p := range ps {
+		v = v.child(p)
+	}
+	return v
+}
+
+// add inserts a member into an object, or appends an element to an array.
+func (v *jsonValue) add(b []byte, p PathElem, val []byte) ([]byte, error) {
+	var ends []int // end of the previous entry and start of the last entry
+	switch {
+	case v.kind == '{' && !p.IsIndex:
+		k, _ := marshalJSON(p.Key)
+		if len(v.members) == 0 {
+			return splice(b, v.start, v.end, slices.Concat([]byte("{"), k, []byte(": "), val, []byte("}"))), nil
+		}
+		last := v.members[len(v.members)-1]
+		sep := b[last.keyEnd:last.value.start]
+		val = slices.Concat(k, sep, val)
+		ends = []int{last.value.end, last.keyStart}
+	case v.kind == '[' && p.IsIndex:
+		if p.Index != len(v.elems) {
+			return nil, errs.Newf(errs.Usage, "index %d out of range (length %d)", p.Index, len(v.elems))
+		}
+		if len(v.elems) == 0 {
+			return splice(b, v.start, v.end, slices.Concat([]byte("["), val, []byte("]"))), nil
+		}
+		last := v.elems[len(v.elems)-1]
+		ends = []int{last.end, last.start}
+	case v.kind == '{':
+		return nil, errs.Newf(errs.Usage, "cannot add index %d to an object", p.Index)
+	case v.kind == '[':
+		return nil, errs.Newf(errs.Usage, "cannot add '%s' to an array", p.Key)
+	default:
+		return nil, errs.Newf(errs.Usage, "cannot add '%s' to a scalar value", p)
+	}
+
+	// reuse the whitespace in front of the last entry, e.g., newline and indentation
+	ws := ends[1]
+	for ws > 0 && isJSONSpace(b[ws-1]) {
+		ws--
+	}
+	sp := b[ws:ends[1]]
+	if len(sp) == 0 && b[ws-1] != ',' {
+		sp = []byte(" ") // the only entry, e.g., {"a": 1}
+	}
+	return splice(b, ends[0], ends[0], slices.Concat([]byte(","), sp, val)), nil
+}
+
+// remove deletes a member or element including the separating comma.
+func (v *jsonValue) remove(b []byte, p PathElem) []byte {
+	var starts, ends []int
+	switch v.kind {
+	case '{':
+		for _, m := range v.members {
+			starts, ends = append(starts, m.keyStart), append(ends, m.value.end)
+		}
+		for j := len(v.members) - 1; j >= 0; j-- {
+			if v.members[j].key == p.Key {
+				return removeEntry(b, v, starts, ends, j)
+			}
+		}
+	case '[':
+		for _, e := range v.elems {
+			starts, ends = append(starts, e.start), append(ends, e.end)
+		}
+		return removeEntry(b, v, starts, ends, p.Index)
+	}
+	return b
+}
+
+func removeEntry(b []byte, v *jsonValue, starts, ends []int, i int) []byte {
+	switch {
+	case len(starts) == 1:
+		return splice(b, v.start+1, v.end-1, nil)
+	case i > 0:
+		return splice(b, ends[i-1], ends[i], nil)
+	default:
+		return splice(b, starts[0], starts[1], nil)
+	}
+}
+
+// jsonScanner records the locations of all values of a valid JSON document.
+type jsonScanner struct {
+	b   []byte
+	pos int
+}
+
+func (s *jsonScanner) skip() {
+	for s.pos < len(s.b) && (isJSONSpace(s.b[s.pos]) || s.b[s.pos] == ',' || s.b[s.pos] == ':') {
+		s.pos++
+	}
+}
+
+func (s *jsonScanner) value() *jsonValue {
+	s.skip()
+	v := &jsonValue{start: s.pos}
+	switch s.b[s.pos] {
+	case '{':
+		v.kind = '{'
+		s.pos++
+		for s.skip(); s.b[s.pos] != '}'; s.skip() {
+			m := jsonMember{keyStart: s.pos}
+			s.str()
+			m.keyEnd = s.pos
+			_ = json.Unmarshal(s.b[m.keyStart:m.keyEnd], &m.key)
+			m.value = s.value()
+			v.members = append(v.members, m)
+		}
+		s.pos++
+	case '[':
+		v.kind = '['
+		s.pos++
+		for s.skip(); s.b[s.pos] != ']'; s.skip() {
+			v.elems = append(v.elems, s.value())
+		}
+		s.pos++
+	case '"':
+		s.str()
+	default:
+		for s.pos < len(s.b) && !isJSONSpace(s.b[s.pos]) && !bytes.ContainsAny(s.b[s.pos:s.pos+1], ",]}") {
+			s.pos++
+		}
+	}
+	v.end = s.pos
+	return v
+}
+
+// str advances to the end of a string.
+func (s *jsonScanner) str() {
+	for s.pos++; s.b[s.pos] != '"'; s.pos++ {
+		if s.b[s.pos] == '\\' {
+			s.pos++
+		}
+	}
+	s.pos++
+}
+
+func isJSONSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r'
+}
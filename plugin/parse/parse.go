@@ -23,10 +23,10 @@ import (
 
 	"github.com/MakeNowJust/heredoc/v2"
 	"github.com/abc-inc/heimdall/cli"
+	"github.com/abc-inc/heimdall/errs"
 	"github.com/abc-inc/heimdall/internal"
 	"github.com/abc-inc/heimdall/res"
 	"github.com/imdario/mergo"
-	"github.com/rs/zerolog/log"
 	"github.com/spf13/cobra"
 )
 
@@ -34,6 +34,11 @@ type Decoder func(io.Reader) (any, error)
 
 var Decoders = make(map[string]Decoder)
 
+// Encoder writes a value in a certain file format.
+type Encoder func(io.Writer, any) error
+
+var Encoders = make(map[string]Encoder)
+
 type parseCfg struct {
 	cli.OutCfg
 	defType string
@@ -54,25 +59,36 @@ func NewParseCmd() *cobra.Command {
 		`),
 		Args: cobra.MinimumNArgs(1),
 		Run: func(cmd *cobra.Command, args []string) {
-			m := make(map[string]any)
-			for _, f := range args {
-				v := internal.Must(processFile(f, cfg.defType))
-				i := SplitNamePrefixType(f)
-				internal.MustNoErr(mergo.Map(&m, toAnyMap(v, i.Alias), mergo.WithOverride))
-			}
-			if len(args) == 1 && len(m) == 1 && m[""] != nil {
-				cli.Fmtln(m[""])
-			} else {
-				cli.Fmtln(m)
-			}
+			cli.Fmtln(internal.Must(processFiles(args, cfg.defType)))
 		},
 	}
 
+	cmd.AddCommand(NewConvertCmd(), NewEditCmd(), NewValidateCmd())
 	cli.AddOutputFlags(cmd, &cfg.OutCfg)
 	cmd.DisableFlagsInUseLine = true
 	return cmd
 }
 
+// processFiles decodes and merges the files. If there is only one file
+// without alias, its content is returned as it is.
+func processFiles(names []string, defType string) (any, error) {
+	m := make(map[string]any)
+	for _, f := range names {
+		v, err := processFile(f, defType)
+		if err != nil {
+			return nil, err
+		}
+		i := SplitNamePrefixType(f)
+		if err = mergo.Map(&m, toAnyMap(v, i.Alias), mergo.WithOverride); err != nil {
+			return nil, errs.New(errs.Input, err)
+		}
+	}
+	if len(names) == 1 && len(m) == 1 && m[""] != nil {
+		return m[""], nil
+	}
+	return m, nil
+}
+
 func processFile(name string, defType string) (m any, err error) {
 	i := SplitNamePrefixType(name)
 	if i.Type == "" || i.Type == "auto" {
@@ -83,8 +99,7 @@ func processFile(name string, defType string) (m any, err error) {
 		defer func() { _ = r.Close() }()
 		return d(r)
 	}
-	log.Fatal().Msgf("unsupported file type: %s", i.Type)
-	return nil, err
+	return nil, errs.Newf(errs.Input, "unsupported file type: %s", i.Type)
 }
 
 type Input struct {
@@ -17,15 +17,18 @@
 package parse
 
 import (
+	"fmt"
 	"io"
+	"maps"
+	"slices"
 	"strings"
 
 	"github.com/MakeNowJust/heredoc/v2"
 	"github.com/abc-inc/heimdall/cli"
+	"github.com/abc-inc/heimdall/errs"
 	"github.com/abc-inc/heimdall/internal"
 	"github.com/abc-inc/heimdall/res"
 	"github.com/magiconair/properties"
-	"github.com/rs/zerolog/log"
 	"github.com/spf13/cobra"
 )
 
@@ -93,7 +96,7 @@ func processProps(cfg propsCfg) any {
 	for _, kv := range cfg.set {
 		k, v, ok := strings.Cut(kv, cfg.sep)
 		if !ok {
-			log.Fatal().Str("property", kv).Str("separator", cfg.sep).Msg("Invalid key-value pair")
+			errs.Abortf(errs.Usage, "invalid key-value pair '%s' (separator '%s')", kv, cfg.sep)
 		}
 		_, _ = props.MustSet(k, v)
 	}
@@ -164,4 +167,26 @@ func init() {
 		return m, nil
 	}
 	Decoders["env"] = Decoders["properties"]
+
+	Encoders["properties"] = func(w io.Writer, v any) error {
+		kv := flatten(v, ".")
+		for _, k := range slices.Sorted(maps.Keys(kv)) {
+			if _, err := fmt.Fprintf(w, "%s=%s\n", escapeProp(k, true), escapeProp(kv[k], false)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	Encoders["env"] = func(w io.Writer, v any) error {
+		kv := make(map[string]string)
+		for k, s := range flatten(v, "_") {
+			kv[envKey(k)] = s
+		}
+		for _, k := range slices.Sorted(maps.Keys(kv)) {
+			if _, err := fmt.Fprintf(w, "%s=%s\n", k, encodeProp(kv[k], true)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
 }
@@ -0,0 +1,253 @@
+// Copyright 2025 The Heimdall authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !no_parse && !no_properties
+
+package parse
+
+import (
+	"bytes"
+	"strings"
+)
+
+// propEntry is the location of a key-value pair in a properties or env file.
+type propEntry struct {
+	key      string
+	start    int // first byte of the line
+	valStart int // first byte of the value
+	valEnd   int // byte after the value, excluding the newline
+	end      int // byte after the newline
+}
+
+func init() {
+	Editors["properties"] = func(b []byte, e Edit) ([]byte, error) {
+		return editProps(b, e, false)
+	}
+	Editors["env"] = func(b []byte, e Edit) ([]byte, error) {
+		return editProps(b, e, true)
+	}
+}
+
+// editProps modifies the lines of the given key, and keeps everything else.
+// Since keys are flat, the path is joined with dots, and indices are appended
+// in brackets, e.g., "server.port" or "hosts[0]" (see 'parse convert').
+func editProps(b []byte, e Edit, env bool) ([]byte, error) {
+	key := propKey(e.Path)
+	es := scanProps(b, env)
+
+	var found []propEntry
+	for _, pe := range es {
+		if pe.key == key {
+			found = append(found, pe)
+		}
+	}
+
+	if e.Delete {
+		if len(found) == 0 {
+			return nil, notFound(e.Path)
+		}
+		for i := len(found) - 1; i >= 0; i-- {
+			b = splice(b, found[i].start, found[i].end, nil)
+		}
+		return b, nil
+	}
+
+	val := encodeProp(valueString(e.Value), env)
+	if len(found) > 0 {
+		for i := len(found) - 1; i >= 0; i-- {
+			b = splice(b, found[i].valStart, found[i].valEnd, []byte(val))
+		}
+		return b, nil
+	}
+
+	// append the pair using the separator of the last entry, e.g., " = "
+	sep := "="
+	if len(es) > 0 {
+		last := es[len(es)-1]
+		line := b[last.start:last.valStart]
+		if i := bytes.IndexAny(line, "=:"); i > 0 && !env {
+			j, k := i, i+1
+			for j > 0 && (line[j-1] == ' ' || line[j-1] == '\t') {
+				j--
+			}
+			for k < len(line) && (line[k] == ' ' || line[k] == '\t') {
+				k++
+			}
+			sep = string(line[j:k])
+		}
+	}
+	if len(b) > 0 && b[len(b)-1] != '\n' {
+		b = append(b, '\n')
+	}
+	if !env {
+		key = escapeProp(key, true)
+	}
+	return append(b, key+sep+val+"\n"...), nil
+}
+
+// scanProps returns the key-value pairs. Properties files support line
+// continuations, escapes and the separators "=", ":" and whitespace, whereas
+// env files consist of "[export] KEY=value" lines.
+func scanProps(b []byte, env bool) (es []propEntry) {
+	for off := 0; off < len(b); {
+		start := off
+		end := lineEnd(b, off)
+		if !env {
+			// a line ending with an odd number of backslashes is continued
+			for end < len(b) && continued(b[start:end]) {
+				end = lineEnd(b, end)
+			}
+		}
+		off = end
+
+		line := string(b[start:end])
+		content := strings.TrimLeft(line, " \t\f")
+		if content == "" || content[0] == '\n' || content[0] == '\r' || content[0] == '#' || (!env && content[0] == '!') {
+			continue
+		}
+		ks := start + len(line) - len(content)
+
+		valEnd := end
+		for valEnd > start && (b[valEnd-1] == '\n' || b[valEnd-1] == '\r') {
+			valEnd--
+		}
+
+		var key string
+		var valStart int
+		if env {
+			content = strings.TrimPrefix(content, "export ")
+			k, _, ok := strings.Cut(content, "=")
+			if !ok {
+				continue
+			}
+			key = strings.TrimSpace(k)
+			valStart = ks + bytes.IndexByte(b[ks:end], '=') + 1
+			for valStart < valEnd && (b[valStart] == ' ' || b[valStart] == '\t') {
+				valStart++
+			}
+			// keep a trailing comment after an unquoted value
+			if valStart < valEnd && b[valStart] != '"' && b[valStart] != '\'' {
+				if i := bytes.Index(b[valStart:valEnd], []byte(" #")); i >= 0 {
+					valEnd = valStart + i
+				}
+			}
+		} else {
+			var sb strings.Builder
+			i := ks
+			for ; i < valEnd; i++ {
+				c := b[i]
+				if c == '\\' && i+1 < valEnd {
+					i++
+					sb.WriteByte(unescapeProp(b[i]))
+					continue
+				} else if c == '=' || c == ':' || c == ' ' || c == '\t' || c == '\f' {
+					break
+				}
+				sb.WriteByte(c)
+			}
+			key = sb.String()
+			for i < valEnd && (b[i] == ' ' || b[i] == '\t' || b[i] == '\f') {
+				i++
+			}
+			if i < valEnd && (b[i] == '=' || b[i] == ':') {
+				i++
+			}
+			for i < valEnd && (b[i] == ' ' || b[i] == '\t' || b[i] == '\f') {
+				i++
+			}
+			valStart = i
+		}
+		es = append(es, propEntry{key: key, start: start, valStart: valStart, valEnd: valEnd, end: end})
+	}
+	return es
+}
+
+func continued(line []byte) bool {
+	line = bytes.TrimRight(line, "\r\n")
+	n := 0
+	for i := len(line) - 1; i >= 0 && line[i] == '\\'; i-- {
+		n++
+	}
+	return n%2 == 1
+}
+
+func unescapeProp(c byte) byte {
+	switch c {
+	case 't':
+		return '\t'
+	case 'n':
+		return '\n'
+	case 'r':
+		return '\r'
+	case 'f':
+		return '\f'
+	}
+	return c
+}
+
+// escapeProp escapes special characters in a key or value.
+func escapeProp(s string, key bool) string {
+	var sb strings.Builder
+	for i, c := range s {
+		switch c {
+		case '\\':
+			sb.WriteString(`\\`)
+		case '\n':
+			sb.WriteString(`\n`)
+		case '\r':
+			sb.WriteString(`\r`)
+		case '\t':
+			sb.WriteString(`\t`)
+		case '\f':
+			sb.WriteString(`\f`)
+		case '=', ':', ' ', '#', '!':
+			if key || (i == 0 && (c == ' ' || c == '#' || c == '!')) {
+				sb.WriteByte('\\')
+			}
+			sb.WriteRune(c)
+		default:
+			sb.WriteRune(c)
+		}
+	}
+	return sb.String()
+}
+
+// encodeProp escapes a value for a properties file, or quotes it for an env
+// file, if necessary.
+func encodeProp(s string, env bool) string {
+	if !env {
+		return escapeProp(s, false)
+	} else if !strings.ContainsAny(s, " \t\r\n#'\"$\\`") {
+		return s
+	} else if !strings.ContainsAny(s, "'\r\n") {
+		return "'" + s + "'"
+	}
+	r := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "$", `\$`, "\n", `\n`, "\r", `\r`)
+	return `"` + r.Replace(s) + `"`
+}
+
+// propKey joins the path elements to a flat key.
+func propKey(ps []PathElem) string {
+	var sb strings.Builder
+	for i, p := range ps {
+		if p.IsIndex {
+			sb.WriteString("[" + p.String() + "]")
+			continue
+		} else if i > 0 {
+			sb.WriteByte('.')
+		}
+		sb.WriteString(p.Key)
+	}
+	return sb.String()
+}
@@ -23,6 +23,7 @@ import (
 	"github.com/BurntSushi/toml"
 	"github.com/MakeNowJust/heredoc/v2"
 	"github.com/abc-inc/heimdall/cli"
+	"github.com/abc-inc/heimdall/errs"
 	"github.com/abc-inc/heimdall/internal"
 	"github.com/abc-inc/heimdall/res"
 	"github.com/spf13/cobra"
@@ -70,4 +71,12 @@ func init() {
 		_, err := toml.NewDecoder(r).Decode(&m)
 		return m, err
 	}
+	Encoders["toml"] = func(w io.Writer, v any) error {
+		if _, ok := v.(map[string]any); !ok {
+			return errs.Newf(errs.Input, "TOML documents must be tables, not %T", v)
+		}
+		enc := toml.NewEncoder(w)
+		enc.Indent = ""
+		return enc.Encode(v)
+	}
 }
@@ -0,0 +1,429 @@
+// Copyright 2025 The Heimdall authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !no_parse && !no_toml
+
+package parse
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/abc-inc/heimdall/errs"
+	"github.com/pelletier/go-toml/v2/unstable"
+)
+
+// tomlEntry is the location of a key-value pair or a table in a TOML document.
+type tomlEntry struct {
+	path     []PathElem
+	start    int // first byte of the key or the table header
+	valStart int // first byte of the value
+	valEnd   int // byte after the value, or the end of the table
+	table    bool
+	inline   *tomlEntry // the inline table containing the entry
+	last     int        // end of the last key-value pair of a table, -1 for inline tables
+}
+
+func init() {
+	Editors["toml"] = editTOML
+}
+
+// editTOML modifies the text of the document, so that comments and formatting
+// are preserved. Values can be set in (array) tables and inline tables, but not
+// in arrays.
+func editTOML(b []byte, e Edit) ([]byte, error) {
+	es, err := scanTOML(b)
+	if err != nil {
+		return nil, errs.Newf(errs.Input, "invalid TOML: %w", err)
+	}
+
+	if e.Delete {
+		var rs [][2]int
+		for _, t := range es {
+			if slices.Equal(t.path, e.Path) && !t.table {
+				rs = append(rs, removeTOMLEntry(b, t))
+			} else if t.table && len(t.path) >= len(e.Path) && slices.Equal(t.path[:len(e.Path)], e.Path) {
+				rs = append(rs, [2]int{t.start, t.valEnd})
+			}
+		}
+		if len(rs) == 0 {
+			return nil, notFound(e.Path)
+		}
+		slices.SortFunc(rs, func(a, b [2]int) int { return b[0] - a[0] })
+		for _, r := range rs {
+			b = splice(b, r[0], r[1], nil)
+		}
+		return b, nil
+	}
+
+	val, err := encodeTOML(e.Value)
+	if err != nil {
+		return nil, err
+	}
+	if t := findTOMLEntry(es, e.Path); t != nil {
+		if t.table {
+			return nil, errs.Newf(errs.Usage, "'%s' is a table", FormatPath(e.Path))
+		}
+		return splice(b, t.valStart, t.valEnd, []byte(val)), nil
+	}
+
+	// insert the key into the table or inline table with the longest matching path
+	var t *tomlEntry
+	for i := len(e.Path) - 1; i >= 0 && t == nil; i-- {
+		if t = findTOMLEntry(es, e.Path[:i]); t != nil && !t.table && t.last >= 0 {
+			return nil, errs.Newf(errs.Usage, "'%s' is neither a table nor an inline table", FormatPath(e.Path[:i]))
+		}
+	}
+	for i := len(t.path); i < len(e.Path); i++ {
+		if n, ok := tomlArrayLen(es, e.Path[:i]); ok {
+			_, err := index(e.Path[i], n)
+			return nil, err
+		}
+	}
+	// the keys up to the first index, which is a new array, e.g., 'b = [value]' for "a.b[0]"
+	ks := e.Path[len(t.path):]
+	j := slices.IndexFunc(ks, func(p PathElem) bool { return p.IsIndex })
+	if j == 0 {
+		return nil, errs.Newf(errs.Usage, "'%s' is not an array", FormatPath(t.path))
+	} else if j > 0 {
+		v, err := nest(ks[j:], e.Value)
+		if err != nil {
+			return nil, err
+		} else if val, err = encodeTOML(v); err != nil {
+			return nil, err
+		}
+		ks = ks[:j]
+	}
+	key := tomlKey(ks)
+	if !t.table {
+		// inline table, e.g., { module = "org.junit:junit", version = "5.11.0" }
+		var last *tomlEntry
+		for _, c := range es {
+			if c.inline == t {
+				last = c
+			}
+		}
+		if last == nil {
+			return splice(b, t.valStart, t.valEnd, []byte("{ "+key+" = "+val+" }")), nil
+		}
+		return splice(b, last.valEnd, last.valEnd, []byte(", "+key+" = "+val)), nil
+	}
+
+	line := key + " = " + val + "\n"
+	if t.last > 0 {
+		line = indentOf(b, t.last-1) + line
+	}
+	if t.last > 0 && b[t.last-1] != '\n' {
+		line = "\n" + line
+	}
+	return splice(b, t.last, t.last, []byte(line)), nil
+}
+
+// findTOMLEntry returns the last entry with the given path.
+func findTOMLEntry(es []*tomlEntry, path []PathElem) *tomlEntry {
+	var found *tomlEntry
+	for _, t := range es {
+		if slices.Equal(t.path, path) {
+			found = t
+		}
+	}
+	return found
+}
+
+// tomlArrayLen returns the number of tables in an array of tables.
+func tomlArrayLen(es []*tomlEntry, path []PathElem) (n int, ok bool) {
+	for _, t := range es {
+		if t.table && len(t.path) == len(path)+1 && slices.Equal(t.path[:len(path)], path) && t.path[len(path)].IsIndex {
+			n, ok = n+1, true
+		}
+	}
+	return n, ok
+}
+
+// removeTOMLEntry returns the range of a key-value pair including its line or,
+// in inline tables, the separating comma.
+func removeTOMLEntry(b []byte, t *tomlEntry) [2]int {
+	if t.inline == nil {
+		return [2]int{lineStart(b, t.start), lineEnd(b, t.valEnd)}
+	}
+	s := t.start
+	for s > 0 && (b[s-1] == ' ' || b[s-1] == '\t') {
+		s--
+	}
+	if b[s-1] == ',' {
+		return [2]int{s - 1, t.valEnd}
+	}
+	e := t.valEnd
+	for e < len(b) && (b[e] == ' ' || b[e] == '\t' || b[e] == ',') {
+		e++
+	}
+	return [2]int{t.start, e}
+}
+
+// scanTOML returns the locations of all tables and key-value pairs. The root
+// table is the first entry.
+func scanTOML(b []byte) ([]*tomlEntry, error) {
+	root := &tomlEntry{table: true, valEnd: len(b)}
+	es := []*tomlEntry{root}
+	cur := root
+	arrays := map[string]int{}
+
+	p := unstable.Parser{}
+	p.Reset(b)
+	for p.NextExpression() {
+		n := p.Expression()
+		var keys []string
+		var start, keyEnd int
+		for it := n.Key(); it.Next(); {
+			k := it.Node()
+			if len(keys) == 0 {
+				start = int(k.Raw.Offset)
+			}
+			keys = append(keys, string(k.Data))
+			keyEnd = int(k.Raw.Offset + k.Raw.Length)
+		}
+
+		switch n.Kind {
+		case unstable.Table, unstable.ArrayTable:
+			start = lineStart(b, start)
+			cur.valEnd = start
+			path := arrayTablePath(keys, arrays, n.Kind == unstable.ArrayTable)
+			cur = &tomlEntry{path: path, start: start, valEnd: len(b), table: true, last: lineEnd(b, keyEnd)}
+			es = append(es, cur)
+		case unstable.KeyValue:
+			t := &tomlEntry{path: slices.Concat(cur.path, Keys(keys...)), start: start, valStart: skipTOMLSep(b, keyEnd)}
+			t.valEnd = scanTOMLValue(b, t.valStart)
+			cur.last = lineEnd(b, t.valEnd)
+			es = append(es, t)
+			if b[t.valStart] == '{' {
+				t.last = -1
+				es = append(es, scanInlineTable(b, t)...)
+			}
+		}
+	}
+	return es, p.Error()
+}
+
+// arrayTablePath returns the path of a table, in which every element, which
+// refers to an array of tables, is followed by the current index.
+func arrayTablePath(keys []string, arrays map[string]int, isArray bool) (path []PathElem) {
+	if isArray {
+		arrays[strings.Join(keys, "\x00")]++
+	}
+	for i, k := range keys {
+		path = append(path, Key(k))
+		if n, ok := arrays[strings.Join(keys[:i+1], "\x00")]; ok {
+			path = append(path, Index(n-1))
+		}
+	}
+	return path
+}
+
+// scanInlineTable returns the locations of the key-value pairs of an inline
+// table, e.g., { a = 1, b.c = 2 }.
+func scanInlineTable(b []byte, t *tomlEntry) (es []*tomlEntry) {
+	i := t.valStart + 1
+	for {
+		for i < t.valEnd && strings.ContainsRune(" \t\r\n,", rune(b[i])) {
+			i++
+		}
+		if i >= t.valEnd || b[i] == '}' {
+			return es
+		}
+		e := &tomlEntry{path: slices.Clone(t.path), start: i, inline: t}
+		for {
+			k, end := scanTOMLKey(b, i)
+			e.path = append(e.path, Key(k))
+			for i = end; b[i] == ' ' || b[i] == '\t'; i++ {
+			}
+			if b[i] != '.' {
+				break
+			}
+			for i++; b[i] == ' ' || b[i] == '\t'; i++ {
+			}
+		}
+		e.valStart = skipTOMLSep(b, i)
+		e.valEnd = scanTOMLValue(b, e.valStart)
+		es = append(es, e)
+		if b[e.valStart] == '{' {
+			e.last = -1
+			es = append(es, scanInlineTable(b, e)...)
+		}
+		i = e.valEnd
+	}
+}
+
+// scanTOMLKey returns a simple key and the offset after it.
+func scanTOMLKey(b []byte, i int) (string, int) {
+	if b[i] == '"' || b[i] == '\'' {
+		end := scanTOMLValue(b, i)
+		var k string
+		if b[i] == '\'' {
+			k = string(b[i+1 : end-1])
+		} else {
+			_ = json.Unmarshal(b[i:end], &k)
+		}
+		return k, end
+	}
+	s := i
+	for i < len(b) && (b[i] == '_' || b[i] == '-' || b[i] >= '0' && b[i] <= '9' || b[i] >= 'A' && b[i] <= 'Z' || b[i] >= 'a' && b[i] <= 'z') {
+		i++
+	}
+	return string(b[s:i]), i
+}
+
+// skipTOMLSep returns the offset of the value after the key.
+func skipTOMLSep(b []byte, i int) int {
+	for i < len(b) && (b[i] == ' ' || b[i] == '\t' || b[i] == '=') {
+		i++
+	}
+	return i
+}
+
+// scanTOMLValue returns the offset after the value starting at i.
+func scanTOMLValue(b []byte, i int) int {
+	switch {
+	case bytes.HasPrefix(b[i:], []byte(`"""`)), bytes.HasPrefix(b[i:], []byte(`'''`)):
+		q := b[i : i+3]
+		for j := i + 3; j < len(b); j++ {
+			if b[j] == '\\' && q[0] == '"' {
+				j++
+			} else if bytes.HasPrefix(b[j:], q) {
+				// up to two quotes are allowed before the closing delimiter
+				for j+3 < len(b) && b[j+3] == q[0] {
+					j++
+				}
+				return j + 3
+			}
+		}
+		return len(b)
+	case b[i] == '"' || b[i] == '\'':
+		for j := i + 1; j < len(b); j++ {
+			if b[j] == '\\' && b[i] == '"' {
+				j++
+			} else if b[j] == b[i] {
+				return j + 1
+			}
+		}
+		return len(b)
+	case b[i] == '[' || b[i] == '{':
+		depth := 0
+		for j := i; j < len(b); j++ {
+			switch b[j] {
+			case '[', '{':
+				depth++
+			case ']', '}':
+				if depth--; depth == 0 {
+					return j + 1
+				}
+			case '"', '\'':
+				j = scanTOMLValue(b, j) - 1
+			case '#':
+				j = lineEnd(b, j) - 1
+			}
+		}
+		return len(b)
+	default:
+		j := i
+		for j < len(b) && !strings.ContainsRune("\r\n#,]}", rune(b[j])) {
+			j++
+		}
+		for j > i && (b[j-1] == ' ' || b[j-1] == '\t') {
+			j--
+		}
+		return j
+	}
+}
+
+// tomlKey formats a dotted key, quoting keys, which are not bare keys.
+func tomlKey(ps []PathElem) string {
+	ks := make([]string, len(ps))
+	for i, p := range ps {
+		if k, end := scanTOMLKey([]byte(p.Key+" "), 0); k == p.Key && end == len(p.Key) && p.Key != "" {
+			ks[i] = p.Key
+		} else {
+			ks[i] = tomlString(p.Key)
+		}
+	}
+	return strings.Join(ks, ".")
+}
+
+// encodeTOML formats a value as TOML, using inline tables for maps.
+func encodeTOML(v any) (string, error) {
+	switch v := v.(type) {
+	case nil:
+		return "", errs.Newf(errs.Usage, "TOML does not support null values")
+	case string:
+		return tomlString(v), nil
+	case json.Number, bool:
+		return fmt.Sprint(v), nil
+	case []any:
+		ss := make([]string, len(v))
+		for i, e := range v {
+			s, err := encodeTOML(e)
+			if err != nil {
+				return "", err
+			}
+			ss[i] = s
+		}
+		return "[" + strings.Join(ss, ", ") + "]", nil
+	case map[string]any:
+		var ss []string
+		for _, k := range slices.Sorted(maps.Keys(v)) {
+			s, err := encodeTOML(v[k])
+			if err != nil {
+				return "", err
+			}
+			ss = append(ss, tomlKey(Keys(k))+" = "+s)
+		}
+		return "{ " + strings.Join(ss, ", ") + " }", nil
+	default:
+		return "", errs.Newf(errs.Usage, "unsupported value %v", v)
+	}
+}
+
+// tomlString returns a basic string with the escape sequences of TOML.
+func tomlString(s string) string {
+	var sb strings.Builder
+	sb.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '"', '\\':
+			sb.WriteString(`\` + string(r))
+		case '\b':
+			sb.WriteString(`\b`)
+		case '\t':
+			sb.WriteString(`\t`)
+		case '\n':
+			sb.WriteString(`\n`)
+		case '\f':
+			sb.WriteString(`\f`)
+		case '\r':
+			sb.WriteString(`\r`)
+		default:
+			if r < 0x20 || r == 0x7f || r == utf8.RuneError {
+				_, _ = fmt.Fprintf(&sb, `\u%04X`, r)
+			} else {
+				sb.WriteRune(r)
+			}
+		}
+	}
+	sb.WriteByte('"')
+	return sb.String()
+}
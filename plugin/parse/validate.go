@@ -0,0 +1,243 @@
+// Copyright 2025 The Heimdall authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !no_parse
+
+package parse
+
+import (
+	"bytes"
+	"cmp"
+	"encoding/json"
+	"errors"
+	"io"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/MakeNowJust/heredoc/v2"
+	"github.com/abc-inc/heimdall/cli"
+	"github.com/abc-inc/heimdall/errs"
+	"github.com/abc-inc/heimdall/internal"
+	"github.com/abc-inc/heimdall/res"
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"github.com/spf13/cobra"
+)
+
+// docDecoders holds the decoders of formats, which support multiple documents
+// per file. Each document is validated on its own.
+var docDecoders = make(map[string]func(io.Reader) ([]any, error))
+
+// SchemaViolation is a value, which does not conform to the schema.
+type SchemaViolation struct {
+	RuleID   string `json:"rule_id" yaml:"rule_id"`
+	Level    string `json:"level" yaml:"level"`
+	File     string `json:"file" yaml:"file"`
+	Document int    `json:"document,omitempty" yaml:"document,omitempty"`
+	Pointer  string `json:"pointer" yaml:"pointer"`
+	Message  string `json:"message" yaml:"message"`
+	Schema   string `json:"schema" yaml:"schema"`
+}
+
+type validateCfg struct {
+	cli.OutCfg
+	schema string
+	from   string
+}
+
+func NewValidateCmd() *cobra.Command {
+	cfg := validateCfg{}
+
+	cmd := &cobra.Command{
+		Use:   "validate [flags] --schema <schema> <file>...",
+		Short: "Validate files against a JSON Schema",
+		Long: heredoc.Doc(`
+			Validate files of any supported format against a JSON Schema.
+			The schema itself can be written in any supported format, e.g., JSON or YAML.
+			Unless the schema declares another version ("$schema"), draft 2020-12 is used.
+
+			Every document of a file is validated, e.g., each document of a multi-document YAML file.
+			Every violation refers to the value by a JSON pointer, e.g., "/spec/replicas", and to the violated keyword of the schema.
+
+			The command exits with status 1, if any file does not conform to the schema.
+		`),
+		Example: heredoc.Doc(`
+			heimdall parse validate --schema config.schema.json config.yaml
+			heimdall parse validate --schema https://json.schemastore.org/github-workflow.json --output sarif .github/workflows/*.yml
+		`),
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			sch, err := compileSchema(cfg.schema)
+			if err != nil {
+				return err
+			}
+
+			vs := make([]SchemaViolation, 0)
+			invalid := 0
+			for _, f := range args {
+				fvs, err := validateFile(sch, f, cfg.from)
+				if err != nil {
+					return err
+				} else if len(fvs) > 0 {
+					invalid++
+				}
+				vs = append(vs, fvs...)
+			}
+
+			cli.Fmtln(vs)
+			if invalid > 0 {
+				return errs.Newf(errs.Violation, "%d violations in %d of %d files", len(vs), invalid, len(args))
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&cfg.schema, "schema", "s", "", "File or URL of the JSON Schema (required)")
+	cmd.Flags().StringVarP(&cfg.from, "from", "f", "", "Type of files without (known) extension")
+	internal.MustNoErr(cmd.MarkFlagRequired("schema"))
+	cli.AddOutputFlags(cmd, &cfg.OutCfg)
+	cmd.DisableFlagsInUseLine = true
+	return cmd
+}
+
+// compileSchema loads a schema. References to other files are resolved
+// relative to it.
+func compileSchema(name string) (*jsonschema.Schema, error) {
+	docs, err := decodeDocuments(name, "json")
+	if err != nil {
+		return nil, err
+	} else if len(docs) != 1 {
+		return nil, errs.Newf(errs.Input, "schema '%s' must contain exactly one document", name)
+	}
+
+	url := name
+	if !res.IsURL(name) {
+		if url, err = filepath.Abs(name); err != nil {
+			return nil, errs.New(errs.Input, err)
+		}
+	}
+
+	c := jsonschema.NewCompiler()
+	c.DefaultDraft(jsonschema.Draft2020)
+	if err = c.AddResource(url, docs[0]); err != nil {
+		return nil, errs.Newf(errs.Input, "invalid schema '%s': %w", name, err)
+	}
+	sch, err := c.Compile(url)
+	if err != nil {
+		return nil, errs.Newf(errs.Input, "invalid schema '%s': %w", name, err)
+	}
+	return sch, nil
+}
+
+func validateFile(sch *jsonschema.Schema, name, defType string) (vs []SchemaViolation, err error) {
+	docs, err := decodeDocuments(name, defType)
+	if err != nil {
+		return nil, err
+	}
+
+	i := SplitNamePrefixType(name)
+	for d, doc := range docs {
+		var ve *jsonschema.ValidationError
+		if err = sch.Validate(doc); errors.As(err, &ve) {
+			for _, v := range violations(ve) {
+				v.File = i.File
+				if len(docs) > 1 {
+					v.Document = d + 1
+				}
+				vs = append(vs, v)
+			}
+		} else if err != nil {
+			return nil, errs.Newf(errs.Input, "cannot validate '%s': %w", i.File, err)
+		}
+	}
+	return vs, nil
+}
+
+// decodeDocuments decodes all documents of a file, and converts them to the
+// types of a JSON document, e.g., timestamps become strings.
+func decodeDocuments(name, defType string) ([]any, error) {
+	i := SplitNamePrefixType(name)
+	if i.Type == "" || i.Type == "auto" {
+		i.Type = defType
+	}
+	d, ok := Decoders[i.Type]
+	if !ok {
+		return nil, errs.Newf(errs.Input, "unsupported file type: %s", i.Type)
+	}
+
+	r, err := res.Open(i.File)
+	if err != nil {
+		return nil, errs.New(errs.Input, err)
+	}
+	defer func() { _ = r.Close() }()
+
+	var docs []any
+	if dd, ok := docDecoders[i.Type]; ok {
+		docs, err = dd(r)
+	} else {
+		var v any
+		v, err = d(r)
+		docs = []any{v}
+	}
+	if err != nil {
+		return nil, errs.Newf(errs.Input, "cannot decode '%s': %w", i.File, err)
+	}
+
+	for j, doc := range docs {
+		b, err := json.Marshal(doc)
+		if err != nil {
+			return nil, errs.Newf(errs.Input, "cannot convert '%s' to JSON: %w", i.File, err)
+		}
+		if docs[j], err = jsonschema.UnmarshalJSON(bytes.NewReader(b)); err != nil {
+			return nil, errs.New(errs.Input, err)
+		}
+	}
+	return docs, nil
+}
+
+// violations returns the innermost causes of a validation error, i.e., the
+// keywords, which failed, ordered by their location in the document.
+func violations(ve *jsonschema.ValidationError) (vs []SchemaViolation) {
+	if len(ve.Causes) > 0 {
+		for _, c := range ve.Causes {
+			vs = append(vs, violations(c)...)
+		}
+		// causes of object properties are unordered
+		slices.SortStableFunc(vs, func(a, b SchemaViolation) int {
+			return cmp.Or(strings.Compare(a.Pointer, b.Pointer), strings.Compare(a.Schema, b.Schema))
+		})
+		return vs
+	}
+
+	kw := ve.ErrorKind.KeywordPath()
+	v := SchemaViolation{
+		RuleID:  "schema",
+		Level:   cli.LevelError,
+		Pointer: jsonPointer(ve.InstanceLocation),
+		Message: ve.BasicOutput().Error.String(),
+		Schema:  ve.SchemaURL + jsonPointer(kw),
+	}
+	if len(kw) > 0 {
+		v.RuleID = "schema-" + kw[0]
+	}
+	return append(vs, v)
+}
+
+func jsonPointer(ps []string) string {
+	var sb strings.Builder
+	for _, p := range ps {
+		sb.WriteString("/" + strings.NewReplacer("~", "~0", "/", "~1").Replace(p))
+	}
+	return sb.String()
+}
@@ -0,0 +1,73 @@
+// Copyright 2025 The Heimdall authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !no_parse
+
+package parse_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/MakeNowJust/heredoc/v2"
+	"github.com/abc-inc/heimdall/cli"
+	"github.com/abc-inc/heimdall/errs"
+	"github.com/abc-inc/heimdall/internal"
+	"github.com/abc-inc/heimdall/plugin/parse"
+	"github.com/abc-inc/heimdall/test"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewValidateCmd(t *testing.T) {
+	schema := filepath.Join(test.GetRootDir(), "testdata", "schema", "config.schema.yaml")
+	dir := t.TempDir()
+	valid, invalid := filepath.Join(dir, "valid.json"), filepath.Join(dir, "invalid.yaml")
+	require.NoError(t, os.WriteFile(valid, []byte(`{"name": "web", "port": 8080}`), 0o600))
+	require.NoError(t, os.WriteFile(invalid, []byte(heredoc.Doc(`
+		name: web
+		port: 8080
+		---
+		name: web
+		port: 70000
+		tags: [a, 2]
+	`)), 0o600))
+
+	cmd := parse.NewValidateCmd()
+	internal.MustNoErr(cmd.Flags().Set("schema", schema))
+	require.Equal(t, "0", test.Run("length", cmd, []string{valid}))
+	require.NoError(t, cmd.RunE(cmd, []string{valid}))
+
+	var err error
+	out, _ := cli.Capture(func() {
+		cli.SetFormat(map[string]any{"output": "json"})
+		err = cmd.RunE(cmd, []string{valid, invalid})
+	})
+	require.ErrorIs(t, err, errs.ErrViolation)
+	var vs []parse.SchemaViolation
+	require.NoError(t, json.Unmarshal(out, &vs))
+	require.Len(t, vs, 2)
+	require.Equal(t, parse.SchemaViolation{
+		RuleID: "schema-maximum", Level: "error", File: invalid, Document: 2, Pointer: "/port",
+		Message: "maximum: got 70,000, want 65,535", Schema: "file://" + filepath.ToSlash(schema) + "#/properties/port/maximum",
+	}, vs[0])
+	require.Equal(t, "/tags/1", vs[1].Pointer)
+
+	internal.MustNoErr(cmd.Flags().Set("schema", valid))
+	require.NoError(t, cmd.RunE(cmd, []string{valid}))
+
+	internal.MustNoErr(cmd.Flags().Set("schema", filepath.Join(dir, "missing.json")))
+	require.ErrorIs(t, cmd.RunE(cmd, []string{valid}), errs.ErrInput)
+}
@@ -17,6 +17,7 @@
 package parse
 
 import (
+	"encoding/xml"
 	"io"
 	"maps"
 
@@ -68,6 +69,23 @@ func ReadXML(name string) mxj.Map {
 }
 
 func init() {
+	Encoders["xml"] = func(w io.Writer, v any) error {
+		if _, err := io.WriteString(w, xml.Header); err != nil {
+			return err
+		}
+		// a document has exactly one root element
+		m, ok := v.(map[string]any)
+		if !ok {
+			m = map[string]any{"doc": map[string]any{"item": v}}
+		} else if len(m) != 1 {
+			m = map[string]any{"doc": m}
+		}
+		if err := mxj.Map(m).XmlIndentWriter(w, "", "  "); err != nil {
+			return err
+		}
+		_, err := io.WriteString(w, "\n")
+		return err
+	}
 	Decoders["xml"] = func(r io.Reader) (any, error) {
 		m, err := mxj.NewMapXmlReader(r)
 		if m != nil {
@@ -0,0 +1,272 @@
+// Copyright 2025 The Heimdall authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !no_parse && !no_xml
+
+package parse
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/abc-inc/heimdall/errs"
+)
+
+// xmlElem is the location of an element in an XML document.
+type xmlElem struct {
+	name        string
+	start       int // first byte of the start tag
+	startEnd    int // byte after the start tag
+	endStart    int // first byte of the end tag
+	end         int // byte after the end tag
+	selfClosing bool
+	text        bool // whether the element contains non-whitespace text
+	children    []*xmlElem
+}
+
+func init() {
+	Editors["xml"] = editXML
+}
+
+// editXML modifies the text of the document, so that comments and formatting
+// are preserved. Like in the output of 'heimdall parse', attributes are
+// prefixed with "-", and "#text" denotes the text of an element.
+func editXML(b []byte, e Edit) ([]byte, error) {
+	root, err := scanXML(b)
+	if err != nil {
+		return nil, errs.Newf(errs.Input, "invalid XML: %w", err)
+	} else if e.Path[0] != Key(root.name) {
+		return nil, errs.Newf(errs.Usage, "the root element is '%s', not '%s'", root.name, e.Path[0])
+	}
+
+	ps := e.Path[1:]
+	if len(ps) > 0 && ps[len(ps)-1] == Key("#text") {
+		ps = ps[:len(ps)-1]
+	}
+	var attr string
+	if len(ps) > 0 && !ps[len(ps)-1].IsIndex && strings.HasPrefix(ps[len(ps)-1].Key, "-") {
+		attr, ps = ps[len(ps)-1].Key[1:], ps[:len(ps)-1]
+	}
+
+	// find the deepest existing element along the path
+	el, i := root, 0
+	for i < len(ps) {
+		c, n, err := el.child(ps[i:])
+		if err != nil {
+			return nil, err
+		} else if c == nil {
+			break
+		}
+		el, i = c, i+n
+	}
+
+	if e.Delete {
+		if i < len(ps) {
+			return nil, notFound(e.Path)
+		} else if attr != "" {
+			return el.removeAttr(b, attr)
+		} else if el == root {
+			return nil, errs.Newf(errs.Usage, "the root element cannot be deleted")
+		}
+		s, end := el.start, el.end
+		if ls := lineStart(b, s); len(bytes.TrimSpace(b[ls:s])) == 0 {
+			if le := lineEnd(b, end); len(bytes.TrimSpace(b[end:le])) == 0 {
+				s, end = ls, le
+			}
+		}
+		return splice(b, s, end, nil), nil
+	}
+
+	switch e.Value.(type) {
+	case map[string]any, []any:
+		return nil, errs.Newf(errs.Usage, "only scalar values can be set in XML")
+	}
+	val := valueString(e.Value)
+	if i == len(ps) {
+		if attr != "" {
+			return el.setAttr(b, attr, val), nil
+		}
+		return el.setText(b, val)
+	}
+
+	// create the missing elements, e.g., <b><c>value</c></b> for "a.b.c"
+	var sb strings.Builder
+	for _, p := range ps[i:] {
+		if p.IsIndex {
+			return nil, errs.Newf(errs.Usage, "'%s' does not exist", FormatPath(e.Path))
+		}
+		sb.WriteString("<" + p.Key + ">")
+	}
+	if attr != "" {
+		sb.Reset()
+		for j, p := range ps[i:] {
+			if sb.WriteString("<" + p.Key); j < len(ps[i:])-1 {
+				sb.WriteString(">")
+			}
+		}
+		sb.WriteString(" " + attr + `="` + escapeXML(val) + `"/>`)
+	} else {
+		sb.WriteString(escapeXML(val))
+		sb.WriteString("</" + ps[len(ps)-1].Key + ">")
+	}
+	for j := len(ps) - 2; j >= i; j-- {
+		sb.WriteString("</" + ps[j].Key + ">")
+	}
+	return el.add(b, sb.String())
+}
+
+// child returns the child element denoted by the path, and the number of
+// consumed path elements, i.e., 2 if the name is followed by an index.
+func (el *xmlElem) child(ps []PathElem) (*xmlElem, int, error) {
+	if ps[0].IsIndex {
+		return nil, 0, errs.Newf(errs.Usage, "index %d must follow the name of an element", ps[0].Index)
+	}
+	var cs []*xmlElem
+	for _, c := range el.children {
+		if c.name == ps[0].Key {
+			cs = append(cs, c)
+		}
+	}
+	if len(ps) > 1 && ps[1].IsIndex {
+		if i := ps[1].Index; i >= len(cs) {
+			return nil, 0, errs.Newf(errs.Usage, "index %d of element '%s' out of range (length %d)", i, ps[0].Key, len(cs))
+		}
+		return cs[ps[1].Index], 2, nil
+	}
+	if len(cs) == 0 {
+		return nil, 0, nil
+	}
+	return cs[0], 1, nil
+}
+
+func (el *xmlElem) setText(b []byte, val string) ([]byte, error) {
+	if len(el.children) > 0 {
+		return nil, errs.Newf(errs.Usage, "element '%s' contains other elements", el.name)
+	} else if el.selfClosing {
+		tag := bytes.TrimSpace(bytes.TrimSuffix(b[el.start:el.startEnd-1], []byte("/")))
+		return splice(b, el.start, el.end, []byte(string(tag)+">"+escapeXML(val)+"</"+el.name+">")), nil
+	}
+	return splice(b, el.startEnd, el.endStart, []byte(escapeXML(val))), nil
+}
+
+// attrRe matches an attribute in a start tag.
+func attrRe(name string) *regexp.Regexp {
+	return regexp.MustCompile(`\s+` + regexp.QuoteMeta(name) + `\s*=\s*("[^"]*"|'[^']*')`)
+}
+
+func (el *xmlElem) setAttr(b []byte, name, val string) []byte {
+	tag := b[el.start:el.startEnd]
+	if loc := attrRe(name).FindSubmatchIndex(tag); loc != nil {
+		return splice(b, el.start+loc[2], el.start+loc[3], []byte(`"`+escapeXML(val)+`"`))
+	}
+	end := el.startEnd - 1
+	if el.selfClosing {
+		for b[end-1] == '/' || b[end-1] == ' ' {
+			end--
+		}
+	}
+	return splice(b, end, end, []byte(" "+name+`="`+escapeXML(val)+`"`))
+}
+
+func (el *xmlElem) removeAttr(b []byte, name string) ([]byte, error) {
+	tag := b[el.start:el.startEnd]
+	loc := attrRe(name).FindIndex(tag)
+	if loc == nil {
+		return nil, errs.Newf(errs.Usage, "element '%s' has no attribute '%s'", el.name, name)
+	}
+	return splice(b, el.start+loc[0], el.start+loc[1], nil), nil
+}
+
+// add inserts a child element after the last child, indented like it.
+func (el *xmlElem) add(b []byte, child string) ([]byte, error) {
+	if el.text {
+		return nil, errs.Newf(errs.Usage, "element '%s' contains text", el.name)
+	} else if len(el.children) > 0 {
+		last := el.children[len(el.children)-1]
+		return splice(b, last.end, last.end, []byte("\n"+indentOf(b, last.start)+child)), nil
+	}
+
+	indent := indentOf(b, el.start)
+	unit := "  "
+	if strings.Contains(indent, "\t") {
+		unit = "\t"
+	}
+	if el.selfClosing {
+		tag := bytes.TrimSpace(bytes.TrimSuffix(b[el.start:el.startEnd-1], []byte("/")))
+		return splice(b, el.start, el.end, []byte(string(tag)+">\n"+indent+unit+child+"\n"+indent+"</"+el.name+">")), nil
+	}
+	return splice(b, el.startEnd, el.endStart, []byte("\n"+indent+unit+child+"\n"+indent)), nil
+}
+
+// scanXML returns the root element and the locations of all its descendants.
+func scanXML(b []byte) (*xmlElem, error) {
+	d := xml.NewDecoder(bytes.NewReader(b))
+	d.Strict = false
+	var stack []*xmlElem
+	var root *xmlElem
+	for {
+		start := int(d.InputOffset())
+		t, err := d.RawToken()
+		if errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+
+		switch t := t.(type) {
+		case xml.StartElement:
+			el := &xmlElem{name: xmlName(t.Name), start: start, startEnd: int(d.InputOffset())}
+			if len(stack) > 0 {
+				p := stack[len(stack)-1]
+				p.children = append(p.children, el)
+			} else if root == nil {
+				root = el
+			}
+			stack = append(stack, el)
+		case xml.EndElement:
+			if len(stack) == 0 {
+				return nil, errs.Newf(errs.Input, "unexpected end element '%s'", xmlName(t.Name))
+			}
+			el := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			el.endStart, el.end = start, int(d.InputOffset())
+			el.selfClosing = el.end == el.startEnd && el.endStart == el.startEnd
+		case xml.CharData:
+			if len(stack) > 0 && len(bytes.TrimSpace(t)) > 0 {
+				stack[len(stack)-1].text = true
+			}
+		}
+	}
+	if root == nil {
+		return nil, errs.Newf(errs.Input, "no root element")
+	}
+	return root, nil
+}
+
+func xmlName(n xml.Name) string {
+	if n.Space != "" {
+		return n.Space + ":" + n.Local
+	}
+	return n.Local
+}
+
+func escapeXML(s string) string {
+	buf := &bytes.Buffer{}
+	_ = xml.EscapeText(buf, []byte(s))
+	return buf.String()
+}
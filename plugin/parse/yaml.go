@@ -17,6 +17,7 @@
 package parse
 
 import (
+	"errors"
 	"io"
 	"maps"
 
@@ -64,10 +65,47 @@ func ProcessYAML(name string) (m map[string]any) {
 	return m
 }
 
-func init() {
-	Decoders["yaml"] = func(r io.Reader) (m any, err error) {
-		err = yaml.NewDecoder(r).Decode(&m)
-		return
+// DecodeYAML decodes all documents of a YAML stream. If the stream contains
+// multiple documents, e.g., a Kubernetes manifest, they are returned as array.
+func DecodeYAML(r io.Reader) (any, error) {
+	docs, err := DecodeYAMLDocuments(r)
+	if err != nil {
+		return nil, err
+	} else if len(docs) == 1 {
+		return docs[0], nil
+	}
+	return docs, nil
+}
+
+// DecodeYAMLDocuments decodes all documents of a YAML stream. Empty documents
+// are skipped.
+func DecodeYAMLDocuments(r io.Reader) ([]any, error) {
+	var docs []any
+	d := yaml.NewDecoder(r)
+	for {
+		var v any
+		if err := d.Decode(&v); errors.Is(err, io.EOF) && len(docs) > 0 {
+			return docs, nil
+		} else if err != nil {
+			return nil, err
+		} else if v != nil {
+			docs = append(docs, v)
+		}
 	}
+}
+
+func init() {
+	Decoders["yaml"] = DecodeYAML
 	Decoders["yml"] = Decoders["yaml"]
+	docDecoders["yaml"] = DecodeYAMLDocuments
+	docDecoders["yml"] = DecodeYAMLDocuments
+	Encoders["yaml"] = func(w io.Writer, v any) error {
+		enc := yaml.NewEncoder(w)
+		enc.SetIndent(2)
+		if err := enc.Encode(v); err != nil {
+			return err
+		}
+		return enc.Close()
+	}
+	Encoders["yml"] = Encoders["yaml"]
 }
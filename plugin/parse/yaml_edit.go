@@ -0,0 +1,207 @@
+// Copyright 2025 The Heimdall authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !no_parse && !no_yaml
+
+package parse
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/abc-inc/heimdall/errs"
+	"gopkg.in/yaml.v3"
+)
+
+func init() {
+	Editors["yaml"] = editYAML
+	Editors["yml"] = editYAML
+}
+
+// editYAML modifies the document. Comments, key order and the style of scalars
+// are kept, but the indentation is normalized. Files with multiple documents are
+// rejected, because a path cannot refer to one of them.
+func editYAML(b []byte, e Edit) ([]byte, error) {
+	var docs []*yaml.Node
+	d := yaml.NewDecoder(bytes.NewReader(b))
+	for {
+		n := &yaml.Node{}
+		if err := d.Decode(n); errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return nil, errs.Newf(errs.Input, "invalid YAML: %w", err)
+		}
+		docs = append(docs, n)
+	}
+	if len(docs) > 1 {
+		return nil, errs.Newf(errs.Usage, "cannot edit YAML with %d documents, only a single document is supported", len(docs))
+	} else if len(docs) == 0 {
+		// Files without content, e.g., only comments, do not contain a document.
+		docs = append(docs, &yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.ScalarNode, Tag: "!!null"}}})
+	}
+	if n := docs[0].Content[0]; n.Kind == yaml.ScalarNode && n.Tag == "!!null" {
+		// An empty document, e.g., "---", becomes a mapping or sequence. The
+		// decoder does not retain all comments of empty documents, hence they
+		// are collected.
+		if n.Value == "" {
+			docs[0].HeadComment, docs[0].FootComment = yamlComments(b), ""
+		}
+		nn := newYAMLNode(e.Path)
+		n.Kind, n.Tag, n.Value, n.Style = nn.Kind, nn.Tag, "", 0
+	}
+
+	if err := editYAMLNode(docs[0].Content[0], e.Path, e); err != nil {
+		return nil, err
+	}
+
+	buf := &bytes.Buffer{}
+	enc := yaml.NewEncoder(buf)
+	enc.SetIndent(yamlIndent(b))
+	for _, doc := range docs {
+		if err := enc.Encode(doc); err != nil {
+			return nil, errs.New(errs.Failure, err)
+		}
+	}
+	return buf.Bytes(), enc.Close()
+}
+
+func editYAMLNode(n *yaml.Node, ps []PathElem, e Edit) error {
+	if n.Kind == yaml.AliasNode {
+		n = n.Alias
+	}
+	p := ps[0]
+	switch {
+	case n.Kind == yaml.MappingNode && !p.IsIndex:
+		for i := len(n.Content) - 2; i >= 0; i -= 2 {
+			if n.Content[i].Value != p.Key {
+				continue
+			} else if len(ps) > 1 {
+				return editYAMLNode(n.Content[i+1], ps[1:], e)
+			} else if e.Delete {
+				n.Content = slices.Delete(n.Content, i, i+2)
+				return nil
+			}
+			return setYAMLNode(n.Content[i+1], e.Value)
+		}
+		if e.Delete {
+			return notFound(e.Path)
+		}
+		k := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: p.Key}
+		v := newYAMLNode(ps[1:])
+		n.Content = append(n.Content, k, v)
+		if len(ps) > 1 {
+			return editYAMLNode(v, ps[1:], e)
+		}
+		return setYAMLNode(v, e.Value)
+	case n.Kind == yaml.SequenceNode && p.IsIndex:
+		i := p.Index
+		if i >= len(n.Content) && e.Delete {
+			return notFound(e.Path)
+		} else if i > len(n.Content) {
+			return errs.Newf(errs.Usage, "index %d out of range (length %d)", i, len(n.Content))
+		} else if i == len(n.Content) {
+			n.Content = append(n.Content, newYAMLNode(ps[1:]))
+		}
+		if len(ps) > 1 {
+			return editYAMLNode(n.Content[i], ps[1:], e)
+		} else if e.Delete {
+			n.Content = slices.Delete(n.Content, i, i+1)
+			return nil
+		}
+		return setYAMLNode(n.Content[i], e.Value)
+	case n.Kind == yaml.MappingNode:
+		return errs.Newf(errs.Usage, "cannot edit index %d of a mapping", p.Index)
+	case n.Kind == yaml.SequenceNode:
+		return errs.Newf(errs.Usage, "cannot edit '%s' of a sequence", p.Key)
+	default:
+		return errs.Newf(errs.Usage, "cannot edit '%s' of a scalar value", p)
+	}
+}
+
+// newYAMLNode returns an empty node for the first of the remaining path
+// elements, i.e., a sequence for an index, and a mapping otherwise.
+func newYAMLNode(ps []PathElem) *yaml.Node {
+	if len(ps) > 0 && ps[0].IsIndex {
+		return &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+	}
+	return &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+}
+
+// setYAMLNode replaces the node with the value, but keeps its comments and, if
+// both are strings, its style.
+func setYAMLNode(n *yaml.Node, v any) error {
+	nv := &yaml.Node{}
+	if err := nv.Encode(fromJSONNumber(v)); err != nil {
+		return errs.New(errs.Usage, err)
+	}
+	if n.Kind == yaml.ScalarNode && nv.Kind == yaml.ScalarNode && n.Tag == nv.Tag {
+		nv.Style = n.Style
+	}
+	nv.HeadComment, nv.LineComment, nv.FootComment = n.HeadComment, n.LineComment, n.FootComment
+	*n = *nv
+	return nil
+}
+
+// yamlComments returns the comments of a file, separated by blank lines as in
+// the file.
+func yamlComments(b []byte) string {
+	var cs []string
+	for _, l := range strings.Split(string(b), "\n") {
+		if l = strings.TrimSpace(l); strings.HasPrefix(l, "#") {
+			cs = append(cs, l)
+		} else if len(cs) > 0 && cs[len(cs)-1] != "" {
+			cs = append(cs, "")
+		}
+	}
+	return strings.TrimSpace(strings.Join(cs, "\n"))
+}
+
+// yamlIndent returns the smallest indentation of a mapping key, or 2.
+func yamlIndent(b []byte) int {
+	indent := 0
+	for _, l := range bytes.Split(b, []byte("\n")) {
+		t := bytes.TrimLeft(l, " ")
+		if n := len(l) - len(t); n > 0 && len(t) > 0 && t[0] != '#' && t[0] != '-' && (indent == 0 || n < indent) {
+			indent = n
+		}
+	}
+	return max(indent, 2)
+}
+
+// fromJSONNumber converts numbers into int64 or float64, because otherwise,
+// they would be encoded as strings.
+func fromJSONNumber(v any) any {
+	switch v := v.(type) {
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i
+		} else if f, err := v.Float64(); err == nil {
+			return f
+		}
+		return v.String()
+	case []any:
+		for i, e := range v {
+			v[i] = fromJSONNumber(e)
+		}
+	case map[string]any:
+		for k, e := range v {
+			v[k] = fromJSONNumber(e)
+		}
+	}
+	return v
+}
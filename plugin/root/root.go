@@ -20,23 +20,33 @@ import (
 
 	"github.com/abc-inc/heimdall/cli"
 	"github.com/abc-inc/heimdall/docs"
+	"github.com/abc-inc/heimdall/errs"
 	"github.com/abc-inc/heimdall/internal"
 	"github.com/abc-inc/heimdall/plugin/artifactory"
+	"github.com/abc-inc/heimdall/plugin/check"
 	"github.com/abc-inc/heimdall/plugin/confluence"
 	"github.com/abc-inc/heimdall/plugin/cyclonedx"
 	"github.com/abc-inc/heimdall/plugin/docker"
 	"github.com/abc-inc/heimdall/plugin/echo"
 	"github.com/abc-inc/heimdall/plugin/eval"
 	"github.com/abc-inc/heimdall/plugin/example"
+	"github.com/abc-inc/heimdall/plugin/external"
 	"github.com/abc-inc/heimdall/plugin/git"
 	"github.com/abc-inc/heimdall/plugin/golang"
 	"github.com/abc-inc/heimdall/plugin/html"
 	"github.com/abc-inc/heimdall/plugin/java"
 	"github.com/abc-inc/heimdall/plugin/jira"
+	"github.com/abc-inc/heimdall/plugin/k8s"
 	"github.com/abc-inc/heimdall/plugin/keyring"
 	"github.com/abc-inc/heimdall/plugin/parse"
+	"github.com/abc-inc/heimdall/plugin/run"
+	"github.com/abc-inc/heimdall/plugin/serve"
 	"github.com/abc-inc/heimdall/plugin/ssh"
+	"github.com/abc-inc/heimdall/plugin/terraform"
+	"github.com/abc-inc/heimdall/res"
+	"github.com/rs/zerolog/log"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 )
 
 var rootCmd *cobra.Command
@@ -47,15 +57,43 @@ func GetRootCmd() *cobra.Command {
 	}
 
 	rootCmd = &cobra.Command{
-		Use:   "heimdall <command>",
-		Short: "Perform compliance checks and report evidences",
-		PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		Use:           "heimdall <command>",
+		Short:         "Perform compliance checks and report evidences",
+		SilenceErrors: true,
+		SilenceUsage:  true,
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) (err error) {
+			defer errs.Recover(&err)
+			cmd.SetContext(cli.InitContext(internal.Must(cmd.Flags().GetDuration("timeout"))))
 			cli.SetFormat(map[string]any{
 				"jq":     cmd.Flag("jq"),
 				"output": cmd.Flag("output"),
 				"pretty": cmd.Flag("pretty"),
 				"query":  cmd.Flag("query")},
 			)
+			if cli.Nested() {
+				// evidence and recording belong to the outermost command
+				return nil
+			}
+			if dir := internal.Must(cmd.Flags().GetString("evidence-dir")); dir != "" {
+				cli.RecordEvidence(dir, cmd, os.Args)
+			}
+			if dir := internal.Must(cmd.Flags().GetString("record")); dir != "" {
+				internal.MustNoErr(res.Record(dir))
+			} else if dir = internal.Must(cmd.Flags().GetString("replay")); dir != "" {
+				internal.MustNoErr(res.Replay(dir))
+			}
+			return nil
+		},
+		PersistentPostRunE: func(cmd *cobra.Command, args []string) error {
+			cli.CancelContext()
+			if cli.Nested() {
+				return nil
+			}
+			dir, err := cli.WriteEvidence(0)
+			if dir != "" {
+				log.Debug().Str("dir", dir).Msg("Wrote evidence")
+			}
+			return err
 		},
 	}
 
@@ -63,12 +101,14 @@ func GetRootCmd() *cobra.Command {
 		&cobra.Group{ID: cli.FileGroup, Title: cli.FileGroup + ":"},
 		&cobra.Group{ID: cli.HeimdallGroup, Title: cli.HeimdallGroup + ":"},
 		&cobra.Group{ID: cli.MiscGroup, Title: cli.MiscGroup + ":"},
+		&cobra.Group{ID: cli.PluginGroup, Title: cli.PluginGroup + ":"},
 		&cobra.Group{ID: cli.ServiceGroup, Title: cli.ServiceGroup + ":"},
 		&cobra.Group{ID: cli.SoftwareGroup, Title: cli.SoftwareGroup + ":"},
 	)
 
 	rootCmd.AddCommand(
 		artifactory.NewArtifactoryCmd(),
+		check.NewCheckCmd(),
 		confluence.NewConfluenceCmd(),
 		cyclonedx.NewCycloneDXCmd(),
 		docker.NewDockerCmd(),
@@ -80,9 +120,13 @@ func GetRootCmd() *cobra.Command {
 		html.NewHTMLCmd(),
 		java.NewJavaCmd(),
 		jira.NewJiraCmd(),
+		k8s.NewK8sCmd(),
 		keyring.NewKeyringCmd(),
 		parse.NewParseCmd(),
+		run.NewRunCmd(),
+		serve.NewServeCmd(),
 		ssh.NewSSHCmd(),
+		terraform.NewTerraformCmd(),
 	)
 
 	for _, t := range docs.Topics {
@@ -93,6 +137,43 @@ func GetRootCmd() *cobra.Command {
 
 	cfgDir := filepath.Join(internal.Must(os.UserConfigDir()), "heimdall")
 	rootCmd.PersistentFlags().String("config", cfgDir, "Location of the Heimdall config directory")
+	rootCmd.PersistentFlags().String("profile", "", "Use the service settings of this profile in the config file")
+	rootCmd.PersistentFlags().String("evidence-dir", "", "Record the command line, inputs and output of the command in a bundle in this directory")
+	rootCmd.PersistentFlags().String("record", "", "Record the HTTP requests and responses of service clients in this directory")
+	rootCmd.PersistentFlags().String("replay", "", "Replay the HTTP responses recorded in this directory instead of sending requests")
+	rootCmd.MarkFlagsMutuallyExclusive("record", "replay")
+	rootCmd.PersistentFlags().Duration("timeout", 0, "Cancel the command after this duration, e.g., 30s or 5m (0 means no timeout)")
 	rootCmd.PersistentFlags().String("log-level", "info", "Log level (debug, info, warn, error, fatal)")
 	return rootCmd
 }
+
+// ConfigDir returns the config dir, i.e., the value of --config, the environment
+// variable HEIMDALL_CONFIG or the default. Before the flags are parsed, e.g.,
+// while plugins are added, --config is looked up in os.Args.
+func ConfigDir() string {
+	f := GetRootCmd().PersistentFlags().Lookup("config")
+	if f.Changed {
+		return f.Value.String()
+	}
+
+	fs := pflag.NewFlagSet("config", pflag.ContinueOnError)
+	fs.ParseErrorsWhitelist.UnknownFlags = true
+	fs.Usage = func() {}
+	fs.BoolP("help", "h", false, "")
+	dir := fs.String("config", "", "")
+	if err := fs.Parse(os.Args[1:]); err == nil && *dir != "" {
+		return *dir
+	} else if d := os.Getenv("HEIMDALL_CONFIG"); d != "" {
+		return d
+	}
+	return f.DefValue
+}
+
+// AddPluginCmds adds the external plugins (see external.Discover) in the config
+// dir and on the PATH. It must be called after all built-in commands have been
+// added, some of them by init functions, so that plugins cannot shadow them.
+func AddPluginCmds() {
+	dirs := []string{ConfigDir()}
+	dirs = append(dirs, filepath.SplitList(os.Getenv("PATH"))...)
+	rootCmd.AddCommand(external.NewPluginCmds(rootCmd, dirs...)...)
+}
@@ -0,0 +1,79 @@
+// Copyright 2025 The Heimdall authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package root_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/abc-inc/heimdall/cli"
+	"github.com/abc-inc/heimdall/internal"
+	"github.com/abc-inc/heimdall/plugin/root"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunEvidence(t *testing.T) {
+	ios, _, out, _ := cli.Test()
+	cli.IO = ios
+	defer func() { cli.IO = cli.System() }()
+
+	dir, evDir := t.TempDir(), t.TempDir()
+	internal.MustNoErr(os.WriteFile(filepath.Join(dir, "a.yaml"), []byte("a: 1\n"), 0o600))
+	internal.MustNoErr(os.WriteFile(filepath.Join(dir, "b.json"), []byte(`{"b": 2}`), 0o600))
+	internal.MustNoErr(os.WriteFile(filepath.Join(dir, "Taskfile.yml"), []byte(`
+version: '3'
+tasks:
+  default:
+    interpreter: heimdall
+    cmds: [ 'parse a.yaml --output json', 'parse b.json --output json' ]
+`), 0o600))
+
+	cmd := root.GetRootCmd()
+	cmd.SetArgs([]string{"run", "--silent", "--evidence-dir", evDir, "-t", filepath.Join(dir, "Taskfile.yml")})
+	require.NoError(t, cmd.Execute())
+
+	// a single bundle of the run command containing the output and inputs of all nested commands
+	es := internal.Must(os.ReadDir(evDir))
+	require.Len(t, es, 1)
+	require.True(t, strings.HasSuffix(es[0].Name(), "-heimdall-run"), es[0].Name())
+
+	bd := filepath.Join(evDir, es[0].Name())
+	require.Equal(t, out.String(), string(internal.Must(os.ReadFile(filepath.Join(bd, cli.BundleOutputFile)))))
+	require.Contains(t, out.String(), `"a"`)
+	require.Contains(t, out.String(), `"b"`)
+
+	var b cli.Bundle
+	internal.MustNoErr(json.Unmarshal(internal.Must(os.ReadFile(filepath.Join(bd, cli.BundleCommandFile))), &b))
+	require.Len(t, b.Inputs, 2)
+}
+
+func TestAddPluginCmds(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("plugins are shell scripts")
+	}
+	dir := t.TempDir()
+	internal.MustNoErr(os.WriteFile(filepath.Join(dir, "heimdall-foo"), []byte("#!/bin/sh\necho foo\n"), 0o700))
+	t.Setenv("HEIMDALL_CONFIG", dir)
+	require.Equal(t, dir, root.ConfigDir())
+
+	root.AddPluginCmds()
+	cmd, _, err := root.GetRootCmd().Find([]string{"foo"})
+	require.NoError(t, err)
+	require.Equal(t, "foo", cmd.Name())
+}
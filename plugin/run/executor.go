@@ -0,0 +1,386 @@
+// Copyright 2025 The Heimdall authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !no_run
+
+package run
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"text/template"
+
+	"github.com/Masterminds/sprig/v3"
+	"github.com/abc-inc/heimdall/cli"
+	"github.com/abc-inc/heimdall/errs"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+	"mvdan.cc/sh/v3/syntax"
+)
+
+// executor runs the tasks of a Taskfile sequentially. Tasks are not run in
+// parallel, because commands run by the heimdall interpreter share the state
+// of the process.
+type executor struct {
+	tf      *Taskfile
+	dir     string
+	wd      string
+	root    *cobra.Command
+	restore func()
+	vars    Vars
+	cliArgs string
+	dry     bool
+	force   bool
+	silent  bool
+
+	// sh caches the output of dynamic variables by directory and command.
+	sh map[string]string
+	// calls are the tasks being run, i.e., the callers of the current task.
+	calls []string
+}
+
+// command is a templated command of a task, which is passed to an interpreter.
+type command struct {
+	task  string
+	text  string
+	dir   string
+	env   []string
+	set   []string
+	shopt []string
+}
+
+// run runs the given tasks, or the default task, if none is given.
+func (e *executor) run(ctx context.Context, tasks ...string) error {
+	if len(tasks) == 0 {
+		tasks = []string{"default"}
+	}
+	for _, t := range tasks {
+		if err := e.runTask(ctx, Call{Task: t}, nil, true); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// runTask runs the dependencies and commands of a task. The variables of the
+// call are evaluated with the data of the caller.
+func (e *executor) runTask(ctx context.Context, call Call, callerData map[string]any, fromCLI bool) error {
+	t, ok := e.tf.Tasks[call.Task]
+	if !ok {
+		return errs.Newf(errs.Usage, "task '%s' does not exist", call.Task)
+	} else if t.Internal && fromCLI {
+		return errs.Newf(errs.Usage, "task '%s' is internal and cannot be called directly", call.Task)
+	} else if i := slices.Index(e.calls, call.Task); i >= 0 {
+		return errs.Newf(errs.Usage, "task '%s' calls itself: %s", call.Task,
+			strings.Join(append(e.calls[i:], call.Task), " -> "))
+	}
+	e.calls = append(e.calls, call.Task)
+	defer func() { e.calls = e.calls[:len(e.calls)-1] }()
+
+	data, env, err := e.compile(ctx, call, t, callerData)
+	if err != nil {
+		return err
+	}
+	var missing []string
+	for _, v := range t.Requires.Vars {
+		if _, ok := data[v]; !ok {
+			missing = append(missing, v)
+		}
+	}
+	if len(missing) > 0 {
+		return errs.Newf(errs.Usage, "task '%s' requires the variables %s", call.Task, strings.Join(missing, ", "))
+	}
+
+	for _, d := range t.Deps {
+		if err := e.runTask(ctx, d, data, false); err != nil {
+			return err
+		}
+	}
+
+	c := command{
+		task:  call.Task,
+		dir:   e.taskDir(t, data),
+		env:   env,
+		set:   slices.Concat(e.tf.Set, t.Set),
+		shopt: slices.Concat(e.tf.Shopt, t.Shopt),
+	}
+	if !e.dry {
+		if upToDate, err := e.upToDate(ctx, t, c, data); err != nil {
+			return err
+		} else if upToDate {
+			e.logf("task: Task '%s' is up to date\n", call.Task)
+			return nil
+		}
+	}
+
+	for _, cmd := range t.Cmds {
+		if err := e.runCmd(ctx, t, cmd, c, data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// runCmd runs a command once, or for every item, if it contains a loop.
+func (e *executor) runCmd(ctx context.Context, t *Task, cmd Cmd, c command, data map[string]any) error {
+	items := []any{nil}
+	if cmd.For != nil {
+		items = forItems(cmd.For, data)
+	}
+
+	for _, item := range items {
+		d := data
+		if cmd.For != nil {
+			d = maps.Clone(data)
+			d[cmp.Or(cmd.For.As, "ITEM")] = item
+		}
+
+		if cmd.Task != "" {
+			name, err := e.template(cmd.Task, d)
+			if err != nil {
+				return err
+			} else if err = e.runTask(ctx, Call{Task: name, Vars: cmd.Vars}, d, false); err != nil {
+				return err
+			}
+			continue
+		}
+
+		text, err := e.template(cmd.Cmd, d)
+		if err != nil {
+			return err
+		}
+		c.text = text
+		if !(e.silent || e.tf.Silent || t.Silent || cmd.Silent) || e.dry {
+			e.logf("task: [%s] %s\n", c.task, strings.TrimSpace(c.text))
+		}
+		if e.dry {
+			continue
+		}
+
+		name := cmp.Or(cmd.Interpreter, t.Interpreter, e.tf.Interpreter, "sh")
+		interp, ok := interpreters[name]
+		if !ok {
+			return errs.Newf(errs.Usage, "task '%s' uses the unknown interpreter '%s'", c.task, name)
+		}
+		if err = interp(ctx, e, c); err != nil && cmd.IgnoreError {
+			log.Warn().Str("task", c.task).Err(err).Msg("Ignoring error")
+		} else if errors.Is(err, errs.ErrViolation) {
+			// Violations are not reported by the caller, only the exit code.
+			log.Error().Str("task", c.task).Msg(err.Error())
+			return err
+		} else if err != nil {
+			return errs.Newf(errs.KindOf(err), "task '%s' failed: %w", c.task, err)
+		}
+	}
+	return nil
+}
+
+// upToDate reports whether all status commands of a task succeed. Failing
+// preconditions abort the task.
+func (e *executor) upToDate(ctx context.Context, t *Task, c command, data map[string]any) (bool, error) {
+	for _, p := range t.Preconditions {
+		sh, err := e.template(p.Sh, data)
+		if err != nil {
+			return false, err
+		}
+		if err = e.shell(ctx, c, sh, nil); err != nil {
+			msg := cmp.Or(p.Msg, fmt.Sprintf("'%s' failed", sh))
+			return false, errs.Newf(errs.Input, "task '%s' precondition not met: %s", c.task, msg)
+		}
+	}
+
+	if e.force || len(t.Status) == 0 {
+		return false, nil
+	}
+	for _, s := range t.Status {
+		sh, err := e.template(s, data)
+		if err != nil {
+			return false, err
+		}
+		if e.shell(ctx, c, sh, nil) != nil {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// compile evaluates the variables and environment variables available to a
+// task. Variables declared by the task take precedence over those passed by the
+// caller, which take precedence over global variables and the environment.
+// Environment variables declared in the Taskfile are also available as
+// variables.
+func (e *executor) compile(ctx context.Context, call Call, t *Task, callerData map[string]any) (data map[string]any, env []string, err error) {
+	data = map[string]any{}
+	for _, kv := range os.Environ() {
+		k, v, _ := strings.Cut(kv, "=")
+		data[k] = v
+	}
+	data["TASK"] = call.Task
+	data["ROOT_DIR"] = e.dir
+	data["TASKFILE_DIR"] = e.dir
+	data["USER_WORKING_DIR"] = e.wd
+	data["CLI_ARGS"] = e.cliArgs
+
+	c := command{task: call.Task, dir: e.dir, set: e.tf.Set, shopt: e.tf.Shopt}
+	eval := func(vs Vars, isEnv bool) error {
+		for _, v := range vs {
+			val, err := e.value(ctx, c, v, data)
+			if err != nil {
+				return err
+			}
+			data[v.Name] = val
+			if isEnv {
+				env = append(env, v.Name+"="+fmt.Sprint(val))
+				c.env = env
+			}
+		}
+		return nil
+	}
+
+	if err = eval(e.tf.Env, true); err != nil {
+		return nil, nil, err
+	} else if err = eval(e.tf.Vars, false); err != nil {
+		return nil, nil, err
+	} else if err = eval(e.vars, false); err != nil {
+		return nil, nil, err
+	}
+
+	// The variables of the call are evaluated in the context of the caller.
+	if len(call.Vars) > 0 {
+		cd := maps.Clone(callerData)
+		for _, v := range call.Vars {
+			val, err := e.value(ctx, c, v, cd)
+			if err != nil {
+				return nil, nil, err
+			}
+			cd[v.Name], data[v.Name] = val, val
+		}
+	}
+
+	c.dir = e.taskDir(t, data)
+	if err = eval(t.Vars, false); err != nil {
+		return nil, nil, err
+	} else if err = eval(t.Env, true); err != nil {
+		return nil, nil, err
+	}
+	return data, env, nil
+}
+
+// value evaluates a variable, i.e., it either renders the template of a static
+// value, or it runs the shell command of a dynamic variable.
+func (e *executor) value(ctx context.Context, c command, v Var, data map[string]any) (any, error) {
+	if v.Sh == "" {
+		if s, ok := v.Value.(string); ok {
+			return e.template(s, data)
+		}
+		return v.Value, nil
+	}
+
+	sh, err := e.template(v.Sh, data)
+	if err != nil {
+		return nil, err
+	}
+	key := c.dir + "\x00" + sh
+	if out, ok := e.sh[key]; ok {
+		return out, nil
+	}
+
+	buf := &bytes.Buffer{}
+	if err = e.shell(ctx, c, sh, buf); err != nil {
+		return nil, errs.Newf(errs.Input, "cannot evaluate variable '%s' of task '%s': %v", v.Name, c.task, err)
+	}
+	out := strings.TrimRight(buf.String(), "\r\n")
+	e.sh[key] = out
+	return out, nil
+}
+
+// taskDir returns the directory, in which the commands of a task are run.
+func (e *executor) taskDir(t *Task, data map[string]any) string {
+	dir, err := e.template(t.Dir, data)
+	if err != nil || dir == "" {
+		return e.dir
+	} else if !filepath.IsAbs(dir) {
+		return filepath.Join(e.dir, dir)
+	}
+	return dir
+}
+
+// template renders a Go template with the given variables. Missing variables
+// are rendered as empty string.
+func (e *executor) template(text string, data map[string]any) (string, error) {
+	if !strings.Contains(text, "{{") {
+		return text, nil
+	}
+	t, err := template.New("").Funcs(funcs).Parse(text)
+	if err != nil {
+		return "", errs.Newf(errs.Usage, "invalid template '%s': %w", text, err)
+	}
+	buf := &bytes.Buffer{}
+	if err = t.Execute(buf, data); err != nil {
+		return "", errs.Newf(errs.Usage, "cannot render template '%s': %w", text, err)
+	}
+	return strings.ReplaceAll(buf.String(), "<no value>", ""), nil
+}
+
+func (e *executor) logf(format string, a ...any) {
+	_, _ = fmt.Fprintf(cli.IO.ErrOut, format, a...)
+}
+
+// forItems returns the elements of the list or variable to loop over.
+func forItems(f *For, data map[string]any) []any {
+	if f.Var == "" {
+		return f.List
+	}
+	switch v := data[f.Var].(type) {
+	case []any:
+		return v
+	case nil:
+		return nil
+	default:
+		var ps []string
+		if f.Split == "" {
+			ps = strings.Fields(fmt.Sprint(v))
+		} else {
+			ps = strings.Split(fmt.Sprint(v), f.Split)
+		}
+		items := make([]any, len(ps))
+		for i, p := range ps {
+			items[i] = p
+		}
+		return items
+	}
+}
+
+// funcs are the template functions, i.e., sprig and some of the functions
+// provided by Task.
+var funcs = func() template.FuncMap {
+	fm := sprig.TxtFuncMap()
+	fm["OS"] = func() string { return runtime.GOOS }
+	fm["ARCH"] = func() string { return runtime.GOARCH }
+	fm["splitLines"] = func(s string) []string { return strings.Split(strings.ReplaceAll(s, "\r\n", "\n"), "\n") }
+	fm["catLines"] = func(s string) string { return strings.NewReplacer("\r\n", " ", "\n", " ").Replace(s) }
+	fm["shellQuote"] = func(s string) (string, error) { return syntax.Quote(s, syntax.LangBash) }
+	fm["toSlash"] = filepath.ToSlash
+	fm["fromSlash"] = filepath.FromSlash
+	return fm
+}()
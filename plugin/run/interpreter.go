@@ -0,0 +1,189 @@
+// Copyright 2025 The Heimdall authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !no_run
+
+package run
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/abc-inc/heimdall/cli"
+	"github.com/abc-inc/heimdall/errs"
+	"github.com/abc-inc/heimdall/plugin/eval"
+	"github.com/rs/zerolog/log"
+	"mvdan.cc/sh/v3/expand"
+	"mvdan.cc/sh/v3/interp"
+	"mvdan.cc/sh/v3/shell"
+	"mvdan.cc/sh/v3/syntax"
+)
+
+// interpreter executes a command of a task.
+type interpreter func(ctx context.Context, e *executor, c command) error
+
+// interpreters are the supported values of the "interpreter" property of
+// commands and tasks.
+var interpreters = map[string]interpreter{
+	"bash":     runShell,
+	"eval":     runEval,
+	"heimdall": runHeimdall,
+	"sh":       runShell,
+}
+
+// runShell runs the command by the built-in shell interpreter.
+func runShell(ctx context.Context, e *executor, c command) error {
+	return e.shell(ctx, c, c.text, cli.IO.Out)
+}
+
+// shell runs a script by the built-in shell interpreter, which is compatible
+// with bash. Calls of heimdall are redirected to the running executable.
+func (e *executor) shell(ctx context.Context, c command, script string, out io.Writer) error {
+	var prefix strings.Builder
+	for _, o := range c.set {
+		prefix.WriteString("set -o " + o + "\n")
+	}
+	for _, o := range c.shopt {
+		prefix.WriteString("shopt -s " + o + "\n")
+	}
+
+	f, err := syntax.NewParser().Parse(strings.NewReader(prefix.String()+script), "")
+	if err != nil {
+		return errs.Newf(errs.Usage, "invalid shell command: %w", err)
+	}
+	if out == nil {
+		out = io.Discard
+	}
+	r, err := interp.New(
+		interp.Dir(c.dir),
+		interp.Env(expand.ListEnviron(append(os.Environ(), c.env...)...)),
+		interp.StdIO(cli.IO.In, out, cli.IO.ErrOut),
+		interp.CallHandler(func(ctx context.Context, args []string) ([]string, error) {
+			if args[0] == "heimdall" {
+				if exe, err := os.Executable(); err == nil {
+					args[0] = exe
+				}
+			}
+			return args, nil
+		}),
+	)
+	if err != nil {
+		return errs.New(errs.Failure, err)
+	}
+
+	if err = r.Run(ctx, f); err != nil {
+		if code, ok := interp.IsExitStatus(err); ok {
+			// Commands are expected to use the same exit codes as Heimdall.
+			k := errs.Kind(code)
+			if k < errs.Violation || k > errs.Failure {
+				k = errs.Failure
+			}
+			return errs.Newf(k, "exit status %d", code)
+		}
+		return errs.New(errs.Failure, err)
+	}
+	return nil
+}
+
+// runEval evaluates the command as expression against the files given by the
+// environment variable HEIMDALL_FILE (one per line, see 'heimdall eval').
+func runEval(ctx context.Context, e *executor, c command) error {
+	var files []string
+	for _, kv := range c.env {
+		if v, ok := strings.CutPrefix(kv, "HEIMDALL_FILE="); ok {
+			files = nil
+			for _, f := range strings.Split(v, "\n") {
+				if f = strings.TrimSpace(f); f != "" && f != "-" && !filepath.IsAbs(f) {
+					f = filepath.Join(c.dir, f)
+				}
+				if f != "" {
+					files = append(files, f)
+				}
+			}
+		}
+	}
+
+	inputs, err := eval.ResolveFiles(files)
+	if err != nil {
+		return err
+	}
+	res, err := eval.Evaluate("expr", []string{c.text}, inputs...)
+	if err != nil {
+		return err
+	}
+	log.Debug().Str("task", c.task).Strs("result", res).Msg("Evaluated expression")
+	if !eval.Truthy(res) {
+		return errs.Newf(errs.Violation, "expression '%s' evaluated to '%s'", strings.TrimSpace(c.text), strings.Join(res, "\n"))
+	}
+	return nil
+}
+
+// runHeimdall runs the command in-process. The leading "heimdall" is optional.
+// The working directory and the environment variables of the task are set for
+// the duration of the command. Its output and inputs are recorded as part of
+// the evidence of the run command (see cli.RunNested).
+func runHeimdall(ctx context.Context, e *executor, c command) (err error) {
+	envs := map[string]string{}
+	for _, kv := range c.env {
+		k, v, _ := strings.Cut(kv, "=")
+		envs[k] = v
+	}
+	args, err := shell.Fields(c.text, func(k string) string {
+		if v, ok := envs[k]; ok {
+			return v
+		}
+		return os.Getenv(k)
+	})
+	if err != nil {
+		return errs.Newf(errs.Usage, "invalid command: %w", err)
+	}
+	if len(args) > 0 && args[0] == "heimdall" {
+		args = args[1:]
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		return errs.New(errs.Failure, err)
+	} else if err = os.Chdir(c.dir); err != nil {
+		return errs.New(errs.Input, err)
+	}
+	defer func() { _ = os.Chdir(wd) }()
+
+	for k, v := range envs {
+		if old, ok := os.LookupEnv(k); ok {
+			defer func() { _ = os.Setenv(k, old) }()
+		} else {
+			defer func() { _ = os.Unsetenv(k) }()
+		}
+		_ = os.Setenv(k, v)
+	}
+
+	base := cli.BaseContext
+	cli.BaseContext = func() context.Context { return ctx }
+	defer func() { cli.BaseContext = base }()
+	defer e.restore()
+
+	cli.RunNested(func() {
+		defer errs.Recover(&err)
+		e.root.SetArgs(args)
+		if _, err = e.root.ExecuteC(); err != nil && !errors.As(err, new(*errs.Error)) {
+			err = errs.New(errs.Usage, err)
+		}
+	})
+	return err
+}
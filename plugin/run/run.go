@@ -0,0 +1,196 @@
+// Copyright 2025 The Heimdall authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !no_run
+
+package run
+
+import (
+	"cmp"
+	"errors"
+	"io"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/MakeNowJust/heredoc/v2"
+	"github.com/abc-inc/heimdall"
+	"github.com/abc-inc/heimdall/cli"
+	"github.com/abc-inc/heimdall/errs"
+	"github.com/abc-inc/heimdall/internal"
+	"github.com/spf13/cobra"
+	"mvdan.cc/sh/v3/syntax"
+)
+
+// taskfiles are the names of the Taskfiles looked up, if none is given.
+var taskfiles = []string{"Taskfile.yml", "Taskfile.yaml", "taskfile.yml", "taskfile.yaml"}
+
+type runCfg struct {
+	cli.OutCfg
+	taskfile string
+	dir      string
+	list     bool
+	listAll  bool
+	dry      bool
+	force    bool
+	silent   bool
+}
+
+// Entry is a task listed by 'heimdall run --list'.
+type Entry struct {
+	Task string `json:"task" yaml:"task"`
+	Desc string `json:"desc" yaml:"desc"`
+}
+
+func NewRunCmd() *cobra.Command {
+	cfg := runCfg{}
+	cmd := &cobra.Command{
+		Use:   "run [flags] [<task>...] [<name>=<value>...] [-- <args>...]",
+		Short: "Run a Heimdall task file",
+		Long: heredoc.Doc(`
+			Run the tasks of a Taskfile (see https://taskfile.dev) in-process.
+			If no task is given, the task "default" is run.
+
+			Besides the file name, --taskfile accepts the name of a built-in example (see 'heimdall example --list').
+			Variables can be set by <name>=<value> arguments, and arguments after "--" are available as {{.CLI_ARGS}}.
+
+			The interpreter of a command is set by the property "interpreter" of the command, the task or the Taskfile:
+			  sh, bash  the built-in shell, which is compatible with bash (default)
+			  eval      evaluates the command as expression against the files in HEIMDALL_FILE (see 'heimdall eval')
+			  heimdall  runs the command as Heimdall subcommand in the same process, e.g., "parse pom.xml"
+
+			Tasks and their dependencies are run sequentially.
+			Taskfile features like includes, sources, watch mode and parallel execution are not supported.
+		`),
+		Example: heredoc.Doc(`
+			# run the built-in GitHub checks in the current repository
+			heimdall run -t github
+
+			# list the tasks of the Taskfile in the current directory
+			heimdall run --list
+		`),
+		GroupID: cli.HeimdallGroup,
+		Args:    cobra.ArbitraryArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			var cliArgs []string
+			if i := cmd.ArgsLenAtDash(); i >= 0 {
+				args, cliArgs = args[:i], args[i:]
+			}
+			internal.MustNoErr(run(cmd, cfg, args, cliArgs))
+		},
+	}
+
+	cmd.Flags().StringVarP(&cfg.taskfile, "taskfile", "t", cfg.taskfile, `Taskfile or name of a built-in example (default "Taskfile.yml")`)
+	cmd.Flags().StringVarP(&cfg.dir, "dir", "d", cfg.dir, "Directory of the Taskfile")
+	cmd.Flags().BoolVarP(&cfg.list, "list", "l", cfg.list, "List tasks with description")
+	cmd.Flags().BoolVarP(&cfg.listAll, "list-all", "a", cfg.listAll, "List all tasks except internal ones")
+	cmd.Flags().BoolVarP(&cfg.dry, "dry", "n", cfg.dry, "Print the commands without executing them")
+	cmd.Flags().BoolVarP(&cfg.force, "force", "f", cfg.force, "Run tasks even if they are up to date")
+	cmd.Flags().BoolVarP(&cfg.silent, "silent", "s", cfg.silent, "Do not print the commands before executing them")
+	cli.AddOutputFlags(cmd, &cfg.OutCfg)
+	cmd.MarkFlagsMutuallyExclusive("taskfile", "dir")
+	return cmd
+}
+
+func run(cmd *cobra.Command, cfg runCfg, args, cliArgs []string) error {
+	tf, dir, err := loadTaskfile(cfg)
+	if err != nil {
+		return err
+	}
+
+	if cfg.list || cfg.listAll {
+		var es []Entry
+		for _, n := range slices.Sorted(maps.Keys(tf.Tasks)) {
+			if t := tf.Tasks[n]; !t.Internal && (cfg.listAll || t.Desc != "") {
+				es = append(es, Entry{Task: n, Desc: t.Desc})
+			}
+		}
+		cli.Fmtln(es)
+		return nil
+	}
+
+	e := &executor{
+		tf:      tf,
+		dir:     dir,
+		wd:      internal.Must(os.Getwd()),
+		root:    cmd.Root(),
+		restore: cli.SaveFlags(cmd.Root()),
+		dry:     cfg.dry,
+		force:   cfg.force,
+		silent:  cfg.silent,
+		sh:      map[string]string{},
+	}
+
+	var tasks []string
+	for _, a := range args {
+		if k, v, ok := strings.Cut(a, "="); ok {
+			e.vars = append(e.vars, Var{Name: k, Value: v})
+		} else {
+			tasks = append(tasks, a)
+		}
+	}
+	var quoted []string
+	for _, a := range cliArgs {
+		q, err := syntax.Quote(a, syntax.LangBash)
+		if err != nil {
+			return errs.New(errs.Usage, err)
+		}
+		quoted = append(quoted, q)
+	}
+	e.cliArgs = strings.Join(quoted, " ")
+
+	return e.run(cmd.Context(), tasks...)
+}
+
+// loadTaskfile reads the Taskfile given by --taskfile or found in --dir, and
+// returns it along with its directory. Built-in examples are run in the
+// current working directory.
+func loadTaskfile(cfg runCfg) (*Taskfile, string, error) {
+	var r io.ReadCloser
+	var err error
+	name := cfg.taskfile
+
+	if name == "" {
+		for _, n := range taskfiles {
+			if _, err = os.Stat(filepath.Join(cfg.dir, n)); err == nil {
+				name = filepath.Join(cfg.dir, n)
+				break
+			}
+		}
+		if name == "" {
+			return nil, "", errs.Newf(errs.Input, "no Taskfile found in '%s'", cmp.Or(cfg.dir, "."))
+		}
+	}
+
+	dir := filepath.Dir(name)
+	if r, err = os.Open(name); errors.Is(err, fs.ErrNotExist) && !strings.ContainsAny(name, `/\.`) {
+		if r, err = heimdall.StaticFS.Open("docs/examples/" + name + ".yaml"); err != nil {
+			return nil, "", errs.Newf(errs.Input, "neither a Taskfile nor an example named '%s' exists", name)
+		}
+		dir = "."
+	} else if err != nil {
+		return nil, "", errs.New(errs.Input, err)
+	}
+	defer func() { _ = r.Close() }()
+
+	tf, err := DecodeTaskfile(r)
+	if err != nil {
+		return nil, "", errs.Newf(errs.Input, "cannot read Taskfile '%s': %w", name, err)
+	}
+	dir, err = filepath.Abs(dir)
+	return tf, dir, errs.New(errs.Failure, err)
+}
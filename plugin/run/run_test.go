@@ -0,0 +1,109 @@
+// Copyright 2025 The Heimdall authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !no_run
+
+package run
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/abc-inc/heimdall/cli"
+	"github.com/abc-inc/heimdall/errs"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
+)
+
+const taskfile = `
+version: '3'
+env:
+  GREETING: Hello
+vars:
+  NAMES: a b
+tasks:
+  default:
+    deps: [ prepare ]
+    env: { HEIMDALL_FILE: input.yaml }
+    cmds:
+      - for: { var: NAMES }
+        task: greet
+        vars: { NAME: '{{.ITEM}}' }
+      - cmd: version == 3
+        interpreter: eval
+      - cmd: greet --name "$GREETING"
+        interpreter: heimdall
+  prepare:
+    cmds: [ 'echo "version: 3" > input.yaml' ]
+    status: [ test -f input.yaml ]
+  greet:
+    internal: true
+    requires: { vars: [ NAME ] }
+    cmds: [ 'echo "${GREETING}, {{.NAME}}"' ]
+  fail:
+    cmds:
+      - cmd: version == 4
+        interpreter: eval
+    env: { HEIMDALL_FILE: input.yaml }
+`
+
+func newExecutor(t *testing.T) *executor {
+	dir := t.TempDir()
+	tf, err := DecodeTaskfile(strings.NewReader(taskfile))
+	require.NoError(t, err)
+
+	root := &cobra.Command{Use: "heimdall", SilenceErrors: true, SilenceUsage: true}
+	var name string
+	greet := &cobra.Command{Use: "greet", Run: func(cmd *cobra.Command, args []string) {
+		_, _ = cli.Msg("greet " + name + "\n")
+	}}
+	greet.Flags().StringVar(&name, "name", "", "")
+	root.AddCommand(greet)
+
+	wd, _ := os.Getwd()
+	return &executor{tf: tf, dir: dir, wd: wd, root: root, restore: cli.SaveFlags(root), silent: true, sh: map[string]string{}}
+}
+
+func TestRun(t *testing.T) {
+	s, _, out, _ := cli.Test()
+	cli.IO = s
+
+	e := newExecutor(t)
+	require.NoError(t, e.run(context.Background()))
+	require.Equal(t, "Hello, a\nHello, b\ngreet Hello\n", out.String())
+	require.FileExists(t, filepath.Join(e.dir, "input.yaml"))
+
+	err := e.run(context.Background(), "fail")
+	require.ErrorIs(t, err, errs.ErrViolation)
+}
+
+func TestRun_Errors(t *testing.T) {
+	e := newExecutor(t)
+	require.Equal(t, errs.Usage, errs.KindOf(e.run(context.Background(), "missing")))
+	require.ErrorContains(t, e.run(context.Background(), "greet"), "internal")
+
+	e.tf.Tasks["greet"].Internal = false
+	require.ErrorContains(t, e.run(context.Background(), "greet"), "requires the variables NAME")
+
+	e.tf.Tasks["a"] = &Task{Deps: []Call{{Task: "b"}}}
+	e.tf.Tasks["b"] = &Task{Cmds: []Cmd{{Task: "c"}}}
+	e.tf.Tasks["c"] = &Task{Deps: []Call{{Task: "a"}}}
+	err := e.run(context.Background(), "a")
+	require.Equal(t, errs.Usage, errs.KindOf(err))
+	require.ErrorContains(t, err, "task 'a' calls itself: a -> b -> c -> a")
+	require.Empty(t, e.calls)
+}
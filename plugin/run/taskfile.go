@@ -0,0 +1,175 @@
+// Copyright 2025 The Heimdall authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !no_run
+
+package run
+
+import (
+	"fmt"
+	"io"
+	"slices"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Taskfile is the subset of the Taskfile schema (version 3) supported by the
+// built-in task runner, see https://taskfile.dev/reference/schema.
+type Taskfile struct {
+	Version     string           `yaml:"version"`
+	Set         []string         `yaml:"set"`
+	Shopt       []string         `yaml:"shopt"`
+	Env         Vars             `yaml:"env"`
+	Vars        Vars             `yaml:"vars"`
+	Silent      bool             `yaml:"silent"`
+	Interpreter string           `yaml:"interpreter"`
+	Tasks       map[string]*Task `yaml:"tasks"`
+}
+
+// Task is a named list of commands and their dependencies.
+type Task struct {
+	Desc          string         `yaml:"desc"`
+	Summary       string         `yaml:"summary"`
+	Deps          []Call         `yaml:"deps"`
+	Cmd           *Cmd           `yaml:"cmd"`
+	Cmds          []Cmd          `yaml:"cmds"`
+	Dir           string         `yaml:"dir"`
+	Env           Vars           `yaml:"env"`
+	Vars          Vars           `yaml:"vars"`
+	Set           []string       `yaml:"set"`
+	Shopt         []string       `yaml:"shopt"`
+	Silent        bool           `yaml:"silent"`
+	Internal      bool           `yaml:"internal"`
+	Interpreter   string         `yaml:"interpreter"`
+	Status        []string       `yaml:"status"`
+	Preconditions []Precondition `yaml:"preconditions"`
+	Requires      struct {
+		Vars []string `yaml:"vars"`
+	} `yaml:"requires"`
+}
+
+// Cmd is either a command to be executed by an interpreter, or a call of
+// another task.
+type Cmd struct {
+	Cmd         string `yaml:"cmd"`
+	Task        string `yaml:"task"`
+	Vars        Vars   `yaml:"vars"`
+	For         *For   `yaml:"for"`
+	Silent      bool   `yaml:"silent"`
+	Interpreter string `yaml:"interpreter"`
+	IgnoreError bool   `yaml:"ignore_error"`
+}
+
+func (c *Cmd) UnmarshalYAML(n *yaml.Node) error {
+	if n.Kind == yaml.ScalarNode {
+		return n.Decode(&c.Cmd)
+	}
+	type cmd Cmd
+	return n.Decode((*cmd)(c))
+}
+
+// Call is a dependency of a task.
+type Call struct {
+	Task string `yaml:"task"`
+	Vars Vars   `yaml:"vars"`
+}
+
+func (c *Call) UnmarshalYAML(n *yaml.Node) error {
+	if n.Kind == yaml.ScalarNode {
+		return n.Decode(&c.Task)
+	}
+	type call Call
+	return n.Decode((*call)(c))
+}
+
+// For repeats a command for every element of a list or a variable. Variables
+// are split at whitespace, unless a separator is given.
+type For struct {
+	List  []any  `yaml:"-"`
+	Var   string `yaml:"var"`
+	Split string `yaml:"split"`
+	As    string `yaml:"as"`
+}
+
+func (f *For) UnmarshalYAML(n *yaml.Node) error {
+	if n.Kind == yaml.SequenceNode {
+		return n.Decode(&f.List)
+	}
+	type forCmd For
+	return n.Decode((*forCmd)(f))
+}
+
+// Precondition is a shell command, which must succeed before a task is run.
+type Precondition struct {
+	Sh  string `yaml:"sh"`
+	Msg string `yaml:"msg"`
+}
+
+func (p *Precondition) UnmarshalYAML(n *yaml.Node) error {
+	if n.Kind == yaml.ScalarNode {
+		return n.Decode(&p.Sh)
+	}
+	type precondition Precondition
+	return n.Decode((*precondition)(p))
+}
+
+// Var is either a static value or a shell command, whose output is the value.
+type Var struct {
+	Name  string
+	Value any
+	Sh    string
+}
+
+// Vars are variables in the order of their declaration, so that a variable can
+// refer to the ones declared before.
+type Vars []Var
+
+func (vs *Vars) UnmarshalYAML(n *yaml.Node) error {
+	if n.Kind != yaml.MappingNode {
+		return fmt.Errorf("line %d: variables must be a mapping", n.Line)
+	}
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		v := Var{Name: n.Content[i].Value}
+		if val := n.Content[i+1]; val.Kind == yaml.MappingNode {
+			var dyn struct {
+				Sh string `yaml:"sh"`
+			}
+			if err := val.Decode(&dyn); err != nil {
+				return err
+			}
+			v.Sh = dyn.Sh
+		} else if err := val.Decode(&v.Value); err != nil {
+			return err
+		}
+		*vs = append(*vs, v)
+	}
+	return nil
+}
+
+// DecodeTaskfile reads a Taskfile.
+func DecodeTaskfile(r io.Reader) (*Taskfile, error) {
+	tf := &Taskfile{}
+	if err := yaml.NewDecoder(r).Decode(tf); err != nil {
+		return nil, err
+	}
+	if tf.Version != "3" && tf.Version != "" {
+		return nil, fmt.Errorf("unsupported Taskfile version '%s', must be '3'", tf.Version)
+	}
+	for _, t := range tf.Tasks {
+		if t.Cmd != nil {
+			t.Cmds = slices.Insert(t.Cmds, 0, *t.Cmd)
+		}
+	}
+	return tf, nil
+}
@@ -0,0 +1,433 @@
+// Copyright 2025 The Heimdall authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !no_serve
+
+package serve
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"mime"
+	"net"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/MakeNowJust/heredoc/v2"
+	"github.com/abc-inc/heimdall/cli"
+	"github.com/abc-inc/heimdall/errs"
+	"github.com/abc-inc/heimdall/internal"
+	"github.com/gobwas/glob"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+)
+
+// maxBodySize is the maximum size of a request body.
+const maxBodySize = 1 << 20
+
+// readOnly are the patterns of the exposed command paths. Only commands, which
+// neither modify files, credentials or remote resources nor run arbitrary
+// commands, are exposed, e.g., keyring, run, parse edit and serve are not.
+var readOnly = []string{
+	"artifactory/list",
+	"check",
+	"confluence/search",
+	"cyclonedx/read",
+	"docker/**",
+	"eval",
+	"git/**",
+	"github/**",
+	"go/**",
+	"html",
+	"java/**",
+	"jira/attachment/list",
+	"jira/issue/**",
+	"jira/version/**",
+	"k8s/**",
+	"parse",
+	"parse/convert",
+	"parse/validate",
+	"ssh/**",
+	"terraform/**",
+	"version",
+}
+
+// writeFlags are the flags, which make otherwise read-only commands write files.
+var writeFlags = []string{"dest", "export"}
+
+type serveCfg struct {
+	addr           string
+	tokenFile      string
+	maxQueued      int
+	requestTimeout time.Duration
+}
+
+// server runs commands of the command tree per request.
+type server struct {
+	root    *cobra.Command
+	cfg     serveCfg
+	token   string
+	sem     chan struct{}
+	lock    chan struct{}
+	restore func()
+	methods map[string]*cobra.Command
+}
+
+// Endpoint describes a command exposed by the server.
+type Endpoint struct {
+	Path  string `json:"path" yaml:"path"`
+	Short string `json:"short" yaml:"short"`
+}
+
+func NewServeCmd() *cobra.Command {
+	cfg := serveCfg{addr: "127.0.0.1:8080", maxQueued: 4, requestTimeout: time.Minute}
+	cmd := &cobra.Command{
+		Use:   "serve",
+		Short: "Expose commands as a local REST API",
+		Long: heredoc.Doc(`
+			Expose commands as a local REST API.
+
+			Every command is available as 'POST /v1/<command path>', e.g., 'POST /v1/github/repositories/branch-protection'.
+			The request body is a JSON object with the flags of the command, and the positional arguments in "args".
+			The response contains the output of the command, which is JSON by default.
+			'GET /v1' lists all endpoints.
+
+			Commands are executed one at a time, i.e., the concurrency limit is 1, because they share the state of the
+			process. Further requests wait until the running command completes. Requests exceeding --max-queued waiting
+			requests are rejected with status 503.
+			Global flags like --profile apply to all requests and cannot be set per request.
+
+			Only read-only commands are exposed, and flags writing files cannot be set.
+			Requests must contain the header 'Authorization: Bearer <token>' with the token from HEIMDALL_SERVE_TOKEN or
+			--token-file. If neither is set, a token is generated and logged at startup.
+			Requests must be sent to a loopback host name, e.g., 127.0.0.1 or localhost, and have the content type
+			application/json.
+		`),
+		Example: heredoc.Doc(`
+			heimdall serve --addr 127.0.0.1:8080 --token-file /run/secrets/heimdall-serve
+			curl -X POST -H "Authorization: Bearer $TOKEN" -H "Content-Type: application/json" -d '{"owner": "abc-inc", "repo": "heimdall", "branch": "main"}' \
+			  http://127.0.0.1:8080/v1/github/repositories/branch-protection
+		`),
+		GroupID: cli.HeimdallGroup,
+		Args:    cobra.ExactArgs(0),
+		Run: func(cmd *cobra.Command, args []string) {
+			s := newServer(cmd.Root(), cfg)
+			internal.MustNoErr(s.listenAndServe(cmd.Context()))
+		},
+	}
+
+	cmd.Flags().StringVar(&cfg.addr, "addr", cfg.addr, "Address to listen on")
+	cmd.Flags().StringVar(&cfg.tokenFile, "token-file", cfg.tokenFile, "File containing the bearer token required for requests")
+	cmd.Flags().IntVar(&cfg.maxQueued, "max-queued", cfg.maxQueued, "Maximum number of requests waiting for the running command")
+	cmd.Flags().DurationVar(&cfg.requestTimeout, "request-timeout", cfg.requestTimeout, "Cancel commands after this duration")
+	return cmd
+}
+
+func newServer(root *cobra.Command, cfg serveCfg) *server {
+	s := &server{
+		root:    root,
+		cfg:     cfg,
+		token:   os.Getenv("HEIMDALL_SERVE_TOKEN"),
+		sem:     make(chan struct{}, max(cfg.maxQueued, 0)+1),
+		lock:    make(chan struct{}, 1),
+		methods: map[string]*cobra.Command{},
+	}
+	if cfg.tokenFile != "" {
+		b := internal.Must(os.ReadFile(cfg.tokenFile))
+		s.token = strings.TrimSpace(string(b))
+	}
+	if s.token == "" {
+		s.token = hex.EncodeToString(internal.Must(randomBytes(32)))
+		log.Warn().Str("token", s.token).Msg("No token configured, generated a bearer token")
+	}
+
+	var gs []glob.Glob
+	for _, p := range readOnly {
+		gs = append(gs, glob.MustCompile(p, '/'))
+	}
+	var walk func(c *cobra.Command)
+	walk = func(c *cobra.Command) {
+		if c.Hidden {
+			return
+		}
+		p := strings.Join(strings.Fields(c.CommandPath())[1:], "/")
+		if c.Runnable() && c != root && slices.ContainsFunc(gs, func(g glob.Glob) bool { return g.Match(p) }) {
+			s.methods["/v1/"+p] = c
+		}
+		for _, sub := range c.Commands() {
+			walk(sub)
+		}
+	}
+	walk(root)
+	s.restore = cli.SaveFlags(root)
+	return s
+}
+
+// randomBytes returns n cryptographically secure random bytes.
+func randomBytes(n int) ([]byte, error) {
+	b := make([]byte, n)
+	_, err := rand.Read(b)
+	return b, err
+}
+
+func (s *server) listenAndServe(ctx context.Context) error {
+	srv := &http.Server{
+		Addr:              s.cfg.addr,
+		Handler:           s,
+		ReadHeaderTimeout: 10 * time.Second,
+		ReadTimeout:       30 * time.Second,
+	}
+	go func() {
+		<-ctx.Done()
+		shutdown, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = srv.Shutdown(shutdown)
+	}()
+
+	log.Info().Str("addr", s.cfg.addr).Int("endpoints", len(s.methods)).Msg("Serving")
+	if err := srv.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+		return errs.New(errs.Failure, err)
+	}
+	return nil
+}
+
+func (s *server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	// reject requests via other host names, e.g., due to DNS rebinding
+	if !isLoopback(r.Host) {
+		writeError(w, http.StatusMisdirectedRequest, fmt.Errorf("host %s not allowed", r.Host))
+		return
+	}
+	t, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok || subtle.ConstantTimeCompare([]byte(t), []byte(s.token)) != 1 {
+		w.Header().Set("WWW-Authenticate", "Bearer")
+		writeError(w, http.StatusUnauthorized, errors.New("missing or invalid bearer token"))
+		return
+	}
+
+	if r.URL.Path == "/v1" || r.URL.Path == "/v1/" {
+		s.list(w, r)
+		return
+	}
+	cmd, ok := s.methods[r.URL.Path]
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Errorf("no command for %s", r.URL.Path))
+		return
+	} else if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s not allowed", r.Method))
+		return
+	} else if t, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); t != "application/json" {
+		writeError(w, http.StatusUnsupportedMediaType, errors.New("content type must be application/json"))
+		return
+	}
+
+	select {
+	case s.sem <- struct{}{}:
+		defer func() { <-s.sem }()
+	default:
+		w.Header().Set("Retry-After", "1")
+		writeError(w, http.StatusServiceUnavailable, errors.New("too many requests queued"))
+		return
+	}
+
+	args, err := s.args(cmd, r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), s.cfg.requestTimeout)
+	defer cancel()
+	select {
+	case s.lock <- struct{}{}:
+		defer func() { <-s.lock }()
+	case <-ctx.Done():
+		writeError(w, http.StatusServiceUnavailable, errors.New("timeout while waiting for other requests"))
+		return
+	}
+
+	out, err := s.run(ctx, cmd, args)
+	s.respond(w, out, err)
+}
+
+// isLoopback reports whether the host (with optional port) is localhost or a
+// loopback address.
+func isLoopback(host string) bool {
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	if ip := net.ParseIP(strings.Trim(host, "[]")); ip != nil {
+		return ip.IsLoopback()
+	}
+	return strings.EqualFold(host, "localhost")
+}
+
+// list writes the endpoints.
+func (s *server) list(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", http.MethodGet)
+		writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s not allowed", r.Method))
+		return
+	}
+	var es []Endpoint
+	for _, p := range slices.Sorted(maps.Keys(s.methods)) {
+		es = append(es, Endpoint{Path: p, Short: s.methods[p].Short})
+	}
+	writeJSON(w, http.StatusOK, es)
+}
+
+// args converts the JSON object in the request body to command line arguments.
+// Flags of the root command are reserved for the server.
+func (s *server) args(cmd *cobra.Command, r *http.Request) ([]string, error) {
+	body := map[string]any{}
+	d := json.NewDecoder(http.MaxBytesReader(nil, r.Body, maxBodySize))
+	d.UseNumber()
+	if err := d.Decode(&body); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("invalid request body: %w", err)
+	}
+
+	args := strings.Fields(cmd.CommandPath())[1:]
+	var pos []string
+	for _, k := range slices.Sorted(maps.Keys(body)) {
+		vs, err := values(body[k])
+		if err != nil {
+			return nil, fmt.Errorf("invalid value of '%s': %w", k, err)
+		}
+		if k == "args" {
+			pos = vs
+			continue
+		} else if strings.ContainsRune(k, '=') || strings.HasPrefix(k, "-") {
+			return nil, fmt.Errorf("invalid flag name '%s'", k)
+		} else if s.root.PersistentFlags().Lookup(k) != nil || slices.Contains(writeFlags, k) {
+			return nil, fmt.Errorf("flag '%s' cannot be set per request", k)
+		} else if cmd.Flags().Lookup(k) == nil && cmd.InheritedFlags().Lookup(k) == nil {
+			return nil, fmt.Errorf("unknown flag '%s'", k)
+		}
+		for _, v := range vs {
+			args = append(args, "--"+k+"="+v)
+		}
+	}
+	args = append(args, "--timeout="+s.cfg.requestTimeout.String())
+	return append(append(args, "--"), pos...), nil
+}
+
+// values returns the string representations of a JSON value.
+func values(v any) ([]string, error) {
+	switch v := v.(type) {
+	case nil:
+		return nil, nil
+	case string:
+		return []string{v}, nil
+	case bool:
+		return []string{fmt.Sprint(v)}, nil
+	case json.Number:
+		return []string{v.String()}, nil
+	case []any:
+		var vs []string
+		for _, e := range v {
+			s, err := values(e)
+			if err != nil {
+				return nil, err
+			} else if len(s) != 1 {
+				return nil, errors.New("nested arrays are not supported")
+			}
+			vs = append(vs, s...)
+		}
+		return vs, nil
+	default:
+		return nil, fmt.Errorf("unsupported type %T", v)
+	}
+}
+
+// run executes the command in-process and returns its output. Flags are reset
+// to their initial values afterward, so that they do not leak into the next
+// request.
+func (s *server) run(ctx context.Context, cmd *cobra.Command, args []string) (out []byte, err error) {
+	defer s.restore()
+	base := cli.BaseContext
+	cli.BaseContext = func() context.Context { return ctx }
+	defer func() { cli.BaseContext = base }()
+
+	log.Debug().Str("command", cmd.CommandPath()).Strs("args", args).Msg("Running command")
+	// evidence and recording belong to the serve command, not to requests
+	out, errOut := cli.Capture(func() {
+		cli.RunNested(func() {
+			defer errs.Recover(&err)
+			s.root.SetArgs(args)
+			if _, err = s.root.ExecuteC(); err != nil && !errors.As(err, new(*errs.Error)) {
+				err = errs.New(errs.Usage, err)
+			}
+		})
+	})
+	if len(errOut) > 0 {
+		log.Debug().Str("command", cmd.CommandPath()).Bytes("stderr", errOut).Msg("Command wrote to error output")
+	}
+	return out, err
+}
+
+// respond writes the output of a command. Output, which is not JSON, is
+// returned as JSON string. Violations are reported by the status code 200 and
+// the exit code in the header X-Heimdall-Exit-Code, like other errors.
+func (s *server) respond(w http.ResponseWriter, out []byte, err error) {
+	w.Header().Set("X-Heimdall-Exit-Code", fmt.Sprint(errs.ExitCode(err)))
+	if err != nil && !errors.Is(err, errs.ErrViolation) {
+		writeError(w, status(errs.KindOf(err)), err)
+		return
+	}
+
+	if b := bytes.TrimSpace(out); len(b) > 0 && json.Valid(b) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write(out)
+		return
+	}
+	writeJSON(w, http.StatusOK, string(out))
+}
+
+// status returns the HTTP status code for errors of the given kind.
+func status(k errs.Kind) int {
+	switch k {
+	case errs.Usage:
+		return http.StatusBadRequest
+	case errs.Input:
+		return http.StatusUnprocessableEntity
+	case errs.Auth:
+		return http.StatusForbidden
+	case errs.Remote:
+		return http.StatusBadGateway
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func writeError(w http.ResponseWriter, code int, err error) {
+	writeJSON(w, code, map[string]string{"error": err.Error(), "kind": errs.KindOf(err).String()})
+}
+
+func writeJSON(w http.ResponseWriter, code int, a any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(a)
+}
@@ -0,0 +1,175 @@
+// Copyright 2025 The Heimdall authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !no_serve
+
+package serve
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/abc-inc/heimdall/cli"
+	"github.com/abc-inc/heimdall/errs"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestRoot() *cobra.Command {
+	root := &cobra.Command{Use: "heimdall", SilenceErrors: true, SilenceUsage: true}
+	root.PersistentFlags().Duration("timeout", 0, "")
+	root.PersistentFlags().String("profile", "", "")
+
+	var names []string
+	greet := &cobra.Command{
+		Use: "greet",
+		Run: func(cmd *cobra.Command, args []string) {
+			cli.Fmtln(map[string]any{"names": names, "args": args})
+		},
+	}
+	greet.Flags().StringSliceVar(&names, "name", nil, "")
+
+	fail := &cobra.Command{
+		Use: "fail",
+		Run: func(cmd *cobra.Command, args []string) {
+			errs.Abortf(errs.Input, "invalid input")
+		},
+	}
+	write := &cobra.Command{Use: "write", Run: func(cmd *cobra.Command, args []string) {}}
+	write.Flags().String("dest", "", "")
+	root.AddCommand(&cobra.Command{Use: "group"})
+	root.Commands()[0].AddCommand(greet, fail, write)
+	return root
+}
+
+func newTestServer(t *testing.T) *server {
+	t.Helper()
+	defer func(p []string) { readOnly = p }(readOnly)
+	readOnly = []string{"group/fail", "group/greet"}
+	s := newServer(newTestRoot(), serveCfg{requestTimeout: time.Second})
+	s.token = "secret"
+	return s
+}
+
+func do(t *testing.T, s *server, method, path, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(method, "http://localhost:8080"+path, strings.NewReader(body))
+	r.Header.Set("Authorization", "Bearer secret")
+	r.Header.Set("Content-Type", "application/json")
+	s.ServeHTTP(w, r)
+	return w
+}
+
+func TestServe(t *testing.T) {
+	s := newTestServer(t)
+
+	w := do(t, s, http.MethodGet, "/v1", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	require.JSONEq(t, `[{"path":"/v1/group/fail","short":""},{"path":"/v1/group/greet","short":""}]`, w.Body.String())
+
+	w = do(t, s, http.MethodPost, "/v1/group/greet", `{"name":["a","b"],"args":["x"]}`)
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Equal(t, "0", w.Header().Get("X-Heimdall-Exit-Code"))
+	require.JSONEq(t, `{"names":["a","b"],"args":["x"]}`, w.Body.String())
+
+	// flags must not leak into subsequent requests
+	w = do(t, s, http.MethodPost, "/v1/group/greet", `{}`)
+	require.JSONEq(t, `{"names":null,"args":[]}`, w.Body.String())
+
+	w = do(t, s, http.MethodPost, "/v1/group/fail", "")
+	require.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	require.Equal(t, "3", w.Header().Get("X-Heimdall-Exit-Code"))
+	require.JSONEq(t, `{"error":"invalid input","kind":"input error"}`, w.Body.String())
+}
+
+func TestServe_BadRequest(t *testing.T) {
+	s := newTestServer(t)
+
+	require.Equal(t, http.StatusBadRequest, do(t, s, http.MethodPost, "/v1/group/greet", `{"profile":"prod"}`).Code)
+	require.Equal(t, http.StatusBadRequest, do(t, s, http.MethodPost, "/v1/group/greet", `{"name":{"a":1}}`).Code)
+	require.Equal(t, http.StatusBadRequest, do(t, s, http.MethodPost, "/v1/group/greet", `{"unknown":1}`).Code)
+	require.Equal(t, http.StatusNotFound, do(t, s, http.MethodPost, "/v1/group", "").Code)
+	require.Equal(t, http.StatusMethodNotAllowed, do(t, s, http.MethodGet, "/v1/group/greet", "").Code)
+	require.Equal(t, http.StatusNotFound, do(t, s, http.MethodPost, "/v1/group/write", "").Code)
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodPost, "http://127.0.0.1:8080/v1/group/greet", strings.NewReader("name=a"))
+	r.Header.Set("Authorization", "Bearer secret")
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	s.ServeHTTP(w, r)
+	require.Equal(t, http.StatusUnsupportedMediaType, w.Code)
+
+	w = httptest.NewRecorder()
+	r = httptest.NewRequest(http.MethodGet, "http://attacker.example:8080/v1", nil)
+	r.Header.Set("Authorization", "Bearer secret")
+	s.ServeHTTP(w, r)
+	require.Equal(t, http.StatusMisdirectedRequest, w.Code)
+
+	s.token = "other"
+	require.Equal(t, http.StatusUnauthorized, do(t, s, http.MethodGet, "/v1", "").Code)
+}
+
+func TestServe_Busy(t *testing.T) {
+	s := newTestServer(t)
+	s.sem <- struct{}{}
+	w := do(t, s, http.MethodPost, "/v1/group/greet", "")
+	require.Equal(t, http.StatusServiceUnavailable, w.Code)
+	require.Equal(t, "1", w.Header().Get("Retry-After"))
+}
+
+func TestServe_Nested(t *testing.T) {
+	s := newTestServer(t)
+	nested := false
+	s.root.PersistentPreRun = func(cmd *cobra.Command, args []string) { nested = cli.Nested() }
+
+	require.Equal(t, http.StatusOK, do(t, s, http.MethodPost, "/v1/group/greet", "").Code)
+	require.True(t, nested)
+	require.False(t, cli.Nested())
+}
+
+func TestNewServer_Token(t *testing.T) {
+	t.Setenv("HEIMDALL_SERVE_TOKEN", "")
+	s := newServer(newTestRoot(), serveCfg{requestTimeout: time.Second})
+	require.Len(t, s.token, 64)
+	require.Empty(t, s.methods)
+}
+
+func TestServe_WriteFlags(t *testing.T) {
+	defer func(p []string) { readOnly = p }(readOnly)
+	readOnly = []string{"group/write"}
+	s := newServer(newTestRoot(), serveCfg{requestTimeout: time.Second})
+	s.token = "secret"
+	require.Equal(t, http.StatusBadRequest, do(t, s, http.MethodPost, "/v1/group/write", `{"dest":"/tmp/x"}`).Code)
+	require.Equal(t, http.StatusBadRequest, do(t, s, http.MethodPost, "/v1/group/write", `{"dest=/tmp/x":"y"}`).Code)
+	require.Equal(t, http.StatusBadRequest, do(t, s, http.MethodPost, "/v1/group/write", `{"-dest":"/tmp/x"}`).Code)
+
+	readOnly = []string{"group/greet"}
+	s = newServer(newTestRoot(), serveCfg{requestTimeout: time.Second})
+	s.token = "secret"
+	require.Equal(t, http.StatusBadRequest, do(t, s, http.MethodPost, "/v1/group/greet", `{"timeout=1h":"y"}`).Code)
+	require.Equal(t, http.StatusBadRequest, do(t, s, http.MethodPost, "/v1/group/greet", `{"name=a":"b"}`).Code)
+}
+
+func TestIsLoopback(t *testing.T) {
+	for _, h := range []string{"localhost", "LOCALHOST:8080", "127.0.0.1", "127.0.0.2:80", "[::1]:8080", "::1"} {
+		require.True(t, isLoopback(h), h)
+	}
+	for _, h := range []string{"", "example.com", "localhost.example.com:8080", "10.0.0.1:8080", "[::]:80"} {
+		require.False(t, isLoopback(h), h)
+	}
+}
@@ -0,0 +1,219 @@
+// Copyright 2025 The Heimdall authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !no_terraform
+
+package terraform
+
+import (
+	"encoding/json"
+	"slices"
+	"strings"
+
+	"github.com/MakeNowJust/heredoc/v2"
+	"github.com/abc-inc/heimdall/cli"
+	"github.com/abc-inc/heimdall/errs"
+	"github.com/abc-inc/heimdall/res"
+	"github.com/spf13/cobra"
+)
+
+// Actions of resource changes. A replacement consists of "delete" and
+// "create" in any order.
+const (
+	ActionCreate  = "create"
+	ActionDelete  = "delete"
+	ActionNoOp    = "no-op"
+	ActionRead    = "read"
+	ActionReplace = "replace"
+	ActionUpdate  = "update"
+)
+
+var actions = []string{ActionCreate, ActionDelete, ActionNoOp, ActionRead, ActionReplace, ActionUpdate}
+
+// Change is a planned change of a resource.
+type Change struct {
+	Action   string         `json:"action" yaml:"action"`
+	Address  string         `json:"address" yaml:"address"`
+	Type     string         `json:"type" yaml:"type"`
+	Name     string         `json:"name" yaml:"name"`
+	Provider string         `json:"provider" yaml:"provider"`
+	Actions  []string       `json:"actions" yaml:"actions"`
+	Before   map[string]any `json:"before,omitempty" yaml:"before,omitempty"`
+	After    map[string]any `json:"after,omitempty" yaml:"after,omitempty"`
+}
+
+// Is reports whether the change performs the action, e.g., a replacement
+// deletes a resource.
+func (c Change) Is(action string) bool {
+	return c.Action == action || slices.Contains(c.Actions, action)
+}
+
+// plan is the subset of the JSON representation of a plan, which is needed to
+// list the resource changes.
+type plan struct {
+	FormatVersion   string `json:"format_version"`
+	ResourceChanges []struct {
+		Address      string `json:"address"`
+		Type         string `json:"type"`
+		Name         string `json:"name"`
+		ProviderName string `json:"provider_name"`
+		Change       struct {
+			Actions []string       `json:"actions"`
+			Before  map[string]any `json:"before"`
+			After   map[string]any `json:"after"`
+		} `json:"change"`
+	} `json:"resource_changes"`
+}
+
+type planCfg struct {
+	cli.OutCfg
+	actions []string
+	failOn  []string
+	all     bool
+	values  bool
+}
+
+func NewPlanCmd() *cobra.Command {
+	cfg := planCfg{}
+
+	cmd := &cobra.Command{
+		Use:   "plan [flags] [<file>]",
+		Short: "List the resource changes of a Terraform plan",
+		Long: heredoc.Doc(`
+			List the resource changes of a Terraform plan in JSON format, i.e., the output of 'terraform show -json <plan>'.
+			If no file is given, the plan is read from standard input.
+
+			The action of a change is one of: ` + strings.Join(actions, ", ") + `.
+			Filtering by "delete" or "create" includes replacements, because they delete and create a resource.
+			Unchanged resources ("no-op") are omitted, unless --all is set.
+			--fail-on checks all changes of the plan, regardless of --action and --all.
+		`),
+		Example: heredoc.Doc(`
+			terraform plan -out tfplan
+			terraform show -json tfplan > tfplan.json
+
+			# list all changes including the attributes before and after the change
+			heimdall terraform plan --values tfplan.json
+
+			# fail, if any resource would be destroyed
+			heimdall terraform plan --fail-on delete tfplan.json
+
+			# fail, if any S3 bucket would allow public access
+			heimdall terraform plan --values -o json tfplan.json > changes.json
+			heimdall eval -e 'none(c, #.type == "aws_s3_bucket_public_access_block" && #.after?.restrict_public_buckets == false)' changes.json:c
+		`),
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			for _, a := range append(slices.Clone(cfg.actions), cfg.failOn...) {
+				if !slices.Contains(actions, a) {
+					return errs.Newf(errs.Usage, "unknown action '%s', must be one of %s", a, strings.Join(actions, ", "))
+				}
+			}
+
+			name := "-"
+			if len(args) > 0 {
+				name = args[0]
+			}
+			cs, err := readPlan(name, cfg.values)
+			if err != nil {
+				return err
+			}
+			cli.Fmtln(filter(cs, cfg))
+
+			// --fail-on applies to all changes, not only to the listed ones
+			if n := count(cs, cfg.failOn); n > 0 {
+				return errs.Newf(errs.Violation, "%d resources would be changed by %s", n, strings.Join(cfg.failOn, " or "))
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringSliceVarP(&cfg.actions, "action", "a", nil, "Only list changes with the action. May be provided multiple times.")
+	cmd.Flags().BoolVar(&cfg.all, "all", cfg.all, `List unchanged resources ("no-op") as well`)
+	cmd.Flags().StringSliceVar(&cfg.failOn, "fail-on", nil, "Exit with status 1, if any change has the action. May be provided multiple times.")
+	cmd.Flags().BoolVar(&cfg.values, "values", cfg.values, "Include the attributes before and after the change")
+	cli.AddOutputFlags(cmd, &cfg.OutCfg)
+	cmd.DisableFlagsInUseLine = true
+	return cmd
+}
+
+// readPlan returns all resource changes, including unchanged resources.
+func readPlan(name string, values bool) ([]Change, error) {
+	r, err := res.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = r.Close() }()
+
+	var p plan
+	if err = json.NewDecoder(r).Decode(&p); err != nil {
+		return nil, errs.Newf(errs.Input, "cannot decode plan '%s': %w", name, err)
+	} else if p.FormatVersion == "" {
+		return nil, errs.Newf(errs.Input, "'%s' is not a plan in JSON format, see 'terraform show -json'", name)
+	}
+
+	cs := make([]Change, 0, len(p.ResourceChanges))
+	for _, rc := range p.ResourceChanges {
+		c := Change{
+			Action:   action(rc.Change.Actions),
+			Address:  rc.Address,
+			Type:     rc.Type,
+			Name:     rc.Name,
+			Provider: rc.ProviderName,
+			Actions:  rc.Change.Actions,
+		}
+		if values {
+			c.Before, c.After = rc.Change.Before, rc.Change.After
+		}
+		cs = append(cs, c)
+	}
+	return cs, nil
+}
+
+// filter returns the changes to list, i.e., those matching the actions.
+// Unchanged resources are omitted, unless requested.
+func filter(cs []Change, cfg planCfg) []Change {
+	fs := make([]Change, 0, len(cs))
+	for _, c := range cs {
+		if c.Action == ActionNoOp && !cfg.all && !slices.Contains(cfg.actions, ActionNoOp) {
+			continue
+		}
+		if len(cfg.actions) == 0 || slices.ContainsFunc(cfg.actions, c.Is) {
+			fs = append(fs, c)
+		}
+	}
+	return fs
+}
+
+// action summarizes the actions of a change.
+func action(as []string) string {
+	switch {
+	case len(as) == 2 && slices.Contains(as, ActionDelete) && slices.Contains(as, ActionCreate):
+		return ActionReplace
+	case len(as) == 1:
+		return as[0]
+	default:
+		return strings.Join(as, ",")
+	}
+}
+
+// count returns the number of changes, which perform any of the actions.
+func count(cs []Change, actions []string) (n int) {
+	for _, c := range cs {
+		if slices.ContainsFunc(actions, c.Is) {
+			n++
+		}
+	}
+	return n
+}
@@ -0,0 +1,74 @@
+// Copyright 2025 The Heimdall authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !no_terraform
+
+package terraform_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/abc-inc/heimdall/errs"
+	"github.com/abc-inc/heimdall/internal"
+	"github.com/abc-inc/heimdall/plugin/terraform"
+	"github.com/abc-inc/heimdall/test"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewPlanCmd(t *testing.T) {
+	f := filepath.Join(test.GetRootDir(), "testdata", "terraform", "tfplan.json")
+
+	got := test.Run(`[.[] | .action + " " + .address] | join(",")`, terraform.NewPlanCmd(), []string{f})
+	require.Equal(t, "create aws_s3_bucket.logs,update aws_s3_bucket_public_access_block.logs,"+
+		"replace aws_security_group.web,delete aws_instance.legacy", got)
+
+	cmd := terraform.NewPlanCmd()
+	internal.MustNoErr(cmd.Flags().Set("action", "delete"))
+	got = test.Run(`[.[].address] | join(",")`, cmd, []string{f})
+	require.Equal(t, "aws_security_group.web,aws_instance.legacy", got)
+
+	cmd = terraform.NewPlanCmd()
+	internal.MustNoErr(cmd.Flags().Set("all", "true"))
+	internal.MustNoErr(cmd.Flags().Set("values", "true"))
+	got = test.Run(`.[4].after.name`, cmd, []string{f})
+	require.Equal(t, "ci", got)
+}
+
+func TestNewPlanCmdErrors(t *testing.T) {
+	f := filepath.Join(test.GetRootDir(), "testdata", "terraform", "tfplan.json")
+
+	cmd := terraform.NewPlanCmd()
+	internal.MustNoErr(cmd.Flags().Set("fail-on", "delete"))
+	require.ErrorIs(t, cmd.RunE(cmd, []string{f}), errs.ErrViolation)
+
+	// --fail-on is not limited to the changes listed by --action
+	cmd = terraform.NewPlanCmd()
+	internal.MustNoErr(cmd.Flags().Set("action", "create"))
+	internal.MustNoErr(cmd.Flags().Set("fail-on", "update"))
+	require.ErrorIs(t, cmd.RunE(cmd, []string{f}), errs.ErrViolation)
+
+	cmd = terraform.NewPlanCmd()
+	internal.MustNoErr(cmd.Flags().Set("fail-on", "no-op"))
+	require.ErrorIs(t, cmd.RunE(cmd, []string{f}), errs.ErrViolation)
+
+	cmd = terraform.NewPlanCmd()
+	internal.MustNoErr(cmd.Flags().Set("fail-on", "delete"))
+
+	internal.MustNoErr(cmd.Flags().Set("fail-on", "destroy"))
+	require.ErrorIs(t, cmd.RunE(cmd, []string{f}), errs.ErrUsage)
+
+	cmd = terraform.NewPlanCmd()
+	require.ErrorIs(t, cmd.RunE(cmd, []string{filepath.Join(test.GetRootDir(), "testdata", "terraform", "main.tf")}), errs.ErrInput)
+}
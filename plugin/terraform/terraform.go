@@ -1,4 +1,4 @@
-// Copyright 2023 The Heimdall authors
+// Copyright 2025 The Heimdall authors
 //
 // Licensed under the Apache License, Version 2.0 (the "License");
 // you may not use this file except in compliance with the License.
@@ -12,19 +12,27 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
-//go:build !no_run
+//go:build !no_terraform
 
-package run
+package terraform
 
-import "github.com/spf13/cobra"
+import (
+	"github.com/abc-inc/heimdall/cli"
+	"github.com/spf13/cobra"
+)
 
-func NewRunCmd() *cobra.Command {
+func NewTerraformCmd() *cobra.Command {
 	cmd := &cobra.Command{
-		Use:                "run",
-		Short:              "Run a Heimdall task file",
-		Run:                func(cmd *cobra.Command, args []string) { runTask() },
-		FParseErrWhitelist: cobra.FParseErrWhitelist{UnknownFlags: true},
+		Use:     "terraform <subcommand>",
+		Aliases: []string{"tf"},
+		Short:   "Process Terraform-related files",
+		GroupID: cli.SoftwareGroup,
+		Args:    cobra.ExactArgs(0),
 	}
 
+	cmd.AddCommand(
+		NewPlanCmd(),
+	)
+
 	return cmd
 }
@@ -0,0 +1,169 @@
+// Copyright 2023 The Heimdall authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package res
+
+import (
+	"cmp"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"maps"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/mattn/go-zglob"
+	"github.com/rs/zerolog/log"
+)
+
+// Network holds the settings for connections to remote services. They apply to
+// the client used by Open and all clients created with Transport.
+type Network struct {
+	// CAFile is a PEM file with certificates, which are trusted in addition to
+	// the root certificates of the system.
+	CAFile string
+	// CertFile and KeyFile are PEM files with the client certificate and its
+	// private key for mutual TLS. If KeyFile is empty, CertFile must contain both.
+	CertFile, KeyFile string
+	// Proxies maps host patterns (see Match) to proxy URLs, whereas "direct"
+	// disables the proxy. The longest matching pattern wins. Hosts without any
+	// match use the proxy from the environment (HTTPS_PROXY, NO_PROXY, etc.).
+	Proxies map[string]string
+	// InsecureSkipVerify disables the verification of server certificates.
+	InsecureSkipVerify bool
+}
+
+// base is the transport for all connections to remote services.
+var base http.RoundTripper
+
+func init() {
+	if err := Configure(Network{}); err != nil {
+		panic(err)
+	}
+}
+
+// Configure applies the network settings to the client used by Open and all
+// clients created afterward.
+func Configure(n Network) error {
+	t, err := n.transport()
+	if err != nil {
+		return err
+	}
+	base = t
+
+	ft := t.Clone()
+	ft.RegisterProtocol("file", http.NewFileTransport(http.Dir("/")))
+	client = http.Client{Transport: Transport(ft)}
+	return nil
+}
+
+func (n Network) transport() (*http.Transport, error) {
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	t.TLSClientConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+
+	if n.InsecureSkipVerify {
+		log.Warn().Msg("Verification of server certificates is disabled")
+		t.TLSClientConfig.InsecureSkipVerify = true // #nosec G402 -- explicitly enabled by the user
+	}
+
+	if n.CAFile != "" {
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		b, err := os.ReadFile(n.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("cannot read CA file: %w", err)
+		}
+		if !pool.AppendCertsFromPEM(b) {
+			return nil, fmt.Errorf("no certificates found in CA file %s", n.CAFile)
+		}
+		t.TLSClientConfig.RootCAs = pool
+	}
+
+	if n.CertFile != "" {
+		c, err := tls.LoadX509KeyPair(n.CertFile, cmp.Or(n.KeyFile, n.CertFile))
+		if err != nil {
+			return nil, fmt.Errorf("cannot load client certificate: %w", err)
+		}
+		t.TLSClientConfig.Certificates = []tls.Certificate{c}
+	} else if n.KeyFile != "" {
+		return nil, errors.New("client key requires a client certificate")
+	}
+
+	proxy, err := n.proxy()
+	if err != nil {
+		return nil, err
+	}
+	t.Proxy = proxy
+	return t, nil
+}
+
+// proxyRule routes requests to hosts matching a pattern via a proxy.
+type proxyRule struct {
+	match func(host string) bool
+	url   *url.URL
+}
+
+// proxy returns a function, which selects the proxy for a request according to
+// the longest matching host pattern, or the environment if none matches.
+func (n Network) proxy() (func(*http.Request) (*url.URL, error), error) {
+	pats := slices.SortedFunc(maps.Keys(n.Proxies), func(a, b string) int {
+		return cmp.Or(len(b)-len(a), strings.Compare(a, b))
+	})
+
+	rules := make([]proxyRule, len(pats))
+	for i, p := range pats {
+		m, err := matcher(p)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proxy host pattern '%s': %w", p, err)
+		}
+		rules[i].match = m
+		if u := n.Proxies[p]; u != "" && u != "direct" {
+			if rules[i].url, err = url.Parse(u); err != nil {
+				return nil, fmt.Errorf("invalid proxy URL for '%s': %w", p, err)
+			}
+		}
+	}
+
+	return func(req *http.Request) (*url.URL, error) {
+		for _, r := range rules {
+			if r.match(req.URL.Hostname()) {
+				return r.url, nil
+			}
+		}
+		return http.ProxyFromEnvironment(req)
+	}, nil
+}
+
+// matcher compiles a pattern like Match, but returns an error if it is invalid.
+func matcher(pat string) (func(string) bool, error) {
+	if strings.HasPrefix(pat, "^") {
+		re, err := regexp.Compile(pat)
+		if err != nil {
+			return nil, err
+		}
+		return re.MatchString, nil
+	}
+	g, err := zglob.New(pat)
+	if err != nil {
+		return nil, err
+	}
+	return g.Match, nil
+}
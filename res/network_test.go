@@ -0,0 +1,90 @@
+// Copyright 2023 The Heimdall authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package res
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/abc-inc/heimdall/internal"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigure(t *testing.T) {
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, r.TLS.PeerCertificates[0].Subject.Organization[0])
+	}))
+	srv.TLS = &tls.Config{ClientAuth: tls.RequireAnyClientCert}
+	srv.StartTLS()
+	defer srv.Close()
+	defer func() { internal.MustNoErr(Configure(Network{})) }()
+
+	// The certificate of the test server is used as client certificate, too.
+	dir := t.TempDir()
+	ca := filepath.Join(dir, "ca.pem")
+	crt := srv.TLS.Certificates[0]
+	writePEM(t, ca, "CERTIFICATE", crt.Certificate[0])
+	key := filepath.Join(dir, "key.pem")
+	writePEM(t, key, "PRIVATE KEY", internal.Must(x509.MarshalPKCS8PrivateKey(crt.PrivateKey)))
+
+	_, err := Open(srv.URL)
+	require.ErrorContains(t, err, "certificate")
+
+	require.NoError(t, Configure(Network{CAFile: ca, CertFile: ca, KeyFile: key}))
+	r := internal.Must(Open(srv.URL))
+	defer func() { _ = r.Close() }()
+	require.Equal(t, "Acme Co", string(internal.Must(io.ReadAll(r))))
+
+	require.ErrorContains(t, Configure(Network{CAFile: key}), "no certificates found")
+	require.ErrorContains(t, Configure(Network{KeyFile: key}), "requires a client certificate")
+}
+
+func TestNetworkProxy(t *testing.T) {
+	n := Network{Proxies: map[string]string{
+		"*":                    "http://proxy:3128",
+		"*.corp.example.com":   "http://corp-proxy:8080",
+		"git.corp.example.com": "direct",
+	}}
+	proxy := internal.Must(n.proxy())
+
+	for host, want := range map[string]string{
+		"github.com":            "http://proxy:3128",
+		"jira.corp.example.com": "http://corp-proxy:8080",
+		"git.corp.example.com":  "",
+	} {
+		u := internal.Must(proxy(internal.Must(http.NewRequest(http.MethodGet, "https://"+host+"/x", nil))))
+		if want == "" {
+			require.Nil(t, u, host)
+		} else {
+			require.Equal(t, want, u.String(), host)
+		}
+	}
+
+	_, err := Network{Proxies: map[string]string{"^[": "direct"}}.proxy()
+	require.Error(t, err)
+}
+
+func writePEM(t *testing.T, name, typ string, b []byte) {
+	f := internal.Must(os.Create(name))
+	defer func() { _ = f.Close() }()
+	require.NoError(t, pem.Encode(f, &pem.Block{Type: typ, Bytes: b}))
+}
@@ -0,0 +1,314 @@
+// Copyright 2025 The Heimdall authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package res
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/rs/zerolog/log"
+)
+
+// Exchange is a recorded request and the corresponding response.
+type Exchange struct {
+	Time     time.Time `json:"time"`
+	Request  Message   `json:"request"`
+	Response Message   `json:"response"`
+}
+
+// Message is a recorded request or response without credentials.
+type Message struct {
+	Method string      `json:"method,omitempty"`
+	URL    string      `json:"url,omitempty"`
+	Status int         `json:"status,omitempty"`
+	Header http.Header `json:"header,omitempty"`
+	Body   string      `json:"body,omitempty"`
+	Base64 bool        `json:"base64,omitempty"`
+}
+
+const redacted = "REDACTED"
+
+// ErrNotRecorded is returned in replay mode for requests, which have not been recorded.
+var ErrNotRecorded = errors.New("no recorded response")
+
+var (
+	secretHeader = regexp.MustCompile(`(?i)auth|cookie|token|secret|api-?key|session|signature`)
+	secretField  = regexp.MustCompile(`(?i)^(token|access_token|refresh_token|id_token|password|secret|client_secret)$`)
+	nonWord      = regexp.MustCompile(`[^A-Za-z0-9.]+`)
+)
+
+var recorder struct {
+	mu      sync.Mutex
+	dir     string
+	replay  bool
+	seq     int
+	counts  map[string]int
+	records map[string][]*Exchange
+}
+
+// Record saves the exchanges of all service clients in dir.
+func Record(dir string) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	recorder.mu.Lock()
+	defer recorder.mu.Unlock()
+	recorder.dir, recorder.replay, recorder.seq = dir, false, 0
+	recorder.counts = map[string]int{}
+	return nil
+}
+
+// Replay serves the exchanges recorded in dir instead of sending requests.
+// Identical requests are answered in the order they were recorded.
+func Replay(dir string) error {
+	fs, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return err
+	} else if len(fs) == 0 {
+		return fmt.Errorf("no recorded exchanges in '%s': %w", dir, os.ErrNotExist)
+	}
+
+	records := map[string][]*Exchange{}
+	slices.Sort(fs)
+	for _, f := range fs {
+		b, err := os.ReadFile(f)
+		if err != nil {
+			return err
+		}
+		e := &Exchange{}
+		if err = json.Unmarshal(b, e); err != nil {
+			return fmt.Errorf("cannot read recorded exchange '%s': %w", f, err)
+		}
+		body, _ := e.Request.body()
+		k := exchangeKey(e.Request.Method, e.Request.URL, body)
+		records[k] = append(records[k], e)
+	}
+
+	recorder.mu.Lock()
+	defer recorder.mu.Unlock()
+	recorder.dir, recorder.replay, recorder.records = dir, true, records
+	recorder.counts = map[string]int{}
+	return nil
+}
+
+// Transport returns the RoundTripper for service clients, which retries failed
+// requests and records or replays them, if enabled.
+func Transport(rt http.RoundTripper) http.RoundTripper {
+	return WithRetry(WithRecorder(rt))
+}
+
+// WithRecorder returns a RoundTripper, which records or replays exchanges as
+// configured by Record or Replay. Otherwise, it passes requests on to rt.
+func WithRecorder(rt http.RoundTripper) http.RoundTripper {
+	if rt == nil {
+		rt = base
+	}
+	return recTransport{rt: rt}
+}
+
+type recTransport struct {
+	rt http.RoundTripper
+}
+
+func (t recTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	recorder.mu.Lock()
+	dir, replay := recorder.dir, recorder.replay
+	recorder.mu.Unlock()
+	if dir == "" || !IsURL(req.URL.String()) {
+		return t.rt.RoundTrip(req)
+	}
+
+	var reqBody []byte
+	if req.Body != nil && req.Body != http.NoBody {
+		b, err := io.ReadAll(req.Body)
+		_ = req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		req = req.Clone(req.Context())
+		req.Body, reqBody = io.NopCloser(bytes.NewReader(b)), b
+	}
+
+	e := &Exchange{Time: time.Now().UTC(), Request: newMessage(req.Header, reqBody)}
+	e.Request.Method, e.Request.URL = req.Method, redactURL(req.URL)
+	body, _ := e.Request.body()
+	k := exchangeKey(e.Request.Method, e.Request.URL, body)
+	if replay {
+		return replayExchange(req, k)
+	}
+
+	resp, err := t.rt.RoundTrip(req)
+	if err != nil {
+		return resp, err
+	}
+	b, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(b))
+	if err != nil {
+		return resp, err
+	}
+
+	e.Response = newMessage(resp.Header, b)
+	e.Response.Status = resp.StatusCode
+	return resp, saveExchange(dir, k, e)
+}
+
+func replayExchange(req *http.Request, key string) (*http.Response, error) {
+	recorder.mu.Lock()
+	es, n := recorder.records[key], recorder.counts[key]
+	recorder.counts[key]++
+	recorder.mu.Unlock()
+	if len(es) == 0 {
+		return nil, fmt.Errorf("%w for %s %s", ErrNotRecorded, req.Method, redactURL(req.URL))
+	}
+
+	e := es[min(n, len(es)-1)]
+	b, err := e.Response.body()
+	if err != nil {
+		return nil, err
+	}
+	h := e.Response.Header.Clone()
+	if h == nil {
+		h = http.Header{}
+	}
+	h.Del("Content-Length")
+	log.Debug().Str("method", req.Method).Str("url", e.Request.URL).Int("status", e.Response.Status).Msg("Replaying response")
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", e.Response.Status, http.StatusText(e.Response.Status)),
+		StatusCode:    e.Response.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        h,
+		Body:          io.NopCloser(bytes.NewReader(b)),
+		ContentLength: int64(len(b)),
+		Request:       req,
+	}, nil
+}
+
+func saveExchange(dir, key string, e *Exchange) error {
+	recorder.mu.Lock()
+	recorder.seq++
+	seq := recorder.seq
+	recorder.mu.Unlock()
+
+	u, _ := url.Parse(e.Request.URL)
+	slug := strings.Trim(nonWord.ReplaceAllString(u.Host+u.Path, "-"), "-")
+	name := fmt.Sprintf("%05d-%s-%s.json", seq, e.Request.Method, slug[:min(len(slug), 80)])
+
+	b, err := json.MarshalIndent(e, "", "  ")
+	if err != nil {
+		return err
+	}
+	log.Debug().Str("key", key).Str("file", name).Msg("Recording exchange")
+	return os.WriteFile(filepath.Join(dir, name), b, 0o600)
+}
+
+// newMessage creates a message without credentials in headers and bodies.
+func newMessage(h http.Header, body []byte) Message {
+	m := Message{Header: h.Clone()}
+	for n := range m.Header {
+		if secretHeader.MatchString(n) {
+			m.Header[n] = []string{redacted}
+		}
+	}
+
+	body = redactBody(body)
+	if utf8.Valid(body) {
+		m.Body = string(body)
+	} else {
+		m.Body, m.Base64 = base64.StdEncoding.EncodeToString(body), true
+	}
+	return m
+}
+
+func (m Message) body() ([]byte, error) {
+	if m.Base64 {
+		return base64.StdEncoding.DecodeString(m.Body)
+	}
+	return []byte(m.Body), nil
+}
+
+// exchangeKey identifies a request by method, URL and body.
+func exchangeKey(method, url string, body []byte) string {
+	k := method + " " + url
+	if len(body) > 0 {
+		s := sha256.Sum256(body)
+		k += " " + hex.EncodeToString(s[:])
+	}
+	return k
+}
+
+func redactURL(u *url.URL) string {
+	r := *u
+	q := r.Query()
+	for n := range q {
+		if secretHeader.MatchString(n) {
+			q.Set(n, redacted)
+		}
+	}
+	r.RawQuery = q.Encode()
+	return r.Redacted()
+}
+
+// redactBody replaces credentials in JSON documents, e.g., access tokens.
+func redactBody(b []byte) []byte {
+	var doc any
+	if len(b) == 0 || (b[0] != '{' && b[0] != '[') || json.Unmarshal(b, &doc) != nil || !redactJSON(doc) {
+		return b
+	}
+	r, err := json.Marshal(doc)
+	if err != nil {
+		return b
+	}
+	return r
+}
+
+func redactJSON(doc any) (changed bool) {
+	switch v := doc.(type) {
+	case map[string]any:
+		for k, e := range v {
+			if _, ok := e.(string); ok && secretField.MatchString(k) {
+				v[k], changed = redacted, true
+			} else if redactJSON(e) {
+				changed = true
+			}
+		}
+	case []any:
+		for _, e := range v {
+			if redactJSON(e) {
+				changed = true
+			}
+		}
+	}
+	return changed
+}
@@ -0,0 +1,76 @@
+// Copyright 2025 The Heimdall authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package res
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/abc-inc/heimdall/internal"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecordReplay(t *testing.T) {
+	defer func() { recorder.dir, recorder.replay = "", false }()
+
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Set-Cookie", "session=s3cr3t")
+		if r.Method == http.MethodPost {
+			_, _ = io.WriteString(w, `{"token":"ghs_s3cr3t","expires_at":"2025-01-01T00:00:00Z"}`)
+			return
+		}
+		_, _ = io.WriteString(w, r.URL.Query().Get("page"))
+	}))
+
+	dir := t.TempDir()
+	internal.MustNoErr(Record(dir))
+	c := http.Client{Transport: Transport(nil)}
+	get := func(url string) string {
+		req := internal.Must(http.NewRequest(http.MethodGet, url, nil))
+		req.Header.Set("Authorization", "Bearer s3cr3t")
+		resp := internal.Must(c.Do(req))
+		defer func() { _ = resp.Body.Close() }()
+		return string(internal.Must(io.ReadAll(resp.Body)))
+	}
+	require.Equal(t, "1", get(srv.URL+"?page=1"))
+	require.Equal(t, "2", get(srv.URL+"?page=2&access_token=s3cr3t"))
+	resp := internal.Must(c.Post(srv.URL, "application/json", strings.NewReader("{}")))
+	_ = resp.Body.Close()
+	srv.Close()
+
+	fs := internal.Must(filepath.Glob(filepath.Join(dir, "*.json")))
+	require.Len(t, fs, 3)
+	for _, f := range fs {
+		require.NotContains(t, string(internal.Must(os.ReadFile(f))), "s3cr3t")
+	}
+
+	internal.MustNoErr(Replay(dir))
+	require.Equal(t, "2", get(srv.URL+"?page=2&access_token=other"))
+	require.Equal(t, "1", get(srv.URL+"?page=1"))
+	resp = internal.Must(c.Post(srv.URL, "application/json", strings.NewReader("{}")))
+	require.Equal(t, `{"expires_at":"2025-01-01T00:00:00Z","token":"REDACTED"}`, string(internal.Must(io.ReadAll(resp.Body))))
+	_ = resp.Body.Close()
+	require.Equal(t, 3, calls)
+
+	_, err := c.Get(srv.URL + "?page=3")
+	require.ErrorContains(t, err, "no recorded response for GET")
+}
@@ -15,6 +15,7 @@
 package res
 
 import (
+	"context"
 	"io"
 	"net/http"
 	"os"
@@ -28,16 +29,30 @@ import (
 
 var client http.Client
 
-func init() {
-	t := &http.Transport{}
-	t.RegisterProtocol("file", http.NewFileTransport(http.Dir("/")))
-	client = http.Client{Transport: t}
-}
+// Context returns the context for fetching resources. It is replaced by the
+// CLI, so that requests are canceled on timeout or interrupt.
+var Context = context.Background
+
+// OpenHook is called for every resource opened by Open.
+// It can be used to wrap the reader, e.g., to record the content.
+var OpenHook func(uri string, r io.ReadCloser) io.ReadCloser
 
 func Open(uri string) (io.ReadCloser, error) {
 	uri = os.ExpandEnv(uri)
+	r, err := open(uri)
+	if err != nil || OpenHook == nil {
+		return r, err
+	}
+	return OpenHook(uri, r), nil
+}
+
+func open(uri string) (io.ReadCloser, error) {
 	if IsURL(uri) {
-		resp, err := client.Get(uri)
+		req, err := http.NewRequestWithContext(Context(), http.MethodGet, uri, nil)
+		if err != nil {
+			return nil, err
+		}
+		resp, err := client.Do(req)
 		if err != nil {
 			return nil, err
 		}
@@ -48,6 +63,27 @@ func Open(uri string) (io.ReadCloser, error) {
 	return os.Open(uri)
 }
 
+// WithContext returns a RoundTripper, which sends requests without a context
+// (e.g., created by http.NewRequest) with the context returned by Context.
+// It is intended for clients, which do not support contexts.
+func WithContext(rt http.RoundTripper) http.RoundTripper {
+	if rt == nil {
+		rt = base
+	}
+	return ctxTransport{rt: rt}
+}
+
+type ctxTransport struct {
+	rt http.RoundTripper
+}
+
+func (t ctxTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Context() == context.Background() {
+		req = req.WithContext(Context())
+	}
+	return t.rt.RoundTrip(req)
+}
+
 func IsURL(uri string) bool {
 	return strings.HasPrefix(uri, "https://") || strings.HasPrefix(uri, "http://")
 }
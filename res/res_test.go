@@ -0,0 +1,61 @@
+// Copyright 2025 The Heimdall authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package res
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/abc-inc/heimdall/internal"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOpenContext(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(5 * time.Second):
+		}
+	}))
+	defer srv.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	Context = func() context.Context { return ctx }
+	defer func() { Context = context.Background }()
+
+	_, err := Open(srv.URL)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	c := http.Client{Transport: WithContext(nil)}
+	_, err = c.Get(srv.URL)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestWithContext(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, "ok")
+	}))
+	defer srv.Close()
+
+	c := http.Client{Transport: WithContext(nil)}
+	resp := internal.Must(c.Get(srv.URL))
+	defer func() { _ = resp.Body.Close() }()
+	require.Equal(t, "ok", string(internal.Must(io.ReadAll(resp.Body))))
+}
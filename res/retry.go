@@ -0,0 +1,142 @@
+// Copyright 2025 The Heimdall authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package res
+
+import (
+	"crypto/tls"
+	"errors"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// Retry policy of RoundTrippers created by WithRetry.
+var (
+	// MaxRetries is the maximum number of retries per request.
+	MaxRetries = 4
+	// MinBackoff is the delay before the first retry, which doubles with every retry.
+	MinBackoff = time.Second
+	// MaxBackoff caps the exponential backoff.
+	MaxBackoff = 30 * time.Second
+	// MaxWait is the maximum time to wait for a rate limit to be reset.
+	// Requests are not retried, if the server asks to wait longer.
+	MaxWait = 5 * time.Minute
+)
+
+var idempotentMethods = []string{
+	http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace, http.MethodPut, http.MethodDelete,
+}
+
+// WithRetry returns a RoundTripper, which retries idempotent requests on
+// network errors and on status 429, 502, 503 and 504 with exponential backoff.
+// If a (primary or secondary) rate limit is exceeded, it waits as long as
+// requested by the Retry-After or X-RateLimit-Reset header.
+func WithRetry(rt http.RoundTripper) http.RoundTripper {
+	if rt == nil {
+		rt = base
+	}
+	return retryTransport{rt: rt}
+}
+
+type retryTransport struct {
+	rt http.RoundTripper
+}
+
+func (t retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !slices.Contains(idempotentMethods, req.Method) || req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return t.rt.RoundTrip(req)
+	}
+
+	for attempt := 1; ; attempt++ {
+		resp, err := t.rt.RoundTrip(req)
+		d, ok := retryDelay(resp, err, attempt)
+		if !ok || attempt > MaxRetries || req.Context().Err() != nil {
+			return resp, err
+		}
+
+		ev := log.Debug().Str("method", req.Method).Str("url", req.URL.Redacted()).Int("attempt", attempt).Dur("delay", d)
+		if resp != nil {
+			ev.Int("status", resp.StatusCode)
+			_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<16))
+			_ = resp.Body.Close()
+		}
+		ev.AnErr("error", err).Msg("Retrying request")
+
+		timer := time.NewTimer(d)
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		case <-timer.C:
+		}
+
+		req = req.Clone(req.Context())
+		if req.GetBody != nil {
+			if req.Body, err = req.GetBody(); err != nil {
+				return nil, err
+			}
+		}
+	}
+}
+
+// retryDelay returns how long to wait before retrying, and whether to retry at all.
+func retryDelay(resp *http.Response, err error, attempt int) (time.Duration, bool) {
+	backoff := min(MinBackoff<<(attempt-1), MaxBackoff)
+	backoff = backoff/2 + rand.N(backoff/2+1)
+	if errors.Is(err, ErrNotRecorded) || errors.As(err, new(*tls.CertificateVerificationError)) {
+		// Neither missing recordings nor untrusted certificates resolve over time.
+		return 0, false
+	} else if err != nil {
+		return backoff, true
+	}
+
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusForbidden:
+		d, ok := rateLimitDelay(resp.Header)
+		if !ok && resp.StatusCode == http.StatusTooManyRequests {
+			return backoff, true
+		}
+		return d, ok && d <= MaxWait
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		if d, ok := rateLimitDelay(resp.Header); ok {
+			return d, d <= MaxWait
+		}
+		return backoff, true
+	}
+	return 0, false
+}
+
+// rateLimitDelay evaluates the Retry-After header (e.g., secondary rate limits
+// of GitHub) and the X-RateLimit-Reset header (primary rate limits).
+func rateLimitDelay(h http.Header) (time.Duration, bool) {
+	if s := h.Get("Retry-After"); s != "" {
+		if secs, err := strconv.Atoi(s); err == nil {
+			return time.Duration(secs) * time.Second, true
+		} else if t, err := http.ParseTime(s); err == nil {
+			return max(time.Until(t), 0), true
+		}
+	}
+	if h.Get("X-RateLimit-Remaining") == "0" {
+		if secs, err := strconv.ParseInt(h.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+			return max(time.Until(time.Unix(secs, 0)), 0) + time.Second, true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,95 @@
+// Copyright 2025 The Heimdall authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package res
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/abc-inc/heimdall/internal"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithRetry(t *testing.T) {
+	defer func(d time.Duration) { MinBackoff = d }(MinBackoff)
+	MinBackoff = time.Millisecond
+
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		switch calls {
+		case 1:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		case 2:
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+		case 3:
+			w.Header().Set("X-RateLimit-Remaining", "0")
+			w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(time.Now().Unix()-1, 10))
+			w.WriteHeader(http.StatusForbidden)
+		default:
+			b, _ := io.ReadAll(r.Body)
+			_, _ = w.Write(b)
+		}
+	}))
+	defer srv.Close()
+
+	c := http.Client{Transport: WithRetry(nil)}
+	req := internal.Must(http.NewRequest(http.MethodPut, srv.URL, strings.NewReader("body")))
+	resp := internal.Must(c.Do(req))
+	defer func() { _ = resp.Body.Close() }()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, "body", string(internal.Must(io.ReadAll(resp.Body))))
+	require.Equal(t, 4, calls)
+}
+
+func TestWithRetryNoRetry(t *testing.T) {
+	defer func(n int) { MaxRetries = n }(MaxRetries)
+	MaxRetries = 1
+
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if r.Method == http.MethodGet {
+			w.Header().Set("Retry-After", "0")
+		} else {
+			w.Header().Set("Retry-After", strconv.Itoa(int(MaxWait.Seconds())+1))
+		}
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer srv.Close()
+
+	c := http.Client{Transport: WithRetry(nil)}
+	resp := internal.Must(c.Get(srv.URL))
+	_ = resp.Body.Close()
+	require.Equal(t, 2, calls, "retries are limited")
+
+	calls = 0
+	resp = internal.Must(c.Post(srv.URL, "text/plain", strings.NewReader("body")))
+	_ = resp.Body.Close()
+	require.Equal(t, 1, calls, "POST is not idempotent")
+
+	calls = 0
+	req := internal.Must(http.NewRequest(http.MethodDelete, srv.URL, nil))
+	resp = internal.Must(c.Do(req))
+	_ = resp.Body.Close()
+	require.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+	require.Equal(t, 1, calls, "waiting longer than MaxWait")
+}
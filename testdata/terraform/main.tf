@@ -0,0 +1,36 @@
+terraform {
+  required_version = ">= 1.5"
+}
+
+variable "env" {
+  type    = string
+  default = "prod"
+}
+
+locals {
+  name = "logs-${var.env}"
+}
+
+resource "aws_s3_bucket" "logs" {
+  bucket = local.name
+  tags = {
+    Env  = var.env
+    Team = "platform"
+  }
+}
+
+resource "aws_s3_bucket_public_access_block" "logs" {
+  bucket                  = aws_s3_bucket.logs.id
+  block_public_acls       = true
+  restrict_public_buckets = false
+}
+
+resource "aws_security_group" "web" {
+  ingress {
+    from_port   = 443
+    cidr_blocks = ["0.0.0.0/0"]
+  }
+  ingress {
+    from_port = 80
+  }
+}